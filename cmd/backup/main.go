@@ -3,20 +3,17 @@ package main
 import (
 	"context"
 	"log"
-	"strings"
+	"log/slog"
 	"time"
 
 	"github.com/vlatan/video-store/internal/backup"
 	"github.com/vlatan/video-store/internal/config"
 	"github.com/vlatan/video-store/internal/integrations/r2"
-	"github.com/vlatan/video-store/internal/utils"
+	"github.com/vlatan/video-store/internal/logging"
 )
 
 func main() {
 
-	// Print separator at the end
-	defer utils.LogPlainln(strings.Repeat("-", 70))
-
 	// Give the backup a reasonable time to finish
 	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
 	defer cancel()
@@ -26,6 +23,9 @@ func main() {
 		log.Fatal(err)
 	}
 
+	// Log JSON lines from now on
+	logging.Setup(cfg.Debug)
+
 	r2Service, err := r2.New(ctx, cfg)
 	if err != nil {
 		log.Fatal(err)
@@ -33,6 +33,6 @@ func main() {
 
 	backupService := backup.New(cfg, r2Service)
 	if err := backupService.Run(ctx); err != nil {
-		log.Println(err)
+		slog.ErrorContext(ctx, "backup failed", "error", err)
 	}
 }
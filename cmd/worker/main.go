@@ -4,19 +4,15 @@ import (
 	"context"
 	"log"
 	"os/signal"
-	"strings"
 	"syscall"
 
 	"github.com/vlatan/video-store/internal/config"
-	"github.com/vlatan/video-store/internal/utils"
+	"github.com/vlatan/video-store/internal/logging"
 	"github.com/vlatan/video-store/internal/worker"
 )
 
 func main() {
 
-	// Print separator at the end
-	defer utils.LogPlainln(strings.Repeat("-", 70))
-
 	// Listen for OS interruption and termination signals
 	sigCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
@@ -26,6 +22,9 @@ func main() {
 		log.Fatal(err)
 	}
 
+	// Log JSON lines from now on
+	logging.Setup(cfg.Debug)
+
 	// Give the worker a reasonable time to finish
 	ctx, cancel := context.WithTimeout(sigCtx, cfg.WorkerExpectedRuntime)
 	defer cancel()
@@ -3,6 +3,7 @@ package app
 import (
 	"context"
 	"encoding/gob"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
@@ -10,7 +11,9 @@ import (
 	"github.com/vlatan/video-store/internal/config"
 	"github.com/vlatan/video-store/internal/drivers/database"
 	"github.com/vlatan/video-store/internal/drivers/rdb"
+	"github.com/vlatan/video-store/internal/handlers/api"
 	"github.com/vlatan/video-store/internal/handlers/auth"
+	"github.com/vlatan/video-store/internal/handlers/feeds"
 	"github.com/vlatan/video-store/internal/handlers/misc"
 	"github.com/vlatan/video-store/internal/handlers/pages"
 	"github.com/vlatan/video-store/internal/handlers/posts"
@@ -18,29 +21,39 @@ import (
 	"github.com/vlatan/video-store/internal/handlers/sources"
 	"github.com/vlatan/video-store/internal/handlers/users"
 	"github.com/vlatan/video-store/internal/integrations/gemini"
+	"github.com/vlatan/video-store/internal/integrations/indexnow"
 	"github.com/vlatan/video-store/internal/integrations/r2"
 	"github.com/vlatan/video-store/internal/integrations/yt"
+	"github.com/vlatan/video-store/internal/logging"
+	"github.com/vlatan/video-store/internal/metrics"
 	"github.com/vlatan/video-store/internal/middlewares"
 	"github.com/vlatan/video-store/internal/models"
 	catsRepo "github.com/vlatan/video-store/internal/repositories/categories"
+	indexNowRepo "github.com/vlatan/video-store/internal/repositories/indexnow"
 	pagesRepo "github.com/vlatan/video-store/internal/repositories/pages"
 	postsRepo "github.com/vlatan/video-store/internal/repositories/posts"
+	searchesRepo "github.com/vlatan/video-store/internal/repositories/searches"
 	sourcesRepo "github.com/vlatan/video-store/internal/repositories/sources"
 	usersRepo "github.com/vlatan/video-store/internal/repositories/users"
 	redisStore "github.com/vlatan/video-store/internal/store"
+	"github.com/vlatan/video-store/internal/tracing"
 	"github.com/vlatan/video-store/internal/ui"
 )
 
 type App struct {
+	api      *api.Service
 	auth     *auth.Service
 	users    *users.Service
 	posts    *posts.Service
 	pages    *pages.Service
 	sources  *sources.Service
 	sitemaps *sitemaps.Service
+	feeds    *feeds.Service
 	mw       *middlewares.Service
+	indexNow *indexnow.Service
 	misc     *misc.Service
 	domain   string
+	locales  []string
 	cleanup  func() error
 	server   *http.Server
 }
@@ -59,6 +72,15 @@ func New() (*App, error) {
 		return nil, fmt.Errorf("couldn't create config: %w", err)
 	}
 
+	// Log JSON lines with the request attributes
+	logging.Setup(cfg.Debug)
+
+	// Set up tracing, a no-op without an exporter endpoint
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't set up tracing: %w", err)
+	}
+
 	// Create database service
 	db, err := database.New(cfg)
 	if err != nil {
@@ -97,6 +119,19 @@ func New() (*App, error) {
 		return nil, fmt.Errorf("couldn't create sources repo: %w", err)
 	}
 
+	searchesRepo, err := searchesRepo.New(db, nil)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't create searches repo: %w", err)
+	}
+
+	indexNowRepo, err := indexNowRepo.New(db, nil)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't create IndexNow repo: %w", err)
+	}
+
+	// Create IndexNow service queueing the changed URLs
+	indexNow := indexnow.New(cfg, indexNowRepo)
+
 	// Create YouTube service
 	ctx := context.Background()
 	yt, err := yt.New(ctx, cfg)
@@ -116,26 +151,55 @@ func New() (*App, error) {
 		return nil, fmt.Errorf("couldn't create R2 service: %w", err)
 	}
 
+	// Report the pools, the Gemini quota and the worker runs at scrape time
+	err = metrics.Register(
+		metrics.NewDBPoolCollector(db.Pool),
+		metrics.NewRedisPoolCollector(rdb.Client),
+		metrics.NewGeminiCollector(gemini.Usage, cfg.GeminiRPD, cfg.GeminiRPM),
+		metrics.NewWorkerCollector(rdb.Client),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't register the metrics: %w", err)
+	}
+
 	// Create user interface service
-	ui, err := ui.New(usersRepo, catsRepo, rdb, r2s, store, cfg)
+	ui, err := ui.New(usersRepo, catsRepo, searchesRepo, rdb, r2s, store, cfg)
 	if err != nil {
 		return nil, fmt.Errorf("couldn't create UI service: %w", err)
 	}
 
+	// Create public API service
+	api, err := api.New(postsRepo, catsRepo, sourcesRepo, pagesRepo, rdb, ui, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't create API service: %w", err)
+	}
+
 	// Create new app service
 	a := &App{
+		api:      api,
 		auth:     auth.New(usersRepo, store, rdb, r2s, ui, cfg),
-		users:    users.New(usersRepo, postsRepo, rdb, r2s, ui, cfg),
-		posts:    posts.New(postsRepo, usersRepo, rdb, ui, cfg, yt, gemini),
-		pages:    pages.New(pagesRepo, rdb, ui, cfg),
+		users:    users.New(usersRepo, postsRepo, searchesRepo, rdb, r2s, ui, cfg),
+		posts:    posts.New(postsRepo, usersRepo, catsRepo, searchesRepo, rdb, ui, cfg, yt, gemini, indexNow),
+		pages:    pages.New(pagesRepo, rdb, ui, cfg, indexNow),
 		sources:  sources.New(postsRepo, sourcesRepo, rdb, ui, cfg, yt),
 		sitemaps: sitemaps.New(postsRepo, rdb, ui, cfg),
+		feeds:    feeds.New(postsRepo, rdb, ui, cfg),
 		misc:     misc.New(cfg, db, rdb, ui),
-		mw:       middlewares.New(ui, cfg),
+		mw:       middlewares.New(ui, rdb, cfg),
+		indexNow: indexNow,
 		domain:   cfg.Domain,
+		locales:  cfg.Locales,
 		cleanup: func() error {
 			db.Pool.Close()
-			return rdb.Client.Close()
+
+			// Flush the pending spans, but don't hang on an unreachable collector
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			return errors.Join(
+				rdb.Client.Close(),
+				shutdownTracing(ctx),
+			)
 		},
 		server: &http.Server{
 			Addr:         fmt.Sprintf(":%d", cfg.Port),
@@ -6,6 +6,9 @@ import (
 	"runtime"
 	"runtime/pprof"
 
+	"github.com/vlatan/video-store/internal/handlers/feeds"
+	"github.com/vlatan/video-store/internal/metrics"
+	"github.com/vlatan/video-store/internal/middlewares"
 	"github.com/vlatan/video-store/internal/utils"
 )
 
@@ -21,13 +24,25 @@ func (a *App) RegisterRoutes() *App {
 	// Videos
 	mux.HandleFunc("/video/new", a.mw.IsAdmin(a.posts.NewPostHandler))
 	mux.HandleFunc("GET /video/{video}/{$}", a.posts.SinglePostHandler)
+
+	// Localized videos
+	for _, locale := range a.locales {
+		mux.HandleFunc("GET /"+locale+"/video/{video}/{$}", a.posts.LocalizedPostHandler(locale))
+	}
+
 	mux.HandleFunc("/video/{video}/edit", a.mw.IsAdmin(a.posts.UpdatePostHandler))
+	mux.HandleFunc("/video/{video}/regenerate", a.mw.IsAdmin(a.posts.RegeneratePostHandler))
 	mux.HandleFunc("POST /video/{video}/delete", a.mw.IsAdmin(a.posts.BanPostHandler))
-	mux.HandleFunc("POST /api/video/{video}/{action}", a.mw.IsAuthenticated(a.posts.ActionPostAPI))
+	mux.HandleFunc("POST /api/video/{video}/{action}", a.mw.IsAuthenticated(a.mw.RateLimit(middlewares.ActionLimit, a.posts.ActionPostAPI)))
+	mux.HandleFunc("POST /video/{video}/flag/{action}", a.mw.IsAdmin(a.posts.ReviewFlaggedPostHandler))
+	mux.HandleFunc("GET /flagged/{$}", a.mw.IsAdmin(a.posts.FlaggedPostsHandler))
+	mux.HandleFunc("GET /oembed", a.mw.PublicCache(a.posts.OEmbedHandler))
 
 	// Categories
 	mux.HandleFunc("GET /category/{category}/{$}", a.posts.CategoryPostsHandler)
 	mux.HandleFunc("GET /api/category/{category}/{$}", a.posts.CategoryPostsAPI)
+	mux.HandleFunc("GET /categories/suggested/{$}", a.mw.IsAdmin(a.posts.SuggestedCategoriesHandler))
+	mux.HandleFunc("POST /categories/suggested/{action}", a.mw.IsAdmin(a.posts.ReviewSuggestedCategoryHandler))
 
 	// Pages
 	mux.HandleFunc("GET /page/{slug}/{$}", a.pages.SinglePageHandler)
@@ -51,20 +66,49 @@ func (a *App) RegisterRoutes() *App {
 	mux.HandleFunc("GET /{part}", a.mw.PublicCache(a.sitemaps.SitemapPartHandler))
 	mux.HandleFunc("GET /sitemap.xml", a.mw.PublicCache(a.sitemaps.SitemapIndexHandler))
 
+	// Feeds
+	for _, format := range feeds.Formats {
+		mux.HandleFunc("GET /"+format, a.mw.PublicCache(a.feeds.HomeFeedHandler(format)))
+		mux.HandleFunc("GET /category/{category}/"+format, a.mw.PublicCache(a.feeds.CategoryFeedHandler(format)))
+		mux.HandleFunc("GET /source/{source}/"+format, a.mw.PublicCache(a.feeds.SourceFeedHandler(format)))
+	}
+
 	// Users
 	mux.HandleFunc("POST /account/delete", a.mw.IsAuthenticated(a.auth.DeleteAccountHandler))
 	mux.HandleFunc("GET /user/favorites/{$}", a.mw.IsAuthenticated(a.users.UserFavoritesHandler))
 	mux.HandleFunc("GET /api/user/favorites/{$}", a.mw.IsAuthenticated(a.users.UserFavoritesAPI))
+	mux.HandleFunc("GET /user/searches/{$}", a.mw.IsAuthenticated(a.users.SavedSearchesHandler))
+	mux.HandleFunc("POST /user/searches/save", a.mw.IsAuthenticated(a.users.SaveSearchHandler))
+	mux.HandleFunc("POST /user/searches/{id}/delete", a.mw.IsAuthenticated(a.users.DeleteSavedSearchHandler))
+	mux.HandleFunc("GET /user/tokens/{$}", a.mw.IsAuthenticated(a.users.TokensHandler))
+	mux.HandleFunc("POST /user/tokens/new", a.mw.IsAuthenticated(a.users.NewTokenHandler))
+	mux.HandleFunc("POST /user/tokens/{id}/delete", a.mw.IsAuthenticated(a.users.DeleteTokenHandler))
 	mux.HandleFunc("GET /users/{$}", a.mw.IsAdmin(a.users.UsersHandler))
 
+	// Public versioned API
+	for pattern, handler := range a.api.Routes() {
+		mux.HandleFunc(pattern, a.mw.PublicCache(a.mw.RateLimit(middlewares.APILimit, handler)))
+	}
+	mux.HandleFunc("GET /api/v1/openapi.json", a.mw.PublicCache(a.api.OpenAPIHandler))
+
 	// The rest
-	mux.HandleFunc("GET /search/{$}", a.posts.SearchPostsHandler)
-	mux.HandleFunc("GET /api/search/{$}", a.posts.SearchPostsAPI)
+	mux.HandleFunc("GET /search/{$}", a.mw.RateLimit(middlewares.SearchLimit, a.posts.SearchPostsHandler))
+	mux.HandleFunc("GET /api/search/{$}", a.mw.RateLimit(middlewares.SearchLimit, a.posts.SearchPostsAPI))
+	mux.HandleFunc("GET /api/search/suggest/{$}", a.mw.RateLimit(middlewares.SuggestLimit, a.posts.SearchSuggestionsAPI))
+	mux.HandleFunc("POST /api/search/click/{$}", a.posts.SearchClickAPI)
+	mux.HandleFunc("GET /searches/{$}", a.mw.IsAdmin(a.posts.SearchReportHandler))
+	mux.HandleFunc("GET /synonyms/{$}", a.mw.IsAdmin(a.posts.SynonymsHandler))
+	mux.HandleFunc("POST /synonyms/{action}", a.mw.IsAdmin(a.posts.ManageSynonymsHandler))
 	mux.HandleFunc("GET /api/health/{$}", a.mw.IsAdmin(a.misc.HealthAPI))
 	mux.HandleFunc("GET /static/", a.misc.StaticHandler)
 	mux.HandleFunc("GET /ads.txt", a.mw.PublicCache(a.misc.TextHandler))
 	mux.HandleFunc("GET /robots.txt", a.mw.PublicCache(a.misc.TextHandler))
 
+	// The IndexNow key file proving the ownership of the site
+	if a.indexNow.Enabled() {
+		mux.HandleFunc("GET "+a.indexNow.KeyPath(), a.mw.PublicCache(a.misc.IndexNowKeyHandler))
+	}
+
 	// Register favicons serving from root
 	for _, favicon := range utils.RootFavicons {
 		mux.HandleFunc("GET "+favicon, a.misc.StaticHandler)
@@ -82,6 +126,9 @@ func (a *App) RegisterRoutes() *App {
 		},
 	))
 
+	// Prometheus metrics, scraped with an admin API token
+	mux.HandleFunc("GET /metrics", a.mw.IsAdmin(metrics.Handler().ServeHTTP))
+
 	// Simple health check
 	mux.HandleFunc("GET /healthcheck", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("X-Robots-Tag", "noindex")
@@ -100,6 +147,8 @@ func (a *App) RegisterRoutes() *App {
 	// The order is important.
 	// Use this custom handler as HTTP server handler
 	a.server.Handler = a.mw.ApplyToAll(
+		a.mw.Trace,             // Start the request span, continuing the caller's trace
+		a.mw.RequestID,         // Identify the request in the logs and in the response
 		a.mw.CloseBody,         // Absolute safety net for body memory leaks
 		a.mw.Compress,          // Compress the response no matter what is it
 		a.mw.CanonicalRedirect, // Redirect www to non-www, nothing to do
@@ -110,6 +159,8 @@ func (a *App) RegisterRoutes() *App {
 		a.mw.LoadData,          // Generate and store template data to context
 		a.mw.HandleErrors,      // Provide response recorder, inspect it, and if error serve HTML/JSON errors
 		a.mw.RecoverPanic,      // Log panic in mux and return 500 error response to client
+		a.mw.Metrics,           // Count the requests by the route pattern the mux matches
+		a.mw.TraceRoute,        // Name the request span after the route pattern the mux matches
 	)(mux)
 
 	return a
@@ -2,7 +2,7 @@ package app
 
 import (
 	"fmt"
-	"log"
+	"log/slog"
 	"net"
 	"net/http"
 )
@@ -41,7 +41,7 @@ func (a *App) Run() error {
 	}
 
 	<-done // Wait for the graceful shutdown to complete
-	log.Println("Graceful shutdown complete.")
+	slog.Info("graceful shutdown complete")
 
 	return nil
 }
@@ -2,7 +2,7 @@ package app
 
 import (
 	"context"
-	"log"
+	"log/slog"
 	"os/signal"
 	"syscall"
 	"time"
@@ -21,7 +21,7 @@ func (a *App) Shutdown(done chan<- struct{}) {
 	// This channel is closed by the sender and the program proceeds.
 	<-ctx.Done()
 
-	log.Println("Shutting down gracefully, press Ctrl+C again to force...")
+	slog.Info("shutting down gracefully, press Ctrl+C again to force")
 
 	// Cancel the context, stop watching for termination signals.
 	// Now if the user presses Ctrl+C again (or someone sends SIGINT, SIGTERM signal),
@@ -38,16 +38,16 @@ func (a *App) Shutdown(done chan<- struct{}) {
 	// Shutdown will wait for connections to return to idle,
 	// but in this case up to 5 seconds.
 	if err := a.server.Shutdown(ctx); err != nil {
-		log.Printf("Server forced to shutdown with error: %v", err)
+		slog.Error("server forced to shut down", "error", err)
 	}
 
 	// Perform cleanup. Close the DB pool and Redis connections.
-	log.Println("Closing Database and Redis connections...")
+	slog.Info("closing the database and Redis connections")
 	if err := a.cleanup(); err != nil {
-		log.Printf("Error during cleanup: %v", err)
+		slog.Error("failed to clean up", "error", err)
 	}
 
-	log.Println("Server exiting...")
+	slog.Info("server exiting")
 
 	// Notify the main goroutine that the shutdown is complete
 	done <- struct{}{}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"log/slog"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -15,6 +16,7 @@ import (
 
 	"github.com/vlatan/video-store/internal/config"
 	"github.com/vlatan/video-store/internal/integrations/r2"
+	"github.com/vlatan/video-store/internal/logging"
 )
 
 type Service struct {
@@ -44,20 +46,23 @@ func New(cfg *config.Config, r2s r2.Service) *Service {
 // Run dumps a database to file and uploads that file to a bucket
 func (s *Service) Run(ctx context.Context) error {
 
-	log.Println("Backup service running...")
+	// Every log line of the run carries its ID
+	ctx = logging.With(ctx, "runId", logging.NewID())
+
+	slog.InfoContext(ctx, "backup running")
 	dbDump := fmt.Sprintf("backup-%v.bak", time.Now().Format("2006-01-02T15-04"))
 	if err := s.DumpDatabase(dbDump); err != nil {
 		return err
 	}
 
-	log.Println("Database dumped.")
+	slog.InfoContext(ctx, "database dumped", "file", dbDump)
 
 	archive := fmt.Sprintf("%s.tar.gz", dbDump)
 	if err := s.ArchiveFiles(archive, dbDump); err != nil {
 		return err
 	}
 
-	log.Println("Database compressed.")
+	slog.InfoContext(ctx, "database compressed", "file", archive)
 
 	if err := s.r2s.UploadFile(
 		ctx,
@@ -69,8 +74,8 @@ func (s *Service) Run(ctx context.Context) error {
 		return err
 	}
 
-	log.Println("Database uploaded to bucket.")
-	log.Println("Backup finished successfully.")
+	slog.InfoContext(ctx, "database uploaded", "bucket", s.config.R2BackupBucketName)
+	slog.InfoContext(ctx, "backup finished")
 
 	return nil
 }
@@ -4,12 +4,23 @@ import (
 	"encoding/base64"
 	"fmt"
 	"math"
+	"net/netip"
+	"regexp"
 	"runtime"
 	"time"
 
 	"github.com/caarlos0/env/v11"
 )
 
+// Validate a locale, i.e. "es" or "pt-BR"
+var validLocale = regexp.MustCompile(`^[a-z]{2,3}(-[A-Za-z0-9]{2,8})?$`)
+
+// Validate an IndexNow key, it's served as a file from the root
+var validIndexNowKey = regexp.MustCompile(`^[a-zA-Z0-9-]{8,128}$`)
+
+// The embedding dimensions of the pgvector index, fixed by the migration
+const embeddingDimensions = 768
+
 type Secret struct {
 	Bytes []byte
 }
@@ -48,6 +59,10 @@ type Config struct {
 	PostsPerPage    int    `env:"POSTS_PER_PAGE" envDefault:"24"`
 	NumRelatedPosts int    `env:"NUM_RELATED_POSTS" envDefault:"5"`
 
+	// Locales to translate the posts into, i.e. "es,de,fr"
+	Locales              []string `env:"LOCALES"`
+	TranslationBatchSize int      `env:"TRANSLATION_BATCH_SIZE" envDefault:"10"`
+
 	// Google APIs settings
 	YouTubeAPIKey  string `env:"YOUTUBE_API_KEY"`
 	GeminiAPIKey   string `env:"GEMINI_API_KEY"`
@@ -56,6 +71,15 @@ type Config struct {
 	GeminiRPD      int64  `env:"GEMINI_RPD" envDefault:"20"`
 	GeminiRPM      int64  `env:"GEMINI_RPM" envDefault:"5"`
 
+	// Max corrective prompts when the generated content fails the quality checks
+	GeminiMaxCorrections int `env:"GEMINI_MAX_CORRECTIONS" envDefault:"1"`
+
+	// Embeddings used for the related posts.
+	// Only 768 dimensions are valid, the pgvector index is of that size.
+	GeminiEmbeddingModel string `env:"GEMINI_EMBEDDING_MODEL" envDefault:"gemini-embedding-001"`
+	EmbeddingDimensions  int32  `env:"EMBEDDING_DIMENSIONS" envDefault:"768"`
+	EmbeddingBatchSize   int    `env:"EMBEDDING_BATCH_SIZE" envDefault:"50"`
+
 	// Google OAuth settings
 	GoogleOAuthClientID     string   `env:"GOOGLE_OAUTH_CLIENT_ID"`
 	GoogleOAuthClientSecret string   `env:"GOOGLE_OAUTH_CLIENT_SECRET"`
@@ -94,6 +118,36 @@ type Config struct {
 	RedisPassword string        `env:"REDIS_PASSWORD"`
 	CacheTimeout  time.Duration `env:"CACHE_TIMEOUT" envDefault:"24h"`
 
+	// The hot cached lists are refreshed in the background once older than this,
+	// serving the stale ones meanwhile. Must be shorter than the CACHE_TIMEOUT,
+	// a 24th of it if not set.
+	CacheSoftTimeout time.Duration `env:"CACHE_SOFT_TIMEOUT"`
+
+	// Short lived cache for the search suggestions
+	SuggestCacheTimeout time.Duration `env:"SUGGEST_CACHE_TIMEOUT" envDefault:"10m"`
+
+	// SMTP server sending the emails, no emails are sent without a host
+	SMTPHost     string `env:"SMTP_HOST"`
+	SMTPPort     uint16 `env:"SMTP_PORT" envDefault:"587"`
+	SMTPUsername string `env:"SMTP_USERNAME"`
+	SMTPPassword string `env:"SMTP_PASSWORD"`
+	MailFrom     string `env:"MAIL_FROM"`
+
+	// IndexNow key, the changed URLs are submitted to the
+	// IndexNow compatible endpoints only if there's a key
+	IndexNowKey  string   `env:"INDEXNOW_KEY"`
+	IndexNowURLs []string `env:"INDEXNOW_URLS" envDefault:"https://api.indexnow.org/indexnow"`
+
+	// OpenTelemetry, the spans are exported over OTLP/HTTP only if there's an endpoint.
+	// The exporter reads the rest of the OTEL_EXPORTER_OTLP_* variables itself.
+	OtelEndpoint    string  `env:"OTEL_EXPORTER_OTLP_ENDPOINT"`
+	OtelServiceName string  `env:"OTEL_SERVICE_NAME" envDefault:"video-store"`
+	OtelSampleRatio float64 `env:"OTEL_TRACES_SAMPLER_RATIO" envDefault:"1"`
+
+	// Text search configuration of the search vectors, e.g. "english" or "simple".
+	// The worker rebuilds the vectors when it changes.
+	SearchConfig string `env:"SEARCH_CONFIG" envDefault:"english"`
+
 	// Postgres
 	DBHost     string `env:"DB_HOST" envDefault:"localhost"`
 	DBPort     uint16 `env:"DB_PORT" envDefault:"5432"`
@@ -105,6 +159,10 @@ type Config struct {
 	// Local port
 	Port int `env:"PORT" envDefault:"5000"`
 
+	// The proxies in front of the app, e.g. the Cloudflare ranges, as CIDRs.
+	// The client IP headers are trusted only on the requests coming from them.
+	TrustedProxies []netip.Prefix `env:"TRUSTED_PROXIES"`
+
 	// Worker expected runtime
 	WorkerExpectedRuntime time.Duration `env:"WORKER_EXPECTED_RUNTIME" envDefault:"1h"`
 }
@@ -126,6 +184,36 @@ func New() (*Config, error) {
 	// Cap the DBMaxConns to the number of cores
 	cfg.DBMaxConns = max(cfg.DBMaxConns, int32(numCPU))
 
+	// Check if the locales are valid, they're used in the URL paths
+	for _, locale := range cfg.Locales {
+		if !validLocale.MatchString(locale) || locale == "en" {
+			return nil, fmt.Errorf("invalid locale defined in env: %q", locale)
+		}
+	}
+
+	if cfg.IndexNowKey != "" && !validIndexNowKey.MatchString(cfg.IndexNowKey) {
+		return nil, fmt.Errorf("invalid IndexNow key defined in env: %q", cfg.IndexNowKey)
+	}
+
+	// Only the soft cache timeout set in env can be invalid
+	switch {
+	case cfg.CacheSoftTimeout == 0:
+		cfg.CacheSoftTimeout = cfg.CacheTimeout / 24
+	case cfg.CacheSoftTimeout < 0 || cfg.CacheSoftTimeout >= cfg.CacheTimeout:
+		return nil, fmt.Errorf("invalid soft cache timeout defined in env: %v", cfg.CacheSoftTimeout)
+	}
+
+	if cfg.EmbeddingDimensions != embeddingDimensions {
+		return nil, fmt.Errorf(
+			"invalid embedding dimensions defined in env: %d, only %d supported",
+			cfg.EmbeddingDimensions, embeddingDimensions,
+		)
+	}
+
+	if cfg.OtelSampleRatio < 0 || cfg.OtelSampleRatio > 1 {
+		return nil, fmt.Errorf("invalid trace sample ratio defined in env: %v", cfg.OtelSampleRatio)
+	}
+
 	// Check if the app has all the necessary secrets
 	if cfg.Target == App {
 		secrets := []Secret{cfg.CsrfKey, cfg.AuthKey, cfg.EncryptionKey}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/vlatan/video-store/internal/config"
+	"github.com/vlatan/video-store/internal/tracing"
 )
 
 type Service struct {
@@ -42,6 +43,9 @@ func New(cfg *config.Config) (*Service, error) {
 	// Get MaxConns from the Config
 	poolConfig.MaxConns = cfg.DBMaxConns
 
+	// Trace the queries, no-op unless tracing is set up
+	poolConfig.ConnConfig.Tracer = tracing.QueryTracer{}
+
 	pool, err := pgxpool.NewWithConfig(context.Background(), poolConfig)
 	if err != nil {
 		return nil, err
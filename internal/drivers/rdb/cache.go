@@ -2,25 +2,158 @@ package rdb
 
 import (
 	"context"
-	"log"
+	"fmt"
 	"log/slog"
 	"reflect"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
+	"github.com/vlatan/video-store/internal/metrics"
 	"github.com/vlatan/video-store/internal/utils"
+	"golang.org/x/sync/singleflight"
 )
 
+const (
+	lockKeyPrefix    = "cache:lock:"         // Redis lock held while loading a key
+	loadLockTTL      = 5 * time.Second       // how long the other replicas wait for a load
+	lockPollInterval = 50 * time.Millisecond // how often the waiting replicas check the cache
+	detachedTimeout  = 30 * time.Second      // time limit of the loads outliving their caller
+	refreshPrefix    = "refresh:"            // singleflight key prefix of the background refreshes
+)
+
+// The loads in flight in this process, per key
+var loads singleflight.Group
+
+// CacheOption configures the caching at the call site
+type CacheOption func(*cacheOptions)
+
+type cacheOptions struct {
+	tags         []string
+	singleflight bool
+	lock         bool
+	softTTL      time.Duration
+}
+
+// WithTags registers the entry under the tags, so invalidating any of them deletes it.
+// The data implementing the Tagger interface adds its own tags.
+func WithTags(tags ...string) CacheOption {
+	return func(o *cacheOptions) {
+		o.tags = append(o.tags, tags...)
+	}
+}
+
+// WithSingleflight shares a single load among the concurrent callers
+// missing the key in this process. The load outlives the caller which started it,
+// so the others still get the data if it leaves.
+// The callers share the returned data, so they must not modify it.
+func WithSingleflight() CacheOption {
+	return func(o *cacheOptions) {
+		o.singleflight = true
+	}
+}
+
+// WithLock lets a single replica load the missing key, holding a short Redis lock.
+// The other replicas wait for the data to show up in the cache,
+// and load it themselves if it doesn't in time.
+func WithLock() CacheOption {
+	return func(o *cacheOptions) {
+		o.lock = true
+	}
+}
+
+// WithSoftTTL serves the data older than the soft TTL as stale, until it expires,
+// while a single caller across the replicas refreshes it in the background.
+func WithSoftTTL(softTTL time.Duration) CacheOption {
+	return func(o *cacheOptions) {
+		o.softTTL = softTTL
+	}
+}
+
 // GetCachedData is generic wrapper getting and setting from cache,
 // with provided function to call if data not in Redis cache.
+// The function gets the context to load the data with,
+// which is not the caller's one if the load outlives it.
 func GetCachedData[T any](
 	ctx context.Context,
 	rdb *Service,
 	key string,
 	ttl time.Duration,
-	callable func() (T, error), // Function to call if cache misses
+	callable func(context.Context) (T, error), // Function to call if cache misses
+	opts ...CacheOption,
 ) (T, error) {
 
+	var options cacheOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	var zero T
+
+	// Try to get value from Redis cache
+	data, stale, err := getCached[T](ctx, rdb, key, ttl, options.softTTL)
+	metrics.ObserveCache(key, err == nil)
+	if err == nil {
+		if stale {
+			refresh(ctx, rdb, key, ttl, callable, &options)
+		}
+		return data, nil
+	}
+
+	// Exit early if context error
+	if utils.IsContextErr(err) {
+		return zero, err
+	}
+
+	// Ignore/log non-nil errors
+	if err != redis.Nil {
+		slog.ErrorContext(
+			ctx, "failed to get data from Redis",
+			"key", key,
+			"error", err,
+		)
+	}
+
+	// If not in cache or error, load the data
+	if !options.singleflight {
+		return load(ctx, rdb, key, ttl, callable, &options)
+	}
+
+	// Share the load with the concurrent callers getting the same type,
+	// the call sites sharing a key could expect different ones
+	result := loads.DoChan(flightKey[T](key), func() (any, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), detachedTimeout)
+		defer cancel()
+		return load(ctx, rdb, key, ttl, callable, &options)
+	})
+
+	select {
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	case res := <-result:
+		if res.Err != nil {
+			return zero, res.Err
+		}
+		return res.Val.(T), nil
+	}
+}
+
+// flightKey is the singleflight key of the Redis key loaded as T
+func flightKey[T any](key string) string {
+	var zero T
+	return fmt.Sprintf("%T:%s", zero, key)
+}
+
+// getCached gets the data from Redis.
+// With a soft TTL it also tells whether the data is older than it,
+// judging by the time the entry has left to live.
+func getCached[T any](
+	ctx context.Context,
+	rdb *Service,
+	key string,
+	ttl, softTTL time.Duration,
+) (T, bool, error) {
+
 	var zero, data T
 	var target any = &data
 
@@ -47,41 +180,178 @@ func GetCachedData[T any](
 	// target is either a pointer to data, or it is equivalent to data which in that case is a pointer.
 	// In any case we're passing target to the Scan, but ultimately the data object is being filled.
 
-	// Try to get value from Redis cache.
 	// The underlying data type needs to implement
 	// the encoding.BinaryUnmarshaler interface if needed.
-	err := rdb.Client.Get(ctx, key).Scan(target)
-	if err == nil {
-		return data, nil
+	if softTTL <= 0 {
+		if err := rdb.Client.Get(ctx, key).Scan(target); err != nil {
+			return zero, false, err
+		}
+		return data, false, nil
 	}
 
-	// Exit early if context error
-	if utils.IsContextErr(err) {
-		return zero, err
+	// Get the data and its time to live in a single round trip
+	var get *redis.StringCmd
+	var pttl *redis.DurationCmd
+	_, err := rdb.Client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		get = pipe.Get(ctx, key)
+		pttl = pipe.PTTL(ctx, key)
+		return nil
+	})
+
+	if err != nil {
+		return zero, false, err
 	}
 
-	// Ignore/log non-nil errors
-	if err != redis.Nil {
-		slog.ErrorContext(
-			ctx, "failed to get data from Redis",
-			"key", key,
-			"error", err,
-		)
+	if err = get.Scan(target); err != nil {
+		return zero, false, err
 	}
 
-	// If not in cache or error, execute the given function
-	data, err = callable()
+	// The entry lived past the soft TTL if it has less than the rest to live
+	stale := pttl.Val() >= 0 && pttl.Val() < ttl-softTTL
+	return data, stale, nil
+}
+
+// load calls the function and caches the data.
+// With the lock option only the replica holding the lock calls it,
+// the others wait for the data to show up in the cache.
+func load[T any](
+	ctx context.Context,
+	rdb *Service,
+	key string,
+	ttl time.Duration,
+	callable func(context.Context) (T, error),
+	options *cacheOptions,
+) (T, error) {
+
+	var zero T
+
+	if options.lock {
+		lock := rdb.NewLock(lockKeyPrefix+key, uuid.NewString(), loadLockTTL)
+		ok, err := lock.TryLock(ctx)
+
+		switch {
+		case err != nil:
+			// Redis is down, load the data without the lock
+			slog.ErrorContext(ctx, "failed to lock the cache key", "key", key, "error", err)
+		case ok:
+			defer lock.Unlock(context.WithoutCancel(ctx))
+		default:
+			// Another replica is loading the data
+			data, err := waitCached[T](ctx, rdb, key, loadLockTTL)
+			if err == nil {
+				return data, nil
+			}
+
+			if utils.IsContextErr(err) {
+				return zero, err
+			}
+
+			// The replica failed or took too long, load the data here
+		}
+	}
+
+	data, err := callable(ctx)
 	if err != nil {
 		return zero, err
 	}
 
-	// Cache the data for later use.
+	setCached(ctx, rdb, key, data, ttl, options.tags)
+	return data, nil
+}
+
+// waitCached polls the cache until the data shows up or the timeout passes
+func waitCached[T any](ctx context.Context, rdb *Service, key string, timeout time.Duration) (T, error) {
+
+	var zero T
+
+	ticker := time.NewTicker(lockPollInterval)
+	defer ticker.Stop()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return zero, ctx.Err()
+		case <-timer.C:
+			return zero, redis.Nil
+		case <-ticker.C:
+			data, _, err := getCached[T](ctx, rdb, key, 0, 0)
+			if err != redis.Nil {
+				return data, err
+			}
+		}
+	}
+}
+
+// refresh reloads the stale data in the background.
+// Only one caller in this process, and one across the replicas, refreshes it.
+func refresh[T any](
+	ctx context.Context,
+	rdb *Service,
+	key string,
+	ttl time.Duration,
+	callable func(context.Context) (T, error),
+	options *cacheOptions,
+) {
+
+	// Nobody waits for the result, the channel is buffered
+	loads.DoChan(refreshPrefix+flightKey[T](key), func() (any, error) {
+
+		// The refresh outlives the request
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), detachedTimeout)
+		defer cancel()
+
+		lock := rdb.NewLock(lockKeyPrefix+key, uuid.NewString(), detachedTimeout)
+		if ok, err := lock.TryLock(ctx); !ok || err != nil {
+			return nil, err // another replica is refreshing it
+		}
+		defer lock.Unlock(ctx)
+
+		data, err := callable(ctx)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to refresh the cached data", "key", key, "error", err)
+			return nil, err
+		}
+
+		setCached(ctx, rdb, key, data, ttl, options.tags)
+		return nil, nil
+	})
+}
+
+// setCached caches the data with its tags.
+// The failure is only logged, the data is loaded again on the next miss.
+func setCached[T any](
+	ctx context.Context,
+	rdb *Service,
+	key string,
+	data T,
+	ttl time.Duration,
+	tags []string,
+) {
+
+	// Collect the tags of the entry
+	tags = append([]string(nil), tags...)
+	if tagger, ok := any(data).(Tagger); ok {
+		tags = append(tags, tagger.CacheTags()...)
+	}
+
 	// The underlying data type needs to implement
 	// the encoding.BinaryMarshaler interface if needed.
-	if err = rdb.Client.Set(ctx, key, data, ttl).Err(); err != nil {
-		// Don't return an error if unable to set redis cache
-		log.Printf("redis error for key %q: %v", key, err)
+	var err error
+	if len(tags) > 0 {
+		err = rdb.setTagged(ctx, key, data, ttl, tags)
+	} else {
+		err = rdb.Client.Set(ctx, key, data, ttl).Err()
 	}
 
-	return data, nil
+	if err != nil {
+		// Don't return an error if unable to set redis cache
+		slog.ErrorContext(
+			ctx, "failed to set data in Redis",
+			"key", key,
+			"error", err,
+		)
+	}
 }
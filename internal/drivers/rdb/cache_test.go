@@ -3,14 +3,16 @@ package rdb
 import (
 	"context"
 	"errors"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
 
 func TestGetCachedData(t *testing.T) {
 
-	validCallable := func() (int, error) { return 1, nil }
-	errorCallable := func() (int, error) { return 0, errors.New("test") }
+	validCallable := func(context.Context) (int, error) { return 1, nil }
+	errorCallable := func(context.Context) (int, error) { return 0, errors.New("test") }
 
 	errorRdb, err := New(testCfg)
 	if err != nil {
@@ -27,7 +29,7 @@ func TestGetCachedData(t *testing.T) {
 		name     string
 		ctx      context.Context
 		rdb      *Service
-		callable func() (int, error)
+		callable func(context.Context) (int, error)
 		wantErr  bool
 	}{
 		{"no context", noCtx, testRdb, validCallable, true},
@@ -52,3 +54,123 @@ func TestGetCachedData(t *testing.T) {
 		})
 	}
 }
+
+func TestGetCachedDataSingleflight(t *testing.T) {
+
+	var calls atomic.Int32
+	callable := func(context.Context) (int, error) {
+		calls.Add(1)
+		time.Sleep(100 * time.Millisecond)
+		return 1, nil
+	}
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Go(func() {
+			data, err := GetCachedData(baseCtx, testRdb, "singleflight", time.Minute, callable, WithSingleflight())
+			if err != nil || data != 1 {
+				t.Errorf("got (%d, %v), want (1, nil)", data, err)
+			}
+		})
+	}
+	wg.Wait()
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("got %d calls, want 1", got)
+	}
+}
+
+func TestGetCachedDataSingleflightTypes(t *testing.T) {
+
+	// Two call sites share the key but expect different types
+	key := "singleflight:types"
+
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		data, err := GetCachedData(baseCtx, testRdb, key, time.Minute,
+			func(context.Context) (int, error) {
+				time.Sleep(100 * time.Millisecond)
+				return 1, nil
+			},
+			WithSingleflight(),
+		)
+		if err != nil || data != 1 {
+			t.Errorf("got (%d, %v), want (1, nil)", data, err)
+		}
+	})
+
+	wg.Go(func() {
+		data, err := GetCachedData(baseCtx, testRdb, key, time.Minute,
+			func(context.Context) (string, error) {
+				time.Sleep(100 * time.Millisecond)
+				return "1", nil
+			},
+			WithSingleflight(),
+		)
+		if err != nil || data != "1" {
+			t.Errorf("got (%q, %v), want (\"1\", nil)", data, err)
+		}
+	})
+
+	wg.Wait()
+}
+
+func TestGetCachedDataLock(t *testing.T) {
+
+	key := "locked"
+
+	// Another replica holds the lock and caches the data a bit later
+	if err := testRdb.Client.Set(baseCtx, lockKeyPrefix+key, "replica", time.Second).Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		testRdb.Client.Set(baseCtx, key, 2, time.Minute)
+	}()
+
+	callable := func(context.Context) (int, error) {
+		t.Error("the data loaded by the other replica was loaded again")
+		return 1, nil
+	}
+
+	data, err := GetCachedData(baseCtx, testRdb, key, time.Minute, callable, WithLock())
+	if err != nil || data != 2 {
+		t.Errorf("got (%d, %v), want (2, nil)", data, err)
+	}
+}
+
+func TestGetCachedDataSoftTTL(t *testing.T) {
+
+	key := "soft"
+	if err := testRdb.Client.Set(baseCtx, key, 1, time.Minute).Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	refreshed := make(chan struct{})
+	callable := func(context.Context) (int, error) {
+		defer close(refreshed)
+		return 2, nil
+	}
+
+	// The entry lived past its soft TTL, the stale data is served
+	data, err := GetCachedData(baseCtx, testRdb, key, time.Hour, callable, WithSoftTTL(time.Minute))
+	if err != nil || data != 1 {
+		t.Errorf("got (%d, %v), want the stale (1, nil)", data, err)
+	}
+
+	select {
+	case <-refreshed:
+	case <-time.After(time.Second):
+		t.Fatal("the stale data was not refreshed")
+	}
+
+	// Wait for the refresh to cache the data
+	for range 20 {
+		if data, _ := testRdb.Client.Get(baseCtx, key).Int(); data == 2 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Error("the refreshed data was not cached")
+}
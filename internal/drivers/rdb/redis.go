@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/redis/go-redis/extra/redisotel/v9"
 	"github.com/redis/go-redis/v9"
 	"github.com/vlatan/video-store/internal/config"
 )
@@ -27,6 +28,11 @@ func New(cfg *config.Config) (*Service, error) {
 		DB:       0, // use default DB
 	})
 
+	// Trace the commands, no-op unless tracing is set up
+	if err := redisotel.InstrumentTracing(rdb); err != nil {
+		return nil, fmt.Errorf("failed to instrument Redis tracing: %w", err)
+	}
+
 	return &Service{rdb}, nil
 }
 
@@ -0,0 +1,119 @@
+package rdb
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// The tags of the cache entries. An entry registers the tags of the data it holds,
+// and invalidating any of them deletes it, wherever the data is cached.
+const (
+	HomeTag       = "listing:home"       // the latest posts
+	SearchTag     = "listing:search"     // the search results and suggestions
+	CategoriesTag = "listing:categories" // the list of categories
+	SourcesTag    = "listing:sources"    // the list of sources
+)
+
+// The Redis set holding the keys of the entries with the tag
+const tagKeyPrefix = "cache:tag:"
+
+// PostTag tags the entries holding the post
+func PostTag(videoID string) string {
+	return "post:" + videoID
+}
+
+// CategoryTag tags the lists of posts in the category
+func CategoryTag(slug string) string {
+	return "category:" + slug
+}
+
+// SourceTag tags the lists of posts from the source
+func SourceTag(playlistID string) string {
+	return "source:" + playlistID
+}
+
+// Tagger is implemented by the cached data which knows its own tags,
+// like the posts in a list, so the call sites don't have to.
+type Tagger interface {
+	CacheTags() []string
+}
+
+// Set the entry and add its key to the tag sets.
+// A tag set lives as long as its longest living entry.
+// KEYS[1] is the entry, KEYS[2..] the tag sets.
+// ARGV[1] is the data, ARGV[2] the TTL in milliseconds, 0 for no expiration.
+var setTaggedScript = redis.NewScript(`
+local ttl = tonumber(ARGV[2])
+
+if ttl > 0 then
+	redis.call("SET", KEYS[1], ARGV[1], "PX", ttl)
+else
+	redis.call("SET", KEYS[1], ARGV[1])
+end
+
+for i = 2, #KEYS do
+	local current = redis.call("PTTL", KEYS[i]) -- -2 missing, -1 no expiration
+	redis.call("SADD", KEYS[i], KEYS[1])
+
+	if ttl <= 0 then
+		redis.call("PERSIST", KEYS[i])
+	elseif current ~= -1 and current < ttl then
+		redis.call("PEXPIRE", KEYS[i], ttl)
+	end
+end
+
+return 1
+`)
+
+// Delete the entries in the tag sets and the sets themselves.
+// The entries are not declared in KEYS, which a single Redis instance allows.
+// KEYS are the tag sets.
+var invalidateScript = redis.NewScript(`
+local deleted = 0
+
+for _, tag in ipairs(KEYS) do
+	local keys = redis.call("SMEMBERS", tag)
+	for i = 1, #keys, 1000 do
+		deleted = deleted + redis.call("DEL", unpack(keys, i, math.min(i + 999, #keys)))
+	end
+	redis.call("DEL", tag)
+end
+
+return deleted
+`)
+
+// setTagged caches the data under the key with the tags, atomically
+func (rs *Service) setTagged(
+	ctx context.Context,
+	key string,
+	data any,
+	ttl time.Duration,
+	tags []string,
+) error {
+
+	keys := make([]string, 0, len(tags)+1)
+	keys = append(keys, key)
+	for _, tag := range tags {
+		keys = append(keys, tagKeyPrefix+tag)
+	}
+
+	return setTaggedScript.Run(ctx, rs.Client, keys, data, ttl.Milliseconds()).Err()
+}
+
+// Invalidate deletes the cache entries with any of the tags, atomically.
+// Returns the number of the deleted entries.
+func (rs *Service) Invalidate(ctx context.Context, tags ...string) (int64, error) {
+
+	if len(tags) == 0 {
+		return 0, nil
+	}
+
+	keys := make([]string, len(tags))
+	for i, tag := range tags {
+		keys[i] = tagKeyPrefix + tag
+	}
+
+	return invalidateScript.Run(ctx, rs.Client, keys).Int64()
+}
@@ -0,0 +1,90 @@
+package rdb
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// Cached data which knows its own tags
+type taggedPost struct {
+	VideoID string `json:"video_id"`
+}
+
+func (p taggedPost) CacheTags() []string {
+	return []string{PostTag(p.VideoID)}
+}
+
+func (p taggedPost) MarshalBinary() ([]byte, error) {
+	return json.Marshal(p)
+}
+
+func (p *taggedPost) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, p)
+}
+
+func TestInvalidate(t *testing.T) {
+
+	// Cache the entries with their tags
+	entries := []struct {
+		key  string
+		ttl  time.Duration
+		post taggedPost
+		tags []string
+	}{
+		{"tags:post:a", time.Minute, taggedPost{"a"}, nil},
+		{"tags:home", time.Hour, taggedPost{"b"}, []string{HomeTag}},
+		{"tags:category", time.Minute, taggedPost{"c"}, []string{CategoryTag("music")}},
+		{"tags:search", time.Minute, taggedPost{"a"}, []string{SearchTag}},
+	}
+
+	for _, e := range entries {
+		_, err := GetCachedData(baseCtx, testRdb, e.key, e.ttl,
+			func(context.Context) (taggedPost, error) { return e.post, nil },
+			WithTags(e.tags...),
+		)
+		if err != nil {
+			t.Fatalf("failed to cache %q; %v", e.key, err)
+		}
+	}
+
+	// The tag set outlives its longest living entry
+	ttl, err := testRdb.Client.PTTL(baseCtx, tagKeyPrefix+HomeTag).Result()
+	if err != nil || ttl <= time.Minute {
+		t.Errorf("got tag set TTL %v, error %v, want about an hour", ttl, err)
+	}
+
+	tests := []struct {
+		name        string
+		tags        []string
+		wantDeleted int64
+		wantGone    []string
+	}{
+		{"no tags", nil, 0, nil},
+		{"unknown tag", []string{PostTag("x")}, 0, nil},
+		{"data tag", []string{PostTag("a")}, 2, []string{"tags:post:a", "tags:search"}},
+		{"call site tags", []string{HomeTag, CategoryTag("music")}, 2, []string{"tags:home", "tags:category"}},
+		{"invalidated tag", []string{HomeTag}, 0, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			deleted, err := testRdb.Invalidate(baseCtx, tt.tags...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if deleted != tt.wantDeleted {
+				t.Errorf("got %d deleted entries, want %d", deleted, tt.wantDeleted)
+			}
+
+			for _, key := range tt.wantGone {
+				if n, _ := testRdb.Client.Exists(baseCtx, key).Result(); n != 0 {
+					t.Errorf("the entry %q is still cached", key)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/vlatan/video-store/internal/config"
+	"github.com/vlatan/video-store/internal/drivers/rdb"
+	catsRepo "github.com/vlatan/video-store/internal/repositories/categories"
+	pagesRepo "github.com/vlatan/video-store/internal/repositories/pages"
+	postsRepo "github.com/vlatan/video-store/internal/repositories/posts"
+	sourcesRepo "github.com/vlatan/video-store/internal/repositories/sources"
+	"github.com/vlatan/video-store/internal/ui"
+)
+
+type Service struct {
+	postsRepo   *postsRepo.Repository
+	catsRepo    *catsRepo.Repository
+	sourcesRepo *sourcesRepo.Repository
+	pagesRepo   *pagesRepo.Repository
+	rdb         *rdb.Service
+	ui          ui.Service
+	config      *config.Config
+	baseURL     string
+	spec        []byte
+}
+
+// Create new public API service, the OpenAPI document is generated once
+func New(
+	postsRepo *postsRepo.Repository,
+	catsRepo *catsRepo.Repository,
+	sourcesRepo *sourcesRepo.Repository,
+	pagesRepo *pagesRepo.Repository,
+	rdb *rdb.Service,
+	ui ui.Service,
+	config *config.Config,
+) (*Service, error) {
+
+	baseURL := fmt.Sprintf("%s://%s", config.Protocol, config.Domain)
+
+	spec, err := json.Marshal(newDocument(config.AppName, baseURL))
+	if err != nil {
+		return nil, fmt.Errorf("could not encode the OpenAPI document; %w", err)
+	}
+
+	return &Service{
+		postsRepo:   postsRepo,
+		catsRepo:    catsRepo,
+		sourcesRepo: sourcesRepo,
+		pagesRepo:   pagesRepo,
+		rdb:         rdb,
+		ui:          ui,
+		config:      config,
+		baseURL:     baseURL,
+		spec:        spec,
+	}, nil
+}
@@ -0,0 +1,252 @@
+package api
+
+import (
+	"time"
+
+	"github.com/vlatan/video-store/internal/models"
+)
+
+// The response objects of the public API. Their shape is a contract
+// with the API clients, add fields but don't rename or remove them
+// without a new API version. The doc tags end up in the OpenAPI document.
+
+// Video in the lists
+type Post struct {
+	ID         string     `json:"id" doc:"YouTube video ID"`
+	Title      string     `json:"title"`
+	URL        string     `json:"url" doc:"Video page on the site"`
+	Thumbnail  *Thumbnail `json:"thumbnail,omitempty"`
+	Category   *Category  `json:"category,omitempty"`
+	Source     *Source    `json:"source,omitempty"`
+	Likes      int        `json:"likes"`
+	Rating     *Rating    `json:"rating,omitempty"`
+	Duration   string     `json:"duration,omitempty" doc:"ISO 8601 duration"`
+	Snippet    string     `json:"snippet,omitempty" doc:"Search results only, HTML with the matched terms in mark tags"`
+	UploadDate *time.Time `json:"upload_date,omitempty"`
+}
+
+// Single video with its full details
+type PostDetail struct {
+	Post
+	Description string   `json:"description,omitempty"`
+	Summary     string   `json:"summary,omitempty" doc:"Markdown"`
+	EmbedURL    string   `json:"embed_url" doc:"YouTube player URL"`
+	Locales     []string `json:"locales,omitempty" doc:"Locales the video is translated in"`
+	Related     []Post   `json:"related,omitempty"`
+}
+
+type Thumbnail struct {
+	URL    string `json:"url"`
+	Width  int64  `json:"width"`
+	Height int64  `json:"height"`
+}
+
+type Rating struct {
+	Average float64 `json:"average"`
+	Count   int64   `json:"count"`
+}
+
+type Category struct {
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type Source struct {
+	ID           string     `json:"id" doc:"YouTube playlist ID, other for the videos without a source"`
+	Title        string     `json:"title,omitempty"`
+	ChannelTitle string     `json:"channel_title,omitempty"`
+	Description  string     `json:"description,omitempty"`
+	URL          string     `json:"url"`
+	Thumbnail    *Thumbnail `json:"thumbnail,omitempty"`
+}
+
+type Page struct {
+	Slug    string `json:"slug"`
+	Title   string `json:"title"`
+	Content string `json:"content" doc:"Markdown"`
+	URL     string `json:"url"`
+}
+
+// Facet value with the number of matching videos
+type Facet struct {
+	Value string `json:"value" doc:"Value of the filter query parameter"`
+	Label string `json:"label"`
+	Count int    `json:"count"`
+}
+
+type Facets struct {
+	Categories []Facet `json:"categories,omitempty"`
+	Sources    []Facet `json:"sources,omitempty"`
+	Durations  []Facet `json:"durations,omitempty"`
+	Decades    []Facet `json:"decades,omitempty"`
+	Ratings    []Facet `json:"ratings,omitempty"`
+}
+
+// Cursor pagination, pass the next cursor as the cursor
+// query parameter to get the next page
+type Pagination struct {
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+}
+
+// Response envelopes, the data is always under the data key
+
+type PostList struct {
+	Data       []Post     `json:"data"`
+	Pagination Pagination `json:"pagination"`
+}
+
+type PostResponse struct {
+	Data PostDetail `json:"data"`
+}
+
+type CategoryList struct {
+	Data       []Category `json:"data"`
+	Pagination Pagination `json:"pagination"`
+}
+
+type SourceList struct {
+	Data       []Source   `json:"data"`
+	Pagination Pagination `json:"pagination"`
+}
+
+type PageResponse struct {
+	Data Page `json:"data"`
+}
+
+type SearchResults struct {
+	Data       []Post     `json:"data"`
+	Pagination Pagination `json:"pagination"`
+	Query      string     `json:"query" doc:"The query the results are for, the correction if corrected"`
+	Corrected  bool       `json:"corrected" doc:"The original query found nothing, the results are for its spelling correction"`
+	Total      int        `json:"total,omitempty" doc:"First page only"`
+	Facets     *Facets    `json:"facets,omitempty" doc:"First page only"`
+}
+
+// Error response, the same shape on all the API errors
+type Error struct {
+	Error   string `json:"error" doc:"HTTP status text"`
+	Code    int    `json:"code" doc:"HTTP status code"`
+	Message string `json:"message,omitempty"`
+}
+
+// Convert the post into the API list item
+func (s *Service) newPost(post models.Post) Post {
+
+	p := Post{
+		ID:         post.VideoID,
+		Title:      post.GetTitle(),
+		URL:        s.baseURL + "/video/" + post.VideoID + "/",
+		Thumbnail:  newThumbnail(post.Thumbnail),
+		Likes:      post.Likes,
+		Duration:   string(post.Duration),
+		Snippet:    string(post.Snippet),
+		UploadDate: post.UploadDate,
+	}
+
+	if post.Category != nil && post.Category.Slug != "" {
+		c := s.newCategory(*post.Category)
+		p.Category = &c
+	}
+
+	if post.Source != nil && post.Source.PlaylistID != "" {
+		src := s.newSource(*post.Source)
+		p.Source = &src
+	}
+
+	if post.Rating != nil {
+		p.Rating = &Rating{Average: post.Rating.Avg, Count: post.Rating.Count}
+	}
+
+	return p
+}
+
+// Convert the post into the API detailed post
+func (s *Service) newPostDetail(post models.Post, related []models.Post) PostDetail {
+	return PostDetail{
+		Post:        s.newPost(post),
+		Description: post.Description,
+		Summary:     post.Summary,
+		EmbedURL:    "https://www.youtube.com/embed/" + post.VideoID,
+		Locales:     post.Locales,
+		Related:     s.newPosts(related),
+	}
+}
+
+// Convert the posts into the API list items, never nil
+func (s *Service) newPosts(posts []models.Post) []Post {
+	result := make([]Post, len(posts))
+	for i, post := range posts {
+		result[i] = s.newPost(post)
+	}
+	return result
+}
+
+func (s *Service) newCategory(category models.Category) Category {
+	return Category{
+		Slug: category.Slug,
+		Name: category.Name,
+		URL:  s.baseURL + "/category/" + category.Slug + "/",
+	}
+}
+
+func (s *Service) newSource(source models.Source) Source {
+	return Source{
+		ID:           source.PlaylistID,
+		Title:        source.Title,
+		ChannelTitle: source.ChannelTitle,
+		Description:  source.Description,
+		URL:          s.baseURL + "/source/" + source.PlaylistID + "/",
+		Thumbnail:    newThumbnail(source.Thumbnail),
+	}
+}
+
+func (s *Service) newPage(page models.Page) Page {
+	return Page{
+		Slug:    page.Slug,
+		Title:   page.Title,
+		Content: page.Content,
+		URL:     s.baseURL + "/page/" + page.Slug + "/",
+	}
+}
+
+// Convert the thumbnail, nil if there's no image
+func newThumbnail(thumb *models.Thumbnail) *Thumbnail {
+	if thumb == nil || thumb.Url == "" {
+		return nil
+	}
+	return &Thumbnail{URL: thumb.Url, Width: thumb.Width, Height: thumb.Height}
+}
+
+// Convert the facets, nil if none
+func newFacets(facets *models.SearchFacets) *Facets {
+
+	if facets == nil {
+		return nil
+	}
+
+	convert := func(items []models.Facet) []Facet {
+		if len(items) == 0 {
+			return nil
+		}
+		result := make([]Facet, len(items))
+		for i, item := range items {
+			result[i] = Facet{Value: item.Value, Label: item.Label, Count: item.Count}
+		}
+		return result
+	}
+
+	return &Facets{
+		Categories: convert(facets.Categories),
+		Sources:    convert(facets.Sources),
+		Durations:  convert(facets.Durations),
+		Decades:    convert(facets.Decades),
+		Ratings:    convert(facets.Ratings),
+	}
+}
+
+// Pagination out of the next cursor
+func newPagination(nextCursor string) Pagination {
+	return Pagination{NextCursor: nextCursor, HasMore: nextCursor != ""}
+}
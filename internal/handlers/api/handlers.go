@@ -0,0 +1,320 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/vlatan/video-store/internal/drivers/rdb"
+	"github.com/vlatan/video-store/internal/models"
+	"github.com/vlatan/video-store/internal/utils"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Handle the latest posts
+func (s *Service) PostsHandler(w http.ResponseWriter, r *http.Request) {
+
+	cursor, orderBy, ok := s.listParams(w, r)
+	if !ok {
+		return
+	}
+
+	posts, err := rdb.GetCachedData(
+		r.Context(),
+		s.rdb,
+		postsCacheKey("home:posts", orderBy, cursor),
+		s.config.CacheTimeout,
+		func(ctx context.Context) (models.Posts, error) {
+			return s.postsRepo.GetHomePosts(ctx, cursor, orderBy)
+		},
+		rdb.WithTags(rdb.HomeTag),
+		rdb.WithSingleflight(),
+	)
+
+	if err != nil {
+		s.serverError(w, r, "failed to get posts from DB", err)
+		return
+	}
+
+	s.writeData(w, r, PostList{
+		Data:       s.newPosts(posts.Items),
+		Pagination: newPagination(posts.NextCursor),
+	})
+}
+
+// Handle a single post with its related posts
+func (s *Service) PostHandler(w http.ResponseWriter, r *http.Request) {
+
+	videoID := r.PathValue("id")
+	if !validVideoID.MatchString(videoID) {
+		s.writeError(w, r, http.StatusNotFound, "video not found")
+		return
+	}
+
+	post, err := rdb.GetCachedData(
+		r.Context(),
+		s.rdb,
+		fmt.Sprintf(postCacheKey, videoID),
+		s.config.CacheTimeout,
+		func(ctx context.Context) (models.Post, error) {
+			return s.postsRepo.GetSinglePost(ctx, videoID)
+		},
+	)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		s.writeError(w, r, http.StatusNotFound, "video not found")
+		return
+	}
+
+	if err != nil {
+		s.serverError(w, r, "failed to get the post from DB", err)
+		return
+	}
+
+	// Link only to the currently configured locales
+	post.Locales = slices.DeleteFunc(post.Locales, func(l string) bool {
+		return !slices.Contains(s.config.Locales, l)
+	})
+
+	// Ignore the error on related posts, no posts will be included
+	related, _ := rdb.GetCachedData(
+		r.Context(),
+		s.rdb,
+		fmt.Sprintf(relatedPostsCacheKey, videoID),
+		s.config.CacheTimeout,
+		func(ctx context.Context) (models.Posts, error) {
+			return s.postsRepo.GetRelatedPosts(ctx, videoID, post.GetTitle())
+		},
+		rdb.WithTags(rdb.PostTag(videoID)),
+		rdb.WithSingleflight(),
+	)
+
+	s.writeData(w, r, PostResponse{Data: s.newPostDetail(post, related.Items)})
+}
+
+// Handle all the categories
+func (s *Service) CategoriesHandler(w http.ResponseWriter, r *http.Request) {
+
+	categories, err := rdb.GetCachedData(
+		r.Context(),
+		s.rdb,
+		"categories",
+		s.config.CacheTimeout,
+		func(ctx context.Context) (models.Categories, error) {
+			return s.catsRepo.GetCategories(ctx)
+		},
+		rdb.WithTags(rdb.CategoriesTag),
+		rdb.WithSingleflight(),
+	)
+
+	if err != nil {
+		s.serverError(w, r, "failed to get categories from DB", err)
+		return
+	}
+
+	data := make([]Category, len(categories))
+	for i, category := range categories {
+		data[i] = s.newCategory(category)
+	}
+
+	s.writeData(w, r, CategoryList{Data: data, Pagination: newPagination("")})
+}
+
+// Handle the posts in a category
+func (s *Service) CategoryPostsHandler(w http.ResponseWriter, r *http.Request) {
+
+	cursor, orderBy, ok := s.listParams(w, r)
+	if !ok {
+		return
+	}
+
+	slug := r.PathValue("slug")
+	posts, err := rdb.GetCachedData(
+		r.Context(),
+		s.rdb,
+		postsCacheKey(fmt.Sprintf("category:%s:posts", slug), orderBy, cursor),
+		s.config.CacheTimeout,
+		func(ctx context.Context) (models.Posts, error) {
+			return s.postsRepo.GetCategoryPosts(ctx, slug, cursor, orderBy)
+		},
+		rdb.WithTags(rdb.CategoryTag(slug)),
+		rdb.WithSingleflight(),
+	)
+
+	if err != nil {
+		s.serverError(w, r, "failed to get category posts from DB", err)
+		return
+	}
+
+	// A category without posts is an empty list, an unknown one is not found
+	if len(posts.Items) == 0 && cursor == "" && !s.catsRepo.CategoryExists(r.Context(), slug) {
+		s.writeError(w, r, http.StatusNotFound, "category not found")
+		return
+	}
+
+	s.writeData(w, r, PostList{
+		Data:       s.newPosts(posts.Items),
+		Pagination: newPagination(posts.NextCursor),
+	})
+}
+
+// Handle all the sources
+func (s *Service) SourcesHandler(w http.ResponseWriter, r *http.Request) {
+
+	sources, err := rdb.GetCachedData(
+		r.Context(),
+		s.rdb,
+		"sources",
+		s.config.CacheTimeout,
+		func(ctx context.Context) (models.Sources, error) {
+			return s.sourcesRepo.GetAllSources(ctx)
+		},
+		rdb.WithTags(rdb.SourcesTag),
+		rdb.WithSingleflight(),
+	)
+
+	if err != nil {
+		s.serverError(w, r, "failed to get sources from DB", err)
+		return
+	}
+
+	data := make([]Source, len(sources))
+	for i, source := range sources {
+		data[i] = s.newSource(source)
+	}
+
+	s.writeData(w, r, SourceList{Data: data, Pagination: newPagination("")})
+}
+
+// Handle the posts in a source
+func (s *Service) SourcePostsHandler(w http.ResponseWriter, r *http.Request) {
+
+	cursor, orderBy, ok := s.listParams(w, r)
+	if !ok {
+		return
+	}
+
+	sourceID := r.PathValue("id")
+	posts, err := rdb.GetCachedData(
+		r.Context(),
+		s.rdb,
+		postsCacheKey(fmt.Sprintf("source:%s:posts", sourceID), orderBy, cursor),
+		s.config.CacheTimeout,
+		func(ctx context.Context) (models.Posts, error) {
+			return s.postsRepo.GetSourcePosts(ctx, sourceID, cursor, orderBy)
+		},
+		rdb.WithTags(rdb.SourceTag(sourceID)),
+		rdb.WithSingleflight(),
+	)
+
+	if err != nil {
+		s.serverError(w, r, "failed to get source posts from DB", err)
+		return
+	}
+
+	// A source without posts is an empty list, an unknown one is not found.
+	// The posts without a source are under "other".
+	if len(posts.Items) == 0 && cursor == "" && sourceID != "other" &&
+		!s.sourcesRepo.SourceExists(r.Context(), sourceID) {
+		s.writeError(w, r, http.StatusNotFound, "source not found")
+		return
+	}
+
+	s.writeData(w, r, PostList{
+		Data:       s.newPosts(posts.Items),
+		Pagination: newPagination(posts.NextCursor),
+	})
+}
+
+// Handle the search, with the same query syntax and filters as the search page
+func (s *Service) SearchHandler(w http.ResponseWriter, r *http.Request) {
+
+	searchQuery := r.URL.Query().Get("q")
+	if searchQuery == "" {
+		s.writeError(w, r, http.StatusBadRequest, "the q query parameter is required")
+		return
+	}
+
+	cursor := r.URL.Query().Get("cursor")
+	if !validCursor(cursor) {
+		s.writeError(w, r, http.StatusBadRequest, "invalid cursor")
+		return
+	}
+
+	filters := models.ParseSearchFilters(r.URL.Query())
+
+	// The same key as the internal search API
+	redisKey := fmt.Sprintf("posts:search:%s", utils.EscapeTrancateString(searchQuery, 100))
+	redisKey += fmt.Sprintf(":filters:%s", filters.Key())
+	redisKey += fmt.Sprintf(":cursor:%s", cursor)
+
+	posts, err := rdb.GetCachedData(
+		r.Context(),
+		s.rdb,
+		redisKey,
+		s.config.CacheTimeout,
+		func(ctx context.Context) (models.Posts, error) {
+			return s.postsRepo.SearchWithFallback(ctx, searchQuery, filters, cursor)
+		},
+		rdb.WithTags(rdb.SearchTag),
+		rdb.WithSingleflight(),
+	)
+
+	if err != nil {
+		s.serverError(w, r, "failed to search posts in DB", err)
+		return
+	}
+
+	results := SearchResults{
+		Data:       s.newPosts(posts.Items),
+		Pagination: newPagination(posts.NextCursor),
+		Query:      searchQuery,
+		Corrected:  posts.Corrected,
+		Facets:     newFacets(posts.Facets),
+	}
+
+	if posts.Corrected {
+		results.Query = posts.Correction
+	}
+
+	if cursor == "" {
+		results.Total = posts.TotalNum
+	}
+
+	s.writeData(w, r, results)
+}
+
+// Handle a single page
+func (s *Service) PageHandler(w http.ResponseWriter, r *http.Request) {
+
+	slug := r.PathValue("slug")
+	page, err := rdb.GetCachedData(
+		r.Context(),
+		s.rdb,
+		fmt.Sprintf(pageCacheKey, slug),
+		s.config.CacheTimeout,
+		func(ctx context.Context) (models.Page, error) {
+			return s.pagesRepo.GetSinglePage(ctx, slug)
+		},
+	)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		s.writeError(w, r, http.StatusNotFound, "page not found")
+		return
+	}
+
+	if err != nil {
+		s.serverError(w, r, "failed to get the page from DB", err)
+		return
+	}
+
+	s.writeData(w, r, PageResponse{Data: s.newPage(page)})
+}
+
+// Serve the OpenAPI document
+func (s *Service) OpenAPIHandler(w http.ResponseWriter, r *http.Request) {
+	s.writeJSON(w, r, http.StatusOK, s.spec)
+}
@@ -0,0 +1,396 @@
+package api
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/vlatan/video-store/internal/models"
+)
+
+// The versioned API path prefix
+const Prefix = "/api/v1"
+
+// OpenAPI 3 document, only the parts this API uses
+type document struct {
+	OpenAPI    string                         `json:"openapi"`
+	Info       info                           `json:"info"`
+	Servers    []server                       `json:"servers"`
+	Paths      map[string]map[string]endpoint `json:"paths"`
+	Components components                     `json:"components"`
+}
+
+type info struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Version     string `json:"version"`
+}
+
+type server struct {
+	URL string `json:"url"`
+}
+
+type endpoint struct {
+	OperationID string              `json:"operationId"`
+	Summary     string              `json:"summary"`
+	Tags        []string            `json:"tags"`
+	Parameters  []parameter         `json:"parameters,omitempty"`
+	Responses   map[string]response `json:"responses"`
+}
+
+type parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required"`
+	Schema      *schema `json:"schema"`
+}
+
+type response struct {
+	Description string               `json:"description"`
+	Content     map[string]mediaType `json:"content,omitempty"`
+}
+
+type mediaType struct {
+	Schema *schema `json:"schema"`
+}
+
+type components struct {
+	Schemas map[string]*schema `json:"schemas"`
+}
+
+type schema struct {
+	Ref         string             `json:"$ref,omitempty"`
+	Type        string             `json:"type,omitempty"`
+	Format      string             `json:"format,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Enum        []any              `json:"enum,omitempty"`
+	Items       *schema            `json:"items,omitempty"`
+	Properties  map[string]*schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+}
+
+// API operation, the source of both the routes and the document
+type operation struct {
+	method   string
+	path     string // relative to the prefix
+	id       string
+	summary  string
+	tag      string
+	params   []parameter
+	response any // response envelope
+	notFound bool
+	handler  func(*Service, http.ResponseWriter, *http.Request)
+}
+
+// Query parameters of the posts lists
+var listParams = []parameter{
+	{
+		Name:        "cursor",
+		In:          "query",
+		Description: "The next_cursor of the previous page",
+		Schema:      &schema{Type: "string"},
+	},
+	{
+		Name:        "order_by",
+		In:          "query",
+		Description: "Newest first if not set",
+		Schema:      &schema{Type: "string", Enum: enum(orderOptions[1:])},
+	},
+}
+
+// Query parameters of the search
+var searchParams = []parameter{
+	{
+		Name:        "q",
+		In:          "query",
+		Description: `Search query, supports "phrases", -exclusions, title: and description: fields`,
+		Required:    true,
+		Schema:      &schema{Type: "string"},
+	},
+	listParams[0],
+	{Name: "category", In: "query", Description: "Category slug", Schema: &schema{Type: "string"}},
+	{Name: "source", In: "query", Description: "Source ID", Schema: &schema{Type: "string"}},
+	{
+		Name:   "duration",
+		In:     "query",
+		Schema: &schema{Type: "string", Enum: enum(durationNames())},
+	},
+	{Name: "year_from", In: "query", Schema: &schema{Type: "integer"}},
+	{Name: "year_to", In: "query", Schema: &schema{Type: "integer"}},
+	{
+		Name:        "min_rating",
+		In:          "query",
+		Description: "Minimum average rating",
+		Schema:      &schema{Type: "integer", Enum: enum(models.RatingThresholds)},
+	},
+}
+
+// Names of the video length buckets
+func durationNames() []string {
+	names := make([]string, len(models.DurationBuckets))
+	for i, bucket := range models.DurationBuckets {
+		names[i] = bucket.Name
+	}
+	return names
+}
+
+// Convert the values to a schema enum
+func enum[T any](values []T) []any {
+	result := make([]any, len(values))
+	for i, v := range values {
+		result[i] = v
+	}
+	return result
+}
+
+// pathParam describes a required path parameter
+func pathParam(name, description string) parameter {
+	return parameter{
+		Name:        name,
+		In:          "path",
+		Description: description,
+		Required:    true,
+		Schema:      &schema{Type: "string"},
+	}
+}
+
+// Routes maps the mux patterns of the API operations to their handlers
+func (s *Service) Routes() map[string]http.HandlerFunc {
+
+	routes := make(map[string]http.HandlerFunc, len(operations))
+	for _, op := range operations {
+		routes[op.method+" "+Prefix+op.path] = func(w http.ResponseWriter, r *http.Request) {
+			op.handler(s, w, r)
+		}
+	}
+
+	return routes
+}
+
+var operations = []operation{
+	{
+		method:   http.MethodGet,
+		path:     "/posts",
+		id:       "listPosts",
+		summary:  "List the latest videos",
+		tag:      "Videos",
+		params:   listParams,
+		response: PostList{},
+		handler:  (*Service).PostsHandler,
+	},
+	{
+		method:   http.MethodGet,
+		path:     "/posts/{id}",
+		id:       "getPost",
+		summary:  "Get a video with its related videos",
+		tag:      "Videos",
+		params:   []parameter{pathParam("id", "YouTube video ID")},
+		response: PostResponse{},
+		notFound: true,
+		handler:  (*Service).PostHandler,
+	},
+	{
+		method:   http.MethodGet,
+		path:     "/categories",
+		id:       "listCategories",
+		summary:  "List the categories",
+		tag:      "Categories",
+		response: CategoryList{},
+		handler:  (*Service).CategoriesHandler,
+	},
+	{
+		method:   http.MethodGet,
+		path:     "/categories/{slug}/posts",
+		id:       "listCategoryPosts",
+		summary:  "List the videos in a category",
+		tag:      "Categories",
+		params:   append([]parameter{pathParam("slug", "Category slug")}, listParams...),
+		response: PostList{},
+		notFound: true,
+		handler:  (*Service).CategoryPostsHandler,
+	},
+	{
+		method:   http.MethodGet,
+		path:     "/sources",
+		id:       "listSources",
+		summary:  "List the sources",
+		tag:      "Sources",
+		response: SourceList{},
+		handler:  (*Service).SourcesHandler,
+	},
+	{
+		method:   http.MethodGet,
+		path:     "/sources/{id}/posts",
+		id:       "listSourcePosts",
+		summary:  "List the videos in a source",
+		tag:      "Sources",
+		params:   append([]parameter{pathParam("id", "Source ID")}, listParams...),
+		response: PostList{},
+		notFound: true,
+		handler:  (*Service).SourcePostsHandler,
+	},
+	{
+		method:   http.MethodGet,
+		path:     "/search",
+		id:       "searchPosts",
+		summary:  "Search the videos",
+		tag:      "Search",
+		params:   searchParams,
+		response: SearchResults{},
+		handler:  (*Service).SearchHandler,
+	},
+	{
+		method:   http.MethodGet,
+		path:     "/pages/{slug}",
+		id:       "getPage",
+		summary:  "Get a page",
+		tag:      "Pages",
+		params:   []parameter{pathParam("slug", "Page slug")},
+		response: PageResponse{},
+		notFound: true,
+		handler:  (*Service).PageHandler,
+	},
+}
+
+// Generate the OpenAPI document out of the operations and the response types
+func newDocument(appName, baseURL string) document {
+
+	doc := document{
+		OpenAPI: "3.0.3",
+		Info: info{
+			Title:       appName + " API",
+			Description: "Read only access to the videos, categories, sources and pages.",
+			Version:     "1.0.0",
+		},
+		Servers:    []server{{URL: baseURL + Prefix}},
+		Paths:      make(map[string]map[string]endpoint),
+		Components: components{Schemas: make(map[string]*schema)},
+	}
+
+	errorResponse := func(description string) response {
+		return response{
+			Description: description,
+			Content: map[string]mediaType{
+				"application/json": {Schema: doc.Components.schemaOf(reflect.TypeFor[Error]())},
+			},
+		}
+	}
+
+	for _, op := range operations {
+
+		responses := map[string]response{
+			"200": {
+				Description: "OK",
+				Content: map[string]mediaType{
+					"application/json": {Schema: doc.Components.schemaOf(reflect.TypeOf(op.response))},
+				},
+			},
+			"500": errorResponse("Internal server error"),
+		}
+
+		if len(op.params) > 0 {
+			responses["400"] = errorResponse("Invalid parameters")
+		}
+
+		if op.notFound {
+			responses["404"] = errorResponse("Not found")
+		}
+
+		if doc.Paths[op.path] == nil {
+			doc.Paths[op.path] = make(map[string]endpoint)
+		}
+
+		doc.Paths[op.path][strings.ToLower(op.method)] = endpoint{
+			OperationID: op.id,
+			Summary:     op.summary,
+			Tags:        []string{op.tag},
+			Parameters:  op.params,
+			Responses:   responses,
+		}
+	}
+
+	return doc
+}
+
+// Get the schema of the type, the named structs
+// are added to the components and referenced
+func (c components) schemaOf(t reflect.Type) *schema {
+
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &schema{Type: "string"}
+	case reflect.Bool:
+		return &schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+		return &schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &schema{Type: "array", Items: c.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if t == reflect.TypeFor[time.Time]() {
+			return &schema{Type: "string", Format: "date-time"}
+		}
+	default:
+		return &schema{}
+	}
+
+	ref := &schema{Ref: "#/components/schemas/" + t.Name()}
+	if _, ok := c.Schemas[t.Name()]; ok {
+		return ref
+	}
+
+	// Reserve the name first, the struct might refer to itself
+	object := &schema{Type: "object", Properties: make(map[string]*schema)}
+	c.Schemas[t.Name()] = object
+	c.addFields(object, t)
+
+	return ref
+}
+
+// Add the struct's JSON fields to the object schema,
+// the fields of the embedded structs are promoted
+func (c components) addFields(object *schema, t reflect.Type) {
+
+	for field := range t.Fields() {
+
+		if !field.IsExported() {
+			continue
+		}
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			c.addFields(object, field.Type)
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		property := c.schemaOf(field.Type)
+
+		// The $ref siblings are ignored, describe only the inline schemas
+		if doc := field.Tag.Get("doc"); doc != "" && property.Ref == "" {
+			property.Description = doc
+		}
+
+		object.Properties[name] = property
+		if !strings.Contains(options, "omitempty") {
+			object.Required = append(object.Required, name)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestNewDocument(t *testing.T) {
+
+	doc := newDocument("My Video App", "https://example.com")
+
+	if _, err := json.Marshal(doc); err != nil {
+		t.Fatalf("failed to encode the document: %v", err)
+	}
+
+	if got, want := doc.Servers[0].URL, "https://example.com/api/v1"; got != want {
+		t.Errorf("got server %q, want %q", got, want)
+	}
+
+	// Every reference must resolve to a component schema
+	var checkRefs func(name string, s *schema)
+	checkRefs = func(name string, s *schema) {
+		if s == nil {
+			return
+		}
+		if s.Ref != "" {
+			if _, ok := doc.Components.Schemas[strings.TrimPrefix(s.Ref, "#/components/schemas/")]; !ok {
+				t.Errorf("%s: unresolved reference %q", name, s.Ref)
+			}
+		}
+		checkRefs(name, s.Items)
+		for property, p := range s.Properties {
+			checkRefs(name+"."+property, p)
+		}
+	}
+
+	for name, s := range doc.Components.Schemas {
+		checkRefs(name, s)
+	}
+
+	// Every path parameter must be described
+	pathParam := regexp.MustCompile(`\{(\w+)\}`)
+	for path, methods := range doc.Paths {
+		for method, e := range methods {
+			for _, match := range pathParam.FindAllStringSubmatch(path, -1) {
+				if !slices.ContainsFunc(e.Parameters, func(p parameter) bool {
+					return p.In == "path" && p.Name == match[1] && p.Required
+				}) {
+					t.Errorf("%s %s: undescribed path parameter %q", method, path, match[1])
+				}
+			}
+		}
+	}
+
+	tests := []struct {
+		schema   string
+		property string
+		ref      string // expected reference, if any
+		required bool
+	}{
+		{"Post", "id", "", true},
+		{"Post", "thumbnail", "#/components/schemas/Thumbnail", false},
+		{"PostDetail", "title", "", true},     // promoted from the embedded Post
+		{"PostDetail", "embed_url", "", true}, // own field
+		{"PostList", "data", "", true},        // array of posts
+		{"PostList", "pagination", "#/components/schemas/Pagination", true},
+		{"SearchResults", "facets", "#/components/schemas/Facets", false},
+		{"Error", "message", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.schema+"."+tt.property, func(t *testing.T) {
+
+			s, ok := doc.Components.Schemas[tt.schema]
+			if !ok {
+				t.Fatalf("missing schema %q", tt.schema)
+			}
+
+			p, ok := s.Properties[tt.property]
+			if !ok {
+				t.Fatalf("missing property %q", tt.property)
+			}
+
+			if p.Ref != tt.ref {
+				t.Errorf("got reference %q, want %q", p.Ref, tt.ref)
+			}
+
+			if got := slices.Contains(s.Required, tt.property); got != tt.required {
+				t.Errorf("got required %v, want %v", got, tt.required)
+			}
+		})
+	}
+}
+
+func TestRoutes(t *testing.T) {
+
+	routes := (&Service{}).Routes()
+	if len(routes) != len(operations) {
+		t.Fatalf("got %d routes, want %d", len(routes), len(operations))
+	}
+
+	// The patterns must be valid and must not conflict
+	mux := http.NewServeMux()
+	for pattern, handler := range routes {
+		mux.HandleFunc(pattern, handler)
+	}
+}
+
+func TestPostsCacheKey(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		orderBy string
+		cursor  string
+		want    string
+	}{
+		{"first page", "", "", "home:posts"},
+		{"ordered", "likes", "", "home:posts:likes"},
+		{"next page", "", "abc=", "home:posts:cursor:abc="},
+		{"ordered next page", "avg_rating", "abc=", "home:posts:avg_rating:cursor:abc="},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := postsCacheKey("home:posts", tt.orderBy, tt.cursor); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
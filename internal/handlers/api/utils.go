@@ -0,0 +1,124 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+
+	"github.com/vlatan/video-store/internal/models"
+)
+
+// The cache keys shared with the site handlers
+const (
+	postCacheKey         = "post:%s"
+	relatedPostsCacheKey = "post:%s:related_posts"
+	pageCacheKey         = "page:%s"
+)
+
+// Validate video ID
+var validVideoID = regexp.MustCompile("^([-a-zA-Z0-9_]{11})$")
+
+// Whitelisted order_by query parameter values
+var orderOptions = []string{"", models.Likes, models.AvgRating, models.RatingCount}
+
+// Get and validate the cursor and order_by query parameters of a posts list.
+// Writes the error response if invalid.
+func (s *Service) listParams(w http.ResponseWriter, r *http.Request) (string, string, bool) {
+
+	cursor := r.URL.Query().Get("cursor")
+	if !validCursor(cursor) {
+		s.writeError(w, r, http.StatusBadRequest, "invalid cursor")
+		return "", "", false
+	}
+
+	orderBy := r.URL.Query().Get("order_by")
+	for _, option := range orderOptions {
+		if orderBy == option {
+			return cursor, orderBy, true
+		}
+	}
+
+	s.writeError(w, r, http.StatusBadRequest, fmt.Sprintf("invalid order_by %q", orderBy))
+	return "", "", false
+}
+
+// The cursors are opaque to the clients, but at least must be base64
+func validCursor(cursor string) bool {
+	_, err := base64.StdEncoding.DecodeString(cursor)
+	return err == nil
+}
+
+// Construct the posts list Redis key the same way the site does
+func postsCacheKey(prefix, orderBy, cursor string) string {
+
+	key := prefix
+	if orderBy != "" {
+		key += ":" + orderBy
+	}
+
+	if cursor != "" {
+		key += ":cursor:" + cursor
+	}
+
+	return key
+}
+
+// Write the response envelope as JSON
+func (s *Service) writeData(w http.ResponseWriter, r *http.Request, data any) {
+
+	body, err := json.Marshal(data)
+	if err != nil {
+		s.serverError(w, r, "failed to encode JSON response", err)
+		return
+	}
+
+	s.writeJSON(w, r, http.StatusOK, body)
+}
+
+// Write the error object as JSON
+func (s *Service) writeError(w http.ResponseWriter, r *http.Request, status int, message string) {
+
+	body, err := json.Marshal(Error{
+		Error:   http.StatusText(status),
+		Code:    status,
+		Message: message,
+	})
+
+	// Let the error middleware write the generic error
+	if err != nil {
+		w.WriteHeader(status)
+		return
+	}
+
+	s.writeJSON(w, r, status, body)
+}
+
+// Log the error and write the internal server error object
+func (s *Service) serverError(w http.ResponseWriter, r *http.Request, msg string, err error) {
+	slog.ErrorContext(
+		r.Context(), msg,
+		"path", r.URL.Path,
+		"error", err,
+	)
+	s.writeError(w, r, http.StatusInternalServerError, "")
+}
+
+// Write the JSON body, any site can read the public API
+func (s *Service) writeJSON(w http.ResponseWriter, r *http.Request, status int, body []byte) {
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.WriteHeader(status)
+
+	if _, err := w.Write(body); err != nil {
+		// Too late for recovery here, just log the error
+		slog.ErrorContext(
+			r.Context(), "failed to write data to response",
+			"path", r.URL.Path,
+			"error", err,
+		)
+	}
+}
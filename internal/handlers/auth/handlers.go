@@ -1,7 +1,6 @@
 package auth
 
 import (
-	"log"
 	"log/slog"
 	"net/http"
 
@@ -36,7 +35,7 @@ func (s *Service) AuthHandler(w http.ResponseWriter, r *http.Request) {
 	// Generate the state
 	state, err := s.providers.GenerateState()
 	if err != nil {
-		log.Printf("failed to generate an oauth state; %v", err)
+		slog.ErrorContext(r.Context(), "failed to generate an oauth state", "error", err)
 		utils.HttpError(w, http.StatusInternalServerError)
 		return
 	}
@@ -63,7 +62,7 @@ func (s *Service) AuthHandler(w http.ResponseWriter, r *http.Request) {
 
 	// Save the session
 	if err = session.Save(r, w); err != nil {
-		log.Printf("failed to save the state/verifier session; %v", err)
+		slog.ErrorContext(r.Context(), "failed to save the state/verifier session", "error", err)
 		utils.HttpError(w, http.StatusInternalServerError)
 		return
 	}
@@ -72,7 +71,7 @@ func (s *Service) AuthHandler(w http.ResponseWriter, r *http.Request) {
 	redirectSession, _ := s.store.Get(r, s.config.RedirectSessionName)
 	redirectSession.Values["redirect"] = redirectTo.String()
 	if err = redirectSession.Save(r, w); err != nil {
-		log.Printf("failed to save the redirect session; %v", err)
+		slog.ErrorContext(r.Context(), "failed to save the redirect session", "error", err)
 	}
 
 	// Redirect the user to the Provider consent page
@@ -134,7 +133,7 @@ func (s *Service) AuthCallbackHandler(w http.ResponseWriter, r *http.Request) {
 	// Delete the session
 	session.Options.MaxAge = -1
 	if err := session.Save(r, w); err != nil {
-		log.Printf("failed to delete the oauth state/verifier sesssion; %v", err)
+		slog.ErrorContext(r.Context(), "failed to delete the oauth state/verifier session", "error", err)
 	}
 
 	// Check the state parameter
@@ -200,7 +199,7 @@ func (s *Service) AuthCallbackHandler(w http.ResponseWriter, r *http.Request) {
 
 	// Save user into our session
 	if err = s.loginUser(w, r, user); err != nil {
-		log.Printf("Error logging in the user: %v", err)
+		slog.ErrorContext(r.Context(), "failed to log in the user", "error", err)
 		s.ui.StoreFlashMessage(w, r, &failedLogin)
 		redirect.Execute(w, r, redirectTo, http.StatusSeeOther)
 		return
@@ -220,7 +219,7 @@ func (s *Service) LogoutHandler(w http.ResponseWriter, r *http.Request) {
 
 	// Remove user's session
 	if err := s.logoutUser(w, r); err != nil {
-		log.Printf("Error loging out the user: %v", err)
+		slog.ErrorContext(r.Context(), "failed to log out the user", "error", err)
 		s.ui.StoreFlashMessage(w, r, &failedLogout)
 		redirect.Execute(w, r, redirectTo, http.StatusSeeOther)
 		return
@@ -244,9 +243,15 @@ func (s *Service) DeleteAccountHandler(w http.ResponseWriter, r *http.Request) {
 	// Get the current user
 	currentUser := models.GetUserFromContext(r)
 
+	// A leaked token must not be able to delete the account
+	if currentUser.IsTokenAuth() {
+		utils.HttpError(w, http.StatusForbidden)
+		return
+	}
+
 	// Remove user session
 	if err := s.logoutUser(w, r); err != nil {
-		log.Printf("Error loging out the user: %v", err)
+		slog.ErrorContext(r.Context(), "failed to log out the user", "error", err)
 		s.ui.StoreFlashMessage(w, r, &failedDeleteAccount)
 		redirect.Execute(w, r, redirectTo, http.StatusFound)
 		return
@@ -284,13 +289,13 @@ func (s *Service) DeleteAccountHandler(w http.ResponseWriter, r *http.Request) {
 	if err = currentUser.DeleteAvatar(
 		r.Context(), s.config, s.rdb, s.r2s,
 	); err != nil {
-		log.Printf("Failed to delete user avatar: %v", err)
+		slog.ErrorContext(r.Context(), "failed to delete the user avatar", "error", err)
 	}
 
 	// Attempt to send revoke request
 	if currentUser.AccessToken != "" {
 		if err := s.revokeLogin(r.Context(), currentUser); err != nil {
-			log.Printf("Failed to delete/revoke app authorization: %v", err)
+			slog.ErrorContext(r.Context(), "failed to revoke the app authorization", "error", err)
 		}
 	}
 
@@ -5,7 +5,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
 	"log/slog"
 	"net/http"
 	"strings"
@@ -100,7 +99,7 @@ func (s *Service) getRedirectFromSession(w http.ResponseWriter, r *http.Request)
 	session.Options.MaxAge = -1
 	session.Values = make(map[any]any)
 	if err := session.Save(r, w); err != nil {
-		log.Printf("failed to delete the redirect session; %v", err)
+		slog.ErrorContext(r.Context(), "failed to delete the redirect session", "error", err)
 	}
 	return redirectTo
 }
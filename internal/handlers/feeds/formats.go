@@ -0,0 +1,373 @@
+package feeds
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"html/template"
+	"time"
+
+	"github.com/vlatan/video-store/internal/models"
+	"github.com/vlatan/video-store/internal/utils"
+)
+
+// Feed formats by their file name
+const (
+	RSS      = "feed.xml"
+	Atom     = "atom.xml"
+	JSONFeed = "feed.json"
+)
+
+// The feed formats in the order they are advertised
+var Formats = []string{RSS, Atom, JSONFeed}
+
+// Media types of the formats
+var mediaTypes = map[string]string{
+	RSS:      "application/rss+xml",
+	Atom:     "application/atom+xml",
+	JSONFeed: "application/feed+json",
+}
+
+// Format independent feed
+type feed struct {
+	title       string
+	description string
+	link        string // the HTML page of the feed
+	self        string // the feed itself
+	author      string
+	updated     time.Time
+	items       []item
+}
+
+type item struct {
+	title     string
+	link      string
+	summary   template.HTML
+	category  string
+	thumbnail *models.Thumbnail
+	published time.Time
+	updated   time.Time
+}
+
+// Create the feed out of the posts, the newest update is the feed update
+func newFeed(title, description, baseURL, path, format string, posts []models.Post) feed {
+
+	f := feed{
+		title:       title,
+		description: description,
+		link:        baseURL + path,
+		self:        baseURL + path + format,
+		author:      title,
+		items:       make([]item, 0, len(posts)),
+	}
+
+	for _, post := range posts {
+
+		it := item{
+			title:     post.GetTitle(),
+			link:      baseURL + "/video/" + post.VideoID + "/",
+			thumbnail: post.Thumbnail,
+		}
+
+		// A broken summary still leaves the rest of the item
+		if html, err := utils.ParseMarkdown(post.Summary); err == nil {
+			it.summary = html
+		}
+
+		if post.Category != nil {
+			it.category = post.Category.Name
+		}
+
+		if post.UploadDate != nil {
+			it.published = post.UploadDate.UTC()
+		}
+
+		it.updated = it.published
+		if post.UpdatedAt != nil {
+			it.updated = post.UpdatedAt.UTC()
+		}
+
+		if it.updated.After(f.updated) {
+			f.updated = it.updated
+		}
+
+		f.items = append(f.items, it)
+	}
+
+	return f
+}
+
+// Encode the feed in the format
+func (f feed) encode(format string) ([]byte, error) {
+
+	switch format {
+	case Atom:
+		return marshalXML(f.atom())
+	case JSONFeed:
+		return json.Marshal(f.jsonFeed())
+	default:
+		return marshalXML(f.rss())
+	}
+}
+
+// Encode the XML with the declaration
+func marshalXML(v any) ([]byte, error) {
+	body, err := xml.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
+
+// RSS 2.0 with the Media RSS thumbnails
+// https://www.rssboard.org/rss-specification
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	MediaNS string     `xml:"xmlns:media,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Self          atomLink  `xml:"atom:link"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string          `xml:"title"`
+	Link        string          `xml:"link"`
+	GUID        rssGUID         `xml:"guid"`
+	PubDate     string          `xml:"pubDate,omitempty"`
+	Description string          `xml:"description,omitempty"`
+	Category    string          `xml:"category,omitempty"`
+	Enclosure   *rssEnclosure   `xml:"enclosure"`
+	Thumbnail   *mediaThumbnail `xml:"media:thumbnail"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type rssEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Length int    `xml:"length,attr"` // unknown, zero as recommended
+	Type   string `xml:"type,attr"`
+}
+
+type mediaThumbnail struct {
+	URL    string `xml:"url,attr"`
+	Width  int64  `xml:"width,attr,omitempty"`
+	Height int64  `xml:"height,attr,omitempty"`
+}
+
+func (f feed) rss() rssFeed {
+
+	channel := rssChannel{
+		Title:       f.title,
+		Link:        f.link,
+		Description: f.description,
+		Self:        atomLink{Href: f.self, Rel: "self", Type: mediaTypes[RSS]},
+		Items:       make([]rssItem, len(f.items)),
+	}
+
+	if !f.updated.IsZero() {
+		channel.LastBuildDate = f.updated.Format(time.RFC1123Z)
+	}
+
+	for i, it := range f.items {
+
+		channel.Items[i] = rssItem{
+			Title:       it.title,
+			Link:        it.link,
+			GUID:        rssGUID{IsPermaLink: true, Value: it.link},
+			Description: string(it.summary),
+			Category:    it.category,
+		}
+
+		if !it.published.IsZero() {
+			channel.Items[i].PubDate = it.published.Format(time.RFC1123Z)
+		}
+
+		if thumb := newMediaThumbnail(it.thumbnail); thumb != nil {
+			channel.Items[i].Thumbnail = thumb
+			channel.Items[i].Enclosure = &rssEnclosure{URL: thumb.URL, Type: "image/jpeg"}
+		}
+	}
+
+	return rssFeed{
+		Version: "2.0",
+		AtomNS:  "http://www.w3.org/2005/Atom",
+		MediaNS: "http://search.yahoo.com/mrss/",
+		Channel: channel,
+	}
+}
+
+// Atom 1.0 with the Media RSS thumbnails
+// https://www.rfc-editor.org/rfc/rfc4287
+
+type atomFeed struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	MediaNS  string      `xml:"xmlns:media,attr"`
+	ID       string      `xml:"id"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	Updated  string      `xml:"updated"`
+	Author   atomAuthor  `xml:"author"`
+	Links    []atomLink  `xml:"link"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomEntry struct {
+	ID        string          `xml:"id"`
+	Title     string          `xml:"title"`
+	Link      atomLink        `xml:"link"`
+	Published string          `xml:"published,omitempty"`
+	Updated   string          `xml:"updated"`
+	Summary   *atomText       `xml:"summary"`
+	Category  *atomCategory   `xml:"category"`
+	Thumbnail *mediaThumbnail `xml:"media:thumbnail"`
+}
+
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+func (f feed) atom() atomFeed {
+
+	a := atomFeed{
+		MediaNS:  "http://search.yahoo.com/mrss/",
+		ID:       f.link,
+		Title:    f.title,
+		Subtitle: f.description,
+		Updated:  f.updated.Format(time.RFC3339),
+		Author:   atomAuthor{Name: f.author},
+		Links: []atomLink{
+			{Href: f.link, Rel: "alternate", Type: "text/html"},
+			{Href: f.self, Rel: "self", Type: mediaTypes[Atom]},
+		},
+		Entries: make([]atomEntry, len(f.items)),
+	}
+
+	for i, it := range f.items {
+
+		a.Entries[i] = atomEntry{
+			ID:        it.link,
+			Title:     it.title,
+			Link:      atomLink{Href: it.link, Rel: "alternate", Type: "text/html"},
+			Updated:   it.updated.Format(time.RFC3339),
+			Thumbnail: newMediaThumbnail(it.thumbnail),
+		}
+
+		if !it.published.IsZero() {
+			a.Entries[i].Published = it.published.Format(time.RFC3339)
+		}
+
+		if it.summary != "" {
+			a.Entries[i].Summary = &atomText{Type: "html", Value: string(it.summary)}
+		}
+
+		if it.category != "" {
+			a.Entries[i].Category = &atomCategory{Term: it.category}
+		}
+	}
+
+	return a
+}
+
+// JSON Feed 1.1
+// https://www.jsonfeed.org/version/1.1/
+
+type jsonFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageURL string         `json:"home_page_url"`
+	FeedURL     string         `json:"feed_url"`
+	Description string         `json:"description,omitempty"`
+	Authors     []jsonAuthor   `json:"authors"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+type jsonAuthor struct {
+	Name string `json:"name"`
+}
+
+type jsonFeedItem struct {
+	ID            string     `json:"id"`
+	URL           string     `json:"url"`
+	Title         string     `json:"title"`
+	ContentHTML   string     `json:"content_html"`
+	Image         string     `json:"image,omitempty"`
+	DatePublished *time.Time `json:"date_published,omitempty"`
+	DateModified  *time.Time `json:"date_modified,omitempty"`
+	Tags          []string   `json:"tags,omitempty"`
+}
+
+func (f feed) jsonFeed() jsonFeed {
+
+	j := jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       f.title,
+		HomePageURL: f.link,
+		FeedURL:     f.self,
+		Description: f.description,
+		Authors:     []jsonAuthor{{Name: f.author}},
+		Items:       make([]jsonFeedItem, len(f.items)),
+	}
+
+	for i, it := range f.items {
+
+		j.Items[i] = jsonFeedItem{
+			ID:          it.link,
+			URL:         it.link,
+			Title:       it.title,
+			ContentHTML: string(it.summary),
+		}
+
+		if it.thumbnail != nil {
+			j.Items[i].Image = it.thumbnail.Url
+		}
+
+		if !it.published.IsZero() {
+			j.Items[i].DatePublished = &it.published
+		}
+
+		if !it.updated.IsZero() {
+			j.Items[i].DateModified = &it.updated
+		}
+
+		if it.category != "" {
+			j.Items[i].Tags = []string{it.category}
+		}
+	}
+
+	return j
+}
+
+// Convert the thumbnail, nil if there's no image
+func newMediaThumbnail(thumb *models.Thumbnail) *mediaThumbnail {
+	if thumb == nil || thumb.Url == "" {
+		return nil
+	}
+	return &mediaThumbnail{URL: thumb.Url, Width: thumb.Width, Height: thumb.Height}
+}
@@ -0,0 +1,118 @@
+package feeds
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vlatan/video-store/internal/models"
+)
+
+func TestNewFeed(t *testing.T) {
+
+	older := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	posts := []models.Post{
+		{
+			VideoID:    "abc",
+			Title:      "First",
+			Summary:    "**bold** summary",
+			Category:   &models.Category{Slug: "music", Name: "Music"},
+			Thumbnail:  &models.Thumbnail{Url: "https://img.example.com/abc.jpg", Width: 320, Height: 180},
+			UploadDate: &older,
+			UpdatedAt:  &newer,
+		},
+		{VideoID: "def", Title: "Second", UploadDate: &older},
+	}
+
+	f := newFeed("Music", "", "https://example.com", "/category/music/", RSS, posts)
+
+	if !f.updated.Equal(newer) {
+		t.Errorf("got updated %v, want %v", f.updated, newer)
+	}
+
+	if got, want := f.self, "https://example.com/category/music/feed.xml"; got != want {
+		t.Errorf("got self %q, want %q", got, want)
+	}
+
+	if got, want := f.items[0].link, "https://example.com/video/abc/"; got != want {
+		t.Errorf("got link %q, want %q", got, want)
+	}
+
+	if !strings.Contains(string(f.items[0].summary), "<strong>bold</strong>") {
+		t.Errorf("the summary %q is not rendered", f.items[0].summary)
+	}
+
+	// An item without an update is last updated when uploaded
+	if !f.items[1].updated.Equal(older) {
+		t.Errorf("got item updated %v, want %v", f.items[1].updated, older)
+	}
+}
+
+func TestEncode(t *testing.T) {
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	posts := []models.Post{{
+		VideoID:    "abc",
+		Title:      "Title & <more>",
+		Summary:    "Summary",
+		Thumbnail:  &models.Thumbnail{Url: "https://img.example.com/abc.jpg"},
+		UploadDate: &now,
+	}}
+
+	tests := []struct {
+		format   string
+		contains []string
+	}{
+		{RSS, []string{
+			`<rss version="2.0"`,
+			`<atom:link href="https://example.com/feed.xml" rel="self"`,
+			`<media:thumbnail url="https://img.example.com/abc.jpg"`,
+			`<enclosure url="https://img.example.com/abc.jpg" length="0" type="image/jpeg"`,
+			`<pubDate>Wed, 01 Jan 2025 00:00:00 +0000</pubDate>`,
+			`Title &amp; &lt;more&gt;`,
+		}},
+		{Atom, []string{
+			`<feed xmlns="http://www.w3.org/2005/Atom"`,
+			`<link href="https://example.com/atom.xml" rel="self"`,
+			`<updated>2025-01-01T00:00:00Z</updated>`,
+			`<summary type="html">&lt;p&gt;Summary&lt;/p&gt;`,
+		}},
+		{JSONFeed, []string{
+			`"version":"https://jsonfeed.org/version/1.1"`,
+			`"feed_url":"https://example.com/feed.json"`,
+			`"image":"https://img.example.com/abc.jpg"`,
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+
+			body, err := newFeed("App", "", "https://example.com", "/", tt.format, posts).encode(tt.format)
+			if err != nil {
+				t.Fatalf("failed to encode: %v", err)
+			}
+
+			// The output must be well formed
+			var v any
+			if tt.format == JSONFeed {
+				err = json.Unmarshal(body, &v)
+			} else {
+				err = xml.Unmarshal(body, new(struct{}))
+			}
+
+			if err != nil {
+				t.Fatalf("malformed feed: %v", err)
+			}
+
+			for _, s := range tt.contains {
+				if !strings.Contains(string(body), s) {
+					t.Errorf("missing %q in\n%s", s, body)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package feeds
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/vlatan/video-store/internal/drivers/rdb"
+	"github.com/vlatan/video-store/internal/models"
+	"github.com/vlatan/video-store/internal/utils"
+)
+
+// Handle the feed of the latest posts
+func (s *Service) HomeFeedHandler(format string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		posts, err := s.getFeedPosts(r.Context(), "feed:home", rdb.HomeTag,
+			func(ctx context.Context) (models.Posts, error) {
+				return s.postsRepo.GetHomePosts(ctx, "", "")
+			},
+		)
+
+		if err != nil {
+			s.feedError(w, r, err)
+			return
+		}
+
+		s.serveFeed(w, r, format, newFeed(
+			s.config.AppName,
+			s.config.AppDescription,
+			s.baseURL, "/", format,
+			posts.Items,
+		))
+	}
+}
+
+// Handle the feed of the latest posts in a category
+func (s *Service) CategoryFeedHandler(format string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		slug := r.PathValue("category")
+		posts, err := s.getFeedPosts(r.Context(), fmt.Sprintf("feed:category:%s", slug), rdb.CategoryTag(slug),
+			func(ctx context.Context) (models.Posts, error) {
+				return s.postsRepo.GetCategoryPosts(ctx, slug, "", "")
+			},
+		)
+
+		if err != nil {
+			s.feedError(w, r, err)
+			return
+		}
+
+		if len(posts.Items) == 0 {
+			http.NotFound(w, r)
+			return
+		}
+
+		s.serveFeed(w, r, format, newFeed(
+			fmt.Sprintf("%s - %s", posts.Title, s.config.AppName),
+			fmt.Sprintf("The latest %s videos on %s", posts.Title, s.config.AppName),
+			s.baseURL, fmt.Sprintf("/category/%s/", slug), format,
+			posts.Items,
+		))
+	}
+}
+
+// Handle the feed of the latest posts from a source
+func (s *Service) SourceFeedHandler(format string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		sourceID := r.PathValue("source")
+		posts, err := s.getFeedPosts(r.Context(), fmt.Sprintf("feed:source:%s", sourceID), rdb.SourceTag(sourceID),
+			func(ctx context.Context) (models.Posts, error) {
+				return s.postsRepo.GetSourcePosts(ctx, sourceID, "", "")
+			},
+		)
+
+		if err != nil {
+			s.feedError(w, r, err)
+			return
+		}
+
+		if len(posts.Items) == 0 {
+			http.NotFound(w, r)
+			return
+		}
+
+		if sourceID == "other" {
+			posts.Title = "Other Uploads"
+		}
+
+		s.serveFeed(w, r, format, newFeed(
+			fmt.Sprintf("%s - %s", posts.Title, s.config.AppName),
+			fmt.Sprintf("The latest videos from %s on %s", posts.Title, s.config.AppName),
+			s.baseURL, fmt.Sprintf("/source/%s/", sourceID), format,
+			posts.Items,
+		))
+	}
+}
+
+// Get the first page of the list with the feed details from Redis or DB.
+// The same posts serve all the formats, the tag is the one of the list.
+func (s *Service) getFeedPosts(
+	ctx context.Context,
+	redisKey, tag string,
+	list func(context.Context) (models.Posts, error),
+) (models.Posts, error) {
+
+	return rdb.GetCachedData(
+		ctx,
+		s.rdb,
+		redisKey,
+		s.config.CacheTimeout,
+		func(ctx context.Context) (models.Posts, error) {
+
+			posts, err := list(ctx)
+			if err != nil {
+				return posts, err
+			}
+
+			err = s.postsRepo.AddFeedDetails(ctx, posts.Items)
+			return posts, err
+		},
+		rdb.WithTags(tag),
+		rdb.WithSingleflight(),
+		rdb.WithLock(),
+	)
+}
+
+// Encode and serve the feed.
+// ServeContent answers the conditional requests using
+// the Last-Modified and the ETag made out of the body.
+func (s *Service) serveFeed(w http.ResponseWriter, r *http.Request, format string, f feed) {
+
+	body, err := f.encode(format)
+	if err != nil {
+		s.feedError(w, r, err)
+		return
+	}
+
+	hash := sha256.Sum256(body)
+	w.Header().Set("Content-Type", mediaTypes[format]+"; charset=utf-8")
+	w.Header().Set("ETag", `"`+hex.EncodeToString(hash[:16])+`"`)
+	http.ServeContent(w, r, "", f.updated, bytes.NewReader(body))
+}
+
+// Log the error and respond with internal server error
+func (s *Service) feedError(w http.ResponseWriter, r *http.Request, err error) {
+	slog.ErrorContext(
+		r.Context(), "failed to get the feed",
+		"path", r.URL.Path,
+		"error", err,
+	)
+	utils.HttpError(w, http.StatusInternalServerError)
+}
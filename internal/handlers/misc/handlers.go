@@ -40,6 +40,18 @@ func (s *Service) TextHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// Serve the IndexNow key, the search engines verify the submissions with it
+func (s *Service) IndexNowKeyHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if _, err := w.Write([]byte(s.config.IndexNowKey)); err != nil {
+		slog.ErrorContext(
+			r.Context(), "failed to write response",
+			"path", r.URL.Path,
+			"error", err,
+		)
+	}
+}
+
 // DB and Redis health status
 // Wrap this with middlware that allows only admins
 func (s *Service) HealthAPI(w http.ResponseWriter, r *http.Request) {
@@ -1,6 +1,7 @@
 package pages
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log/slog"
@@ -40,8 +41,8 @@ func (s *Service) SinglePageHandler(w http.ResponseWriter, r *http.Request) {
 			s.rdb,
 			fmt.Sprintf(pageCacheKey, pageSlug),
 			s.config.CacheTimeout,
-			func() (models.Page, error) {
-				return s.pagesRepo.GetSinglePage(r.Context(), pageSlug)
+			func(ctx context.Context) (models.Page, error) {
+				return s.pagesRepo.GetSinglePage(ctx, pageSlug)
 			},
 		)
 	}
@@ -169,6 +170,9 @@ func (s *Service) UpdatePageHandler(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		// Let the search engines know about the change
+		s.indexNow.Queue(r.Context(), fmt.Sprintf("/page/%s/", slug))
+
 		// Check out the updated page
 		redirectURL := fmt.Sprintf("/page/%s/", slug)
 		redirectTo := redirect.Sanitize(redirectURL, auth.IsProtectedRoute)
@@ -243,6 +247,9 @@ func (s *Service) NewPageHandler(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		// Let the search engines know about the new page
+		s.indexNow.Queue(r.Context(), fmt.Sprintf("/page/%s/", pageSlug))
+
 		// Check out the updated page
 		redirectURL := fmt.Sprintf("/page/%s/", pageSlug)
 		redirectTo := redirect.Sanitize(redirectURL, auth.IsProtectedRoute)
@@ -277,6 +284,9 @@ func (s *Service) DeletePageHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Let the search engines know the page is gone
+	s.indexNow.Queue(r.Context(), fmt.Sprintf("/page/%s/", pageSlug))
+
 	successDelete := models.FlashMessage{
 		Message:  "The page has been deleted!",
 		Category: "info",
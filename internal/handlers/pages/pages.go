@@ -3,6 +3,7 @@ package pages
 import (
 	"github.com/vlatan/video-store/internal/config"
 	"github.com/vlatan/video-store/internal/drivers/rdb"
+	"github.com/vlatan/video-store/internal/integrations/indexnow"
 	pagesRepo "github.com/vlatan/video-store/internal/repositories/pages"
 	"github.com/vlatan/video-store/internal/ui"
 )
@@ -12,6 +13,7 @@ type Service struct {
 	rdb       *rdb.Service
 	ui        ui.Service
 	config    *config.Config
+	indexNow  *indexnow.Service
 }
 
 func New(
@@ -19,11 +21,13 @@ func New(
 	rdb *rdb.Service,
 	ui ui.Service,
 	config *config.Config,
+	indexNow *indexnow.Service,
 ) *Service {
 	return &Service{
 		pagesRepo: pagesRepo,
 		rdb:       rdb,
 		ui:        ui,
 		config:    config,
+		indexNow:  indexNow,
 	}
 }
@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/vlatan/video-store/internal/drivers/rdb"
 	"github.com/vlatan/video-store/internal/utils"
 )
 
@@ -25,7 +26,11 @@ func (s *Service) handleLike(w http.ResponseWriter, r *http.Request, userID int,
 
 	if rowsAffected == 0 {
 		http.NotFound(w, r)
+		return
 	}
+
+	// The cached post and the lists it's in show the likes
+	s.invalidateCache(r.Context(), rdb.PostTag(videoID))
 }
 
 // Handle a post unlike from user
@@ -44,7 +49,11 @@ func (s *Service) handleUnlike(w http.ResponseWriter, r *http.Request, userID in
 
 	if rowsAffected == 0 {
 		http.NotFound(w, r)
+		return
 	}
+
+	// The cached post and the lists it's in show the likes
+	s.invalidateCache(r.Context(), rdb.PostTag(videoID))
 }
 
 // Handle a post favorite from user
@@ -106,5 +115,8 @@ func (s *Service) handleRate(w http.ResponseWriter, r *http.Request, rating, use
 		return
 	}
 
+	// The cached post and the lists it's in show the rating
+	s.invalidateCache(r.Context(), rdb.PostTag(videoID))
+
 	s.ui.WriteJSON(w, r, ratingData)
 }
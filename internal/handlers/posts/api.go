@@ -1,11 +1,14 @@
 package posts
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"net/url"
 	"slices"
+	"unicode/utf8"
 
 	"github.com/vlatan/video-store/internal/drivers/rdb"
 	"github.com/vlatan/video-store/internal/models"
@@ -56,11 +59,13 @@ func (s *Service) HomeAPI(w http.ResponseWriter, r *http.Request) {
 			s.rdb,
 			redisKey,
 			s.config.CacheTimeout,
-			func() (models.Posts, error) {
+			func(ctx context.Context) (models.Posts, error) {
 				return s.postsRepo.GetHomePosts(
-					r.Context(), cursor, orderBy,
+					ctx, cursor, orderBy,
 				)
 			},
+			rdb.WithTags(rdb.HomeTag),
+			rdb.WithSingleflight(),
 		)
 	}
 
@@ -124,11 +129,13 @@ func (s *Service) CategoryPostsAPI(w http.ResponseWriter, r *http.Request) {
 			s.rdb,
 			redisKey,
 			s.config.CacheTimeout,
-			func() (models.Posts, error) {
+			func(ctx context.Context) (models.Posts, error) {
 				return s.postsRepo.GetCategoryPosts(
-					r.Context(), slug, cursor, orderBy,
+					ctx, slug, cursor, orderBy,
 				)
 			},
+			rdb.WithTags(rdb.CategoryTag(slug)),
+			rdb.WithSingleflight(),
 		)
 	}
 
@@ -163,10 +170,14 @@ func (s *Service) SearchPostsAPI(w http.ResponseWriter, r *http.Request) {
 	// Get the cursor if any
 	cursor := r.URL.Query().Get("cursor")
 
+	// Get the normalized filters if any
+	filters := models.ParseSearchFilters(r.URL.Query())
+
 	encodedSearchQuery := utils.EscapeTrancateString(searchQuery, 100)
 
 	// Construct the Redis key
 	redisKey := fmt.Sprintf("posts:search:%s", encodedSearchQuery)
+	redisKey += fmt.Sprintf(":filters:%s", filters.Key())
 	redisKey += fmt.Sprintf(":cursor:%s", cursor)
 
 	// Get current user
@@ -179,20 +190,18 @@ func (s *Service) SearchPostsAPI(w http.ResponseWriter, r *http.Request) {
 
 	// Don't cache the search results only for the admin
 	if currentUser.IsAdmin() {
-		posts, err = s.postsRepo.SearchPosts(
-			r.Context(), searchQuery, s.config.PostsPerPage, cursor,
-		)
+		posts, err = s.postsRepo.SearchWithFallback(r.Context(), searchQuery, filters, cursor)
 	} else {
 		posts, err = rdb.GetCachedData(
 			r.Context(),
 			s.rdb,
 			redisKey,
 			s.config.CacheTimeout,
-			func() (models.Posts, error) {
-				return s.postsRepo.SearchPosts(
-					r.Context(), searchQuery, s.config.PostsPerPage, cursor,
-				)
+			func(ctx context.Context) (models.Posts, error) {
+				return s.postsRepo.SearchWithFallback(ctx, searchQuery, filters, cursor)
 			},
+			rdb.WithTags(rdb.SearchTag),
+			rdb.WithSingleflight(),
 		)
 	}
 
@@ -209,6 +218,97 @@ func (s *Service) SearchPostsAPI(w http.ResponseWriter, r *http.Request) {
 	s.ui.WriteJSON(w, r, posts)
 }
 
+// Handle the search suggestions while the user types in the searchform
+func (s *Service) SearchSuggestionsAPI(w http.ResponseWriter, r *http.Request) {
+
+	// Normalize the typed phrase so the similar requests hit the same cache
+	searchQuery := utils.NormalizeQuery(r.URL.Query().Get("q"), maxSuggestLength)
+
+	// Too short to suggest anything meaningful
+	if utf8.RuneCountInString(searchQuery) < minSuggestLength {
+		s.ui.WriteJSON(w, r, models.Suggestions{})
+		return
+	}
+
+	// Construct the Redis key
+	redisKey := fmt.Sprintf("posts:suggest:%s", utils.EscapeTrancateString(searchQuery, 200))
+
+	suggestions, err := rdb.GetCachedData(
+		r.Context(),
+		s.rdb,
+		redisKey,
+		s.config.SuggestCacheTimeout,
+		func(ctx context.Context) (models.Suggestions, error) {
+			return s.postsRepo.SearchSuggestions(
+				ctx, searchQuery, suggestPosts, suggestTaxonomy,
+			)
+		},
+		rdb.WithTags(rdb.SearchTag),
+		rdb.WithSingleflight(),
+	)
+
+	if err != nil {
+		slog.ErrorContext(
+			r.Context(), "failed to get search suggestions from DB",
+			"path", r.URL.Path,
+			"error", err,
+		)
+		utils.HttpError(w, http.StatusInternalServerError)
+		return
+	}
+
+	// Let the browser reuse the suggestions while the user retypes
+	if !models.GetUserFromContext(r).IsAuthenticated() {
+		w.Header().Set("Cache-Control", "public, max-age=60")
+	}
+
+	s.ui.WriteJSON(w, r, suggestions)
+}
+
+// Record a click on a post from the search results
+func (s *Service) SearchClickAPI(w http.ResponseWriter, r *http.Request) {
+
+	var data struct {
+		Query   string `json:"query"`
+		Filters string `json:"filters"` // the search URL query, the filters are parsed out of it
+		VideoID string `json:"video_id"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		utils.HttpError(w, http.StatusBadRequest)
+		return
+	}
+
+	query := utils.NormalizeQuery(data.Query, maxQueryLength)
+	if query == "" || validVideoID.FindStringSubmatch(data.VideoID) == nil {
+		utils.HttpError(w, http.StatusBadRequest)
+		return
+	}
+
+	// Admin clicks don't count
+	if models.GetUserFromContext(r).IsAdmin() {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	// The invalid filters are dropped, as on the search
+	values, _ := url.ParseQuery(data.Filters)
+	filters := models.ParseSearchFilters(values).Key()
+
+	if _, err := s.searchesRepo.RecordClick(r.Context(), query, filters, data.VideoID); err != nil {
+		slog.ErrorContext(
+			r.Context(), "failed to record the search click",
+			"path", r.URL.Path,
+			"query", query,
+			"error", err,
+		)
+		utils.HttpError(w, http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 // Perform an action on a video
 func (s *Service) ActionPostAPI(w http.ResponseWriter, r *http.Request) {
 
@@ -1,24 +1,30 @@
 package posts
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"log"
 	"log/slog"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/vlatan/video-store/internal/drivers/rdb"
 	"github.com/vlatan/video-store/internal/handlers/auth"
+	"github.com/vlatan/video-store/internal/integrations/gemini"
 	"github.com/vlatan/video-store/internal/models"
 	"github.com/vlatan/video-store/internal/redirect"
 	"github.com/vlatan/video-store/internal/utils"
 
+	slugify "github.com/gosimple/slug"
 	"github.com/jackc/pgx/v5"
 )
 
 const postCacheKey = "post:%s"
 const relatedPostsCacheKey = "post:%s:related_posts"
+const localizedPostCacheKey = "post:%s:locale:%s"
 
 // Handle the Home page
 func (s *Service) HomeHandler(w http.ResponseWriter, r *http.Request) {
@@ -57,11 +63,15 @@ func (s *Service) HomeHandler(w http.ResponseWriter, r *http.Request) {
 			s.rdb,
 			redisKey,
 			s.config.CacheTimeout,
-			func() (models.Posts, error) {
+			func(ctx context.Context) (models.Posts, error) {
 				return s.postsRepo.GetHomePosts(
-					r.Context(), "", orderBy,
+					ctx, "", orderBy,
 				)
 			},
+			rdb.WithTags(rdb.HomeTag),
+			rdb.WithSingleflight(),
+			rdb.WithLock(),
+			rdb.WithSoftTTL(s.config.CacheSoftTimeout),
 		)
 	}
 
@@ -117,11 +127,15 @@ func (s *Service) CategoryPostsHandler(w http.ResponseWriter, r *http.Request) {
 			s.rdb,
 			redisKey,
 			s.config.CacheTimeout,
-			func() (models.Posts, error) {
+			func(ctx context.Context) (models.Posts, error) {
 				return s.postsRepo.GetCategoryPosts(
-					r.Context(), slug, "", orderBy,
+					ctx, slug, "", orderBy,
 				)
 			},
+			rdb.WithTags(rdb.CategoryTag(slug)),
+			rdb.WithSingleflight(),
+			rdb.WithLock(),
+			rdb.WithSoftTTL(s.config.CacheSoftTimeout),
 		)
 	}
 
@@ -142,6 +156,7 @@ func (s *Service) CategoryPostsHandler(w http.ResponseWriter, r *http.Request) {
 
 	data.Posts = &posts
 	data.Title = data.Posts.Title
+	data.FeedPath = fmt.Sprintf("/category/%s/", slug)
 	s.ui.RenderHTML(w, r, "category.html", data)
 }
 
@@ -159,11 +174,16 @@ func (s *Service) SearchPostsHandler(w http.ResponseWriter, r *http.Request) {
 	data := models.GetDataFromContext(r)
 	data.SearchQuery = searchQuery
 
+	// Get the normalized filters if any
+	filters := models.ParseSearchFilters(r.URL.Query())
+	data.SearchFilters = filters
+
 	start := time.Now()
 	encodedSearchQuery := utils.EscapeTrancateString(searchQuery, 100)
 
 	// Construct the Redis key
 	redisKey := fmt.Sprintf("posts:search:%s", encodedSearchQuery)
+	redisKey += fmt.Sprintf(":filters:%s", filters.Key())
 
 	var (
 		err   error
@@ -172,20 +192,18 @@ func (s *Service) SearchPostsHandler(w http.ResponseWriter, r *http.Request) {
 
 	// Don't cache the search results only for the admin
 	if data.CurrentUser.IsAdmin() {
-		posts, err = s.postsRepo.SearchPosts(
-			r.Context(), searchQuery, s.config.PostsPerPage, "",
-		)
+		posts, err = s.postsRepo.SearchWithFallback(r.Context(), searchQuery, filters, "")
 	} else {
 		posts, err = rdb.GetCachedData(
 			r.Context(),
 			s.rdb,
 			redisKey,
 			s.config.CacheTimeout,
-			func() (models.Posts, error) {
-				return s.postsRepo.SearchPosts(
-					r.Context(), searchQuery, s.config.PostsPerPage, "",
-				)
+			func(ctx context.Context) (models.Posts, error) {
+				return s.postsRepo.SearchWithFallback(ctx, searchQuery, filters, "")
 			},
+			rdb.WithTags(rdb.SearchTag),
+			rdb.WithSingleflight(),
 		)
 	}
 
@@ -201,6 +219,26 @@ func (s *Service) SearchPostsHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Record the search with its filters, only the first page counts.
+	// The original query found nothing if the results are for the correction.
+	if !data.CurrentUser.IsAdmin() {
+		results := posts.TotalNum
+		if posts.Corrected {
+			results = 0
+		}
+		s.recordSearch(r, searchQuery, filters, results, end)
+	}
+
+	// Link the facets to the filtered search results,
+	// on a copy because the cached posts are shared among the concurrent searches
+	if posts.Facets != nil {
+		facetsQuery := searchQuery
+		if posts.Corrected {
+			facetsQuery = posts.Correction
+		}
+		posts.Facets = posts.Facets.WithLinks(r.URL.Path, facetsQuery, filters)
+	}
+
 	data.Posts = &posts
 	data.Posts.TimeTook = fmt.Sprintf("%.2f", end.Seconds())
 	data.Title = "Search"
@@ -323,13 +361,28 @@ func (s *Service) NewPostHandler(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		// The new video tops the latest posts of its source,
+		// its category is known once the content is generated
+		sourceID := post.PlaylistID
+		if sourceID == "" {
+			sourceID = "other"
+		}
+		s.invalidateCache(r.Context(), rdb.HomeTag, rdb.SearchTag, rdb.SourceTag(sourceID))
+
+		// Let the search engines know about the new video
+		s.indexNow.QueuePosts(r.Context(), videoID)
+
 		// Generate content in the background using Gemini.
 		// Give reasonable TTL for this to finish.
 		// In production no need to use it, the worker will
 		// generate the post content overnight.
 		go func() {
 			if err := s.generatePostContent(r, post, 30*time.Minute); err != nil {
-				log.Println(err)
+				slog.ErrorContext(
+					r.Context(), "failed to generate the post content",
+					"video", post.VideoID,
+					"error", err,
+				)
 			}
 		}()
 
@@ -345,6 +398,18 @@ func (s *Service) NewPostHandler(w http.ResponseWriter, r *http.Request) {
 
 // Handle a single post
 func (s *Service) SinglePostHandler(w http.ResponseWriter, r *http.Request) {
+	s.servePost(w, r, "")
+}
+
+// Handle a single post translated in a given locale
+func (s *Service) LocalizedPostHandler(locale string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.servePost(w, r, locale)
+	}
+}
+
+// servePost serves a single post, translated in a locale if one given
+func (s *Service) servePost(w http.ResponseWriter, r *http.Request, locale string) {
 
 	// Get video id from URL path
 	videoID := r.PathValue("video")
@@ -363,18 +428,29 @@ func (s *Service) SinglePostHandler(w http.ResponseWriter, r *http.Request) {
 		post models.Post
 	)
 
+	// Get the post in the locale if any
+	getPost := func(ctx context.Context) (models.Post, error) {
+		if locale == "" {
+			return s.postsRepo.GetSinglePost(ctx, videoID)
+		}
+		return s.postsRepo.GetLocalizedPost(ctx, videoID, locale)
+	}
+
+	redisKey := fmt.Sprintf(postCacheKey, videoID)
+	if locale != "" {
+		redisKey = fmt.Sprintf(localizedPostCacheKey, videoID, locale)
+	}
+
 	// Don't cache single post for logged in users
 	if data.CurrentUser.IsAuthenticated() {
-		post, err = s.postsRepo.GetSinglePost(r.Context(), videoID)
+		post, err = getPost(r.Context())
 	} else {
 		post, err = rdb.GetCachedData(
 			r.Context(),
 			s.rdb,
-			fmt.Sprintf(postCacheKey, videoID),
+			redisKey,
 			s.config.CacheTimeout,
-			func() (models.Post, error) {
-				return s.postsRepo.GetSinglePost(r.Context(), videoID)
-			},
+			getPost,
 		)
 	}
 
@@ -393,6 +469,11 @@ func (s *Service) SinglePostHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Link only to the currently configured locales
+	post.Locales = slices.DeleteFunc(post.Locales, func(l string) bool {
+		return !slices.Contains(s.config.Locales, l)
+	})
+
 	// Assign the post to data
 	data.CurrentPost = &post
 
@@ -410,16 +491,18 @@ func (s *Service) SinglePostHandler(w http.ResponseWriter, r *http.Request) {
 	// Ignore the error on related posts, no posts will be shown.
 	var relatedPosts models.Posts
 	if data.CurrentUser.IsAdmin() {
-		relatedPosts, _ = s.postsRepo.GetRelatedPosts(r.Context(), post.GetTitle())
+		relatedPosts, _ = s.postsRepo.GetRelatedPosts(r.Context(), videoID, post.GetTitle())
 	} else {
 		relatedPosts, _ = rdb.GetCachedData(
 			r.Context(),
 			s.rdb,
 			fmt.Sprintf(relatedPostsCacheKey, videoID),
 			s.config.CacheTimeout,
-			func() (models.Posts, error) {
-				return s.postsRepo.GetRelatedPosts(r.Context(), post.GetTitle())
+			func(ctx context.Context) (models.Posts, error) {
+				return s.postsRepo.GetRelatedPosts(ctx, videoID, post.GetTitle())
 			},
+			rdb.WithTags(rdb.PostTag(videoID)),
+			rdb.WithSingleflight(),
 		)
 	}
 
@@ -529,12 +612,22 @@ func (s *Service) UpdatePostHandler(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		// Delete the redis cache
-		redisKey := fmt.Sprintf(postCacheKey, videoID)
-		if err = s.rdb.Client.Del(r.Context(), redisKey).Err(); err != nil {
+		// The post has been reviewed manually, remove the flag if any
+		if _, err = s.postsRepo.UnflagPost(r.Context(), videoID); err != nil {
+			slog.ErrorContext(
+				r.Context(), "failed to unflag the post in DB",
+				"path", r.URL.Path,
+				"error", err,
+			)
+		}
+
+		// Delete the cached post and the lists it's in or joins
+		tags := editedPostTags(videoID, data.Form.Category.Value, data.Categories)
+		if _, err = s.rdb.Invalidate(r.Context(), tags...); err != nil {
 			slog.ErrorContext(
-				r.Context(), "failed to delete the cache on post",
+				r.Context(), "failed to invalidate the cache on post",
 				"path", r.URL.Path,
+				"tags", tags,
 				"error", err,
 			)
 			formError.Message = "Could not delete the cache on post"
@@ -543,6 +636,9 @@ func (s *Service) UpdatePostHandler(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		// Let the search engines know about the change
+		s.indexNow.QueuePosts(r.Context(), videoID)
+
 		// Check out the updated page
 		redirectURL := fmt.Sprintf("/video/%s/", videoID)
 		redirectTo := redirect.Sanitize(redirectURL, auth.IsProtectedRoute)
@@ -553,6 +649,127 @@ func (s *Service) UpdatePostHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// RegeneratePostHandler re-runs the content generation on a post
+// with optional guidance and previews the result next to the current one.
+// The preview is saved through the post update handler.
+func (s *Service) RegeneratePostHandler(w http.ResponseWriter, r *http.Request) {
+
+	// Get video id from URL path
+	videoID := r.PathValue("video")
+
+	// Validate the YT ID
+	if validVideoID.FindStringSubmatch(videoID) == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	// Get the post data straight from DB
+	post, err := s.postsRepo.GetSinglePost(r.Context(), videoID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		http.NotFound(w, r)
+		return
+	}
+
+	if err != nil {
+		slog.ErrorContext(
+			r.Context(), "failed to get the post from DB",
+			"path", r.URL.Path,
+			"error", err,
+		)
+		utils.HttpError(w, http.StatusInternalServerError)
+		return
+	}
+
+	// Generate default data
+	data := models.GetDataFromContext(r)
+
+	// Assign post data
+	data.CurrentPost = &post
+	if data.CurrentPost.Category == nil {
+		data.CurrentPost.Category = &models.Category{}
+	}
+
+	// Populate needed data for the regenerate form
+	data.Form = &models.Form{
+		Legend: "Regenerate Post",
+		Content: &models.FormGroup{
+			Type:        models.FieldTypeTextarea,
+			Label:       "Guidance",
+			Placeholder: "Optional, e.g. focus on the engineering aspects...",
+		},
+		Source: &models.FormGroup{
+			Label: "Source",
+			Value: sourceVideo,
+		},
+	}
+
+	data.Title = "Regenerate This Post"
+
+	switch r.Method {
+	case "GET":
+		// Serve the page with the form
+		s.ui.RenderHTML(w, r, "regenerate.html", data)
+
+	case "POST":
+		var formError models.FlashMessage
+
+		err := r.ParseForm()
+		if err != nil {
+			formError.Message = "Could not parse the form"
+			data.Form.Error = &formError
+			s.ui.RenderHTML(w, r, "regenerate.html", data)
+			return
+		}
+
+		// Get the guidance and the source from the form
+		data.Form.Content.Value = r.FormValue("guidance")
+		if source := r.FormValue("source"); source == sourceText {
+			data.Form.Source.Value = source
+		}
+
+		// The generation can outlast the server's write timeout
+		rc := http.NewResponseController(w)
+		if err = rc.SetWriteDeadline(time.Now().Add(regenerateTimeout + 10*time.Second)); err != nil {
+			slog.ErrorContext(
+				r.Context(), "failed to extend the write deadline",
+				"path", r.URL.Path,
+				"error", err,
+			)
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), regenerateTimeout)
+		defer cancel()
+
+		response, err := s.regeneratePostContent(
+			ctx,
+			&post,
+			data.Form.Content.Value,
+			data.Form.Source.Value,
+		)
+
+		// Still preview the response that failed the quality checks
+		if qualityErr, ok := errors.AsType[*gemini.QualityError](err); ok {
+			response = qualityErr.Response
+		}
+
+		if err != nil {
+			slog.ErrorContext(
+				r.Context(), "failed to regenerate the post content",
+				"path", r.URL.Path,
+				"error", err,
+			)
+			formError.Message = regenerateErrorMessage(err)
+			data.Form.Error = &formError
+		}
+
+		data.Regenerated = response
+		s.ui.RenderHTML(w, r, "regenerate.html", data)
+
+	default:
+		utils.HttpError(w, http.StatusMethodNotAllowed)
+	}
+}
+
 // Handle a post ban
 func (s *Service) BanPostHandler(w http.ResponseWriter, r *http.Request) {
 
@@ -583,6 +800,12 @@ func (s *Service) BanPostHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Delete the cached post and the lists it's in
+	s.invalidateCache(r.Context(), rdb.PostTag(videoID))
+
+	// Let the search engines know the video is gone
+	s.indexNow.QueuePosts(r.Context(), videoID)
+
 	successDelete := models.FlashMessage{
 		Message:  "The video has been deleted!",
 		Category: "info",
@@ -591,3 +814,333 @@ func (s *Service) BanPostHandler(w http.ResponseWriter, r *http.Request) {
 	s.ui.StoreFlashMessage(w, r, &successDelete)
 	http.Redirect(w, r, "/", http.StatusSeeOther)
 }
+
+// Handle the posts flagged for review admin dashboard
+func (s *Service) FlaggedPostsHandler(w http.ResponseWriter, r *http.Request) {
+
+	flaggedPosts, err := s.postsRepo.GetFlaggedPosts(r.Context())
+	if err != nil {
+		slog.ErrorContext(
+			r.Context(), "failed to get the flagged posts from DB",
+			"path", r.URL.Path,
+			"error", err,
+		)
+		utils.HttpError(w, http.StatusInternalServerError)
+		return
+	}
+
+	// Generate template data
+	data := models.GetDataFromContext(r)
+	data.FlaggedPosts = flaggedPosts
+	data.Title = "Flagged Posts"
+	s.ui.RenderHTML(w, r, "flagged.html", data)
+}
+
+// Handle the flagged post's review, approve or dismiss the generated content
+func (s *Service) ReviewFlaggedPostHandler(w http.ResponseWriter, r *http.Request) {
+
+	// Validate the YT ID
+	videoID := r.PathValue("video")
+	if validVideoID.FindStringSubmatch(videoID) == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	flaggedPost, err := s.postsRepo.GetFlaggedPost(r.Context(), videoID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		http.NotFound(w, r)
+		return
+	}
+
+	if err != nil {
+		slog.ErrorContext(
+			r.Context(), "failed to get the flagged post from DB",
+			"path", r.URL.Path,
+			"error", err,
+		)
+		utils.HttpError(w, http.StatusInternalServerError)
+		return
+	}
+
+	message := models.FlashMessage{Category: "info"}
+	tags := []string{rdb.PostTag(videoID)}
+
+	switch r.PathValue("action") {
+	case "approve":
+		if flaggedPost.Response == nil {
+			http.NotFound(w, r)
+			return
+		}
+
+		// Save the rejected response as it is
+		post := &models.Post{
+			VideoID:       videoID,
+			OriginalTitle: flaggedPost.Response.OriginalTitle,
+			Summary:       flaggedPost.Response.Summary,
+			Category:      &models.Category{Name: flaggedPost.Response.Category},
+		}
+
+		if _, err = s.postsRepo.UpdateGeneratedData(r.Context(), post); err != nil {
+			slog.ErrorContext(
+				r.Context(), "failed to update the generated data in DB",
+				"path", r.URL.Path,
+				"error", err,
+			)
+			utils.HttpError(w, http.StatusInternalServerError)
+			return
+		}
+
+		tags = editedPostTags(videoID, post.Category.Name, models.GetDataFromContext(r).Categories)
+		message.Message = "The generated content has been approved!"
+
+	case "dismiss":
+		message.Message = "The flag has been dismissed!"
+
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	if _, err = s.postsRepo.UnflagPost(r.Context(), videoID); err != nil {
+		slog.ErrorContext(
+			r.Context(), "failed to unflag the post in DB",
+			"path", r.URL.Path,
+			"error", err,
+		)
+		utils.HttpError(w, http.StatusInternalServerError)
+		return
+	}
+
+	// Delete the cached post and the lists it's in or joins
+	s.invalidateCache(r.Context(), tags...)
+
+	s.ui.StoreFlashMessage(w, r, &message)
+	http.Redirect(w, r, "/flagged/", http.StatusSeeOther)
+}
+
+// Handle the categories suggested by the model, waiting for a review
+func (s *Service) SuggestedCategoriesHandler(w http.ResponseWriter, r *http.Request) {
+
+	suggestions, err := s.catsRepo.GetSuggestedCategories(r.Context())
+	if err != nil {
+		slog.ErrorContext(
+			r.Context(), "failed to get the suggested categories from DB",
+			"path", r.URL.Path,
+			"error", err,
+		)
+		utils.HttpError(w, http.StatusInternalServerError)
+		return
+	}
+
+	// Generate template data
+	data := models.GetDataFromContext(r)
+	data.SuggestedCategories = suggestions
+	data.Title = "Suggested Categories"
+	s.ui.RenderHTML(w, r, "suggested.html", data)
+}
+
+// Handle the suggested category's review, create the category
+// and reassign the matching posts to it, or dismiss the suggestion
+func (s *Service) ReviewSuggestedCategoryHandler(w http.ResponseWriter, r *http.Request) {
+
+	if err := r.ParseForm(); err != nil {
+		utils.HttpError(w, http.StatusBadRequest)
+		return
+	}
+
+	suggestion := strings.TrimSpace(r.FormValue("suggestion"))
+	if suggestion == "" {
+		utils.HttpError(w, http.StatusBadRequest)
+		return
+	}
+
+	message := models.FlashMessage{Category: "info"}
+
+	switch r.PathValue("action") {
+	case "accept":
+
+		// The admin can rename the category before creating it
+		name := strings.TrimSpace(r.FormValue("name"))
+		if name == "" {
+			name = suggestion
+		}
+
+		category := models.Category{Name: name, Slug: slugify.Make(name)}
+		if category.Slug == "" {
+			message.Message = fmt.Sprintf("The category name %q is not valid!", name)
+			break
+		}
+
+		videoIDs, err := s.catsRepo.AcceptSuggestedCategory(r.Context(), suggestion, &category)
+		if err != nil {
+			slog.ErrorContext(
+				r.Context(), "failed to accept the suggested category",
+				"path", r.URL.Path,
+				"suggestion", suggestion,
+				"error", err,
+			)
+			utils.HttpError(w, http.StatusInternalServerError)
+			return
+		}
+
+		// Delete the cached categories, the new category's posts
+		// and the reassigned posts with the lists they're in
+		tags := []string{rdb.CategoriesTag, rdb.CategoryTag(category.Slug)}
+		for _, videoID := range videoIDs {
+			tags = append(tags, rdb.PostTag(videoID))
+		}
+		// The category enum passed to the model is refreshed on the next generation
+		s.invalidateCache(r.Context(), tags...)
+
+		message.Message = fmt.Sprintf(
+			"%d posts reassigned to the category %q!",
+			len(videoIDs), category.Name,
+		)
+
+	case "dismiss":
+		if _, err := s.catsRepo.DismissSuggestedCategory(r.Context(), suggestion); err != nil {
+			slog.ErrorContext(
+				r.Context(), "failed to dismiss the suggested category",
+				"path", r.URL.Path,
+				"suggestion", suggestion,
+				"error", err,
+			)
+			utils.HttpError(w, http.StatusInternalServerError)
+			return
+		}
+
+		message.Message = "The suggestion has been dismissed!"
+
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	s.ui.StoreFlashMessage(w, r, &message)
+	http.Redirect(w, r, "/categories/suggested/", http.StatusSeeOther)
+}
+
+// Handle the report on what the users search for
+func (s *Service) SearchReportHandler(w http.ResponseWriter, r *http.Request) {
+
+	var (
+		err    error
+		report = models.SearchReport{Days: reportDays}
+	)
+
+	report.Top, err = s.searchesRepo.GetTopQueries(r.Context(), reportDays, reportLimit)
+	if err == nil {
+		report.Trending, err = s.searchesRepo.GetTrendingQueries(
+			r.Context(), trendingDays, reportLimit, trendingMinSearches,
+		)
+	}
+
+	if err == nil {
+		report.ZeroResults, err = s.searchesRepo.GetZeroResultQueries(r.Context(), reportDays, reportLimit)
+	}
+
+	if err == nil {
+		report.Clicked, err = s.searchesRepo.GetClickedPosts(r.Context(), reportDays, reportLimit)
+	}
+
+	if err != nil {
+		slog.ErrorContext(
+			r.Context(), "failed to get the search report from DB",
+			"path", r.URL.Path,
+			"error", err,
+		)
+		utils.HttpError(w, http.StatusInternalServerError)
+		return
+	}
+
+	// Generate template data
+	data := models.GetDataFromContext(r)
+	data.SearchReport = &report
+	data.Title = "Search Report"
+	s.ui.RenderHTML(w, r, "searches.html", data)
+}
+
+// Handle the admin managed search synonyms page
+func (s *Service) SynonymsHandler(w http.ResponseWriter, r *http.Request) {
+
+	groups, err := s.searchesRepo.GetSynonymGroups(r.Context())
+	if err != nil {
+		slog.ErrorContext(
+			r.Context(), "failed to get the synonym groups from DB",
+			"path", r.URL.Path,
+			"error", err,
+		)
+		utils.HttpError(w, http.StatusInternalServerError)
+		return
+	}
+
+	data := models.GetDataFromContext(r)
+	data.SynonymGroups = groups
+	data.Title = "Search Synonyms"
+	s.ui.RenderHTML(w, r, "synonyms.html", data)
+}
+
+// Add or delete a synonym group
+func (s *Service) ManageSynonymsHandler(w http.ResponseWriter, r *http.Request) {
+
+	if err := r.ParseForm(); err != nil {
+		utils.HttpError(w, http.StatusBadRequest)
+		return
+	}
+
+	message := models.FlashMessage{Category: "info"}
+
+	switch r.PathValue("action") {
+	case "add":
+
+		terms := models.ParseSynonyms(r.FormValue("terms"), maxSynonymLength)
+		if len(terms) < 2 {
+			message.Message = "Provide at least two different comma separated terms!"
+			break
+		}
+
+		if _, err := s.searchesRepo.InsertSynonymGroup(r.Context(), terms); err != nil {
+			slog.ErrorContext(
+				r.Context(), "failed to insert the synonym group",
+				"path", r.URL.Path,
+				"terms", terms,
+				"error", err,
+			)
+			utils.HttpError(w, http.StatusInternalServerError)
+			return
+		}
+
+		message.Message = fmt.Sprintf("The synonyms %q have been added!", terms)
+
+	case "delete":
+
+		id, err := strconv.Atoi(r.FormValue("id"))
+		if err != nil {
+			utils.HttpError(w, http.StatusBadRequest)
+			return
+		}
+
+		if _, err = s.searchesRepo.DeleteSynonymGroup(r.Context(), id); err != nil {
+			slog.ErrorContext(
+				r.Context(), "failed to delete the synonym group",
+				"path", r.URL.Path,
+				"id", id,
+				"error", err,
+			)
+			utils.HttpError(w, http.StatusInternalServerError)
+			return
+		}
+
+		message.Message = "The synonyms have been deleted!"
+
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	// Apply the change on the next search, the cached results expire on their own
+	s.postsRepo.ResetSynonyms()
+
+	s.ui.StoreFlashMessage(w, r, &message)
+	http.Redirect(w, r, "/synonyms/", http.StatusSeeOther)
+}
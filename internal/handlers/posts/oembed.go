@@ -0,0 +1,227 @@
+package posts
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/vlatan/video-store/internal/drivers/rdb"
+	"github.com/vlatan/video-store/internal/models"
+	"github.com/vlatan/video-store/internal/utils"
+)
+
+// The post URLs the oEmbed endpoint resolves, optionally localized
+var oEmbedPath = regexp.MustCompile(`^/(?:([a-z]{2}(?:-[A-Za-z]+)?)/)?video/([-a-zA-Z0-9_]{11})/?$`)
+
+// The default player size, 16:9
+const (
+	playerWidth  = 560
+	playerHeight = 315
+)
+
+// oEmbed response of the video type
+// https://oembed.com/#section2.3
+type oEmbed struct {
+	XMLName         xml.Name `json:"-" xml:"oembed"`
+	Type            string   `json:"type" xml:"type"`
+	Version         string   `json:"version" xml:"version"`
+	Title           string   `json:"title" xml:"title"`
+	Description     string   `json:"description,omitempty" xml:"description,omitempty"`
+	AuthorName      string   `json:"author_name,omitempty" xml:"author_name,omitempty"`
+	AuthorURL       string   `json:"author_url,omitempty" xml:"author_url,omitempty"`
+	ProviderName    string   `json:"provider_name" xml:"provider_name"`
+	ProviderURL     string   `json:"provider_url" xml:"provider_url"`
+	CacheAge        int      `json:"cache_age,omitempty" xml:"cache_age,omitempty"`
+	ThumbnailURL    string   `json:"thumbnail_url,omitempty" xml:"thumbnail_url,omitempty"`
+	ThumbnailWidth  int64    `json:"thumbnail_width,omitempty" xml:"thumbnail_width,omitempty"`
+	ThumbnailHeight int64    `json:"thumbnail_height,omitempty" xml:"thumbnail_height,omitempty"`
+	HTML            string   `json:"html" xml:"html"`
+	Width           int      `json:"width" xml:"width"`
+	Height          int      `json:"height" xml:"height"`
+}
+
+// Handle the oEmbed requests for the post URLs
+func (s *Service) OEmbedHandler(w http.ResponseWriter, r *http.Request) {
+
+	query := r.URL.Query()
+
+	format := query.Get("format")
+	if format == "" {
+		format = "json"
+	}
+
+	// The spec wants 501 for the formats we don't serve
+	if format != "json" && format != "xml" {
+		utils.HttpError(w, http.StatusNotImplemented)
+		return
+	}
+
+	locale, videoID, ok := s.parseOEmbedURL(query.Get("url"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	redisKey := fmt.Sprintf(postCacheKey, videoID)
+	if locale != "" {
+		redisKey = fmt.Sprintf(localizedPostCacheKey, videoID, locale)
+	}
+
+	post, err := rdb.GetCachedData(
+		r.Context(),
+		s.rdb,
+		redisKey,
+		s.config.CacheTimeout,
+		func(ctx context.Context) (models.Post, error) {
+			if locale == "" {
+				return s.postsRepo.GetSinglePost(ctx, videoID)
+			}
+			return s.postsRepo.GetLocalizedPost(ctx, videoID, locale)
+		},
+	)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		http.NotFound(w, r)
+		return
+	}
+
+	if err != nil {
+		slog.ErrorContext(
+			r.Context(), "failed to get the post from DB",
+			"path", r.URL.Path,
+			"error", err,
+		)
+		utils.HttpError(w, http.StatusInternalServerError)
+		return
+	}
+
+	maxWidth := positiveInt(query.Get("maxwidth"))
+	maxHeight := positiveInt(query.Get("maxheight"))
+	embed := s.newOEmbed(&post, maxWidth, maxHeight)
+
+	var body []byte
+	if format == "xml" {
+		w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+		body, err = xml.Marshal(embed)
+		body = append([]byte(xml.Header), body...)
+	} else {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		body, err = json.Marshal(embed)
+	}
+
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to encode the oEmbed", "error", err)
+		utils.HttpError(w, http.StatusInternalServerError)
+		return
+	}
+
+	w.Write(body)
+}
+
+// Extract the locale, if any, and the video ID out of a post URL on this domain
+func (s *Service) parseOEmbedURL(rawURL string) (locale, videoID string, ok bool) {
+
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return "", "", false
+	}
+
+	if strings.TrimPrefix(u.Host, "www.") != strings.TrimPrefix(s.config.Domain, "www.") {
+		return "", "", false
+	}
+
+	match := oEmbedPath.FindStringSubmatch(u.Path)
+	if match == nil {
+		return "", "", false
+	}
+
+	locale, videoID = match[1], match[2]
+	if locale != "" && !slices.Contains(s.config.Locales, locale) {
+		return "", "", false
+	}
+
+	return locale, videoID, true
+}
+
+// Create the oEmbed of the post, the player and the thumbnail
+// fit in the maximum dimensions, zero means no limit
+func (s *Service) newOEmbed(post *models.Post, maxWidth, maxHeight int) oEmbed {
+
+	baseURL := fmt.Sprintf("%s://%s", s.config.Protocol, s.config.Domain)
+	width, height := playerSize(maxWidth, maxHeight)
+
+	embed := oEmbed{
+		Type:         "video",
+		Version:      "1.0",
+		Title:        post.GetTitle(),
+		Description:  post.MetaDescription,
+		ProviderName: s.config.AppName,
+		ProviderURL:  baseURL + "/",
+		CacheAge:     int(s.config.CacheTimeout.Seconds()),
+		Width:        width,
+		Height:       height,
+		HTML: fmt.Sprintf(
+			`<iframe width="%d" height="%d" src="https://www.youtube-nocookie.com/embed/%s" `+
+				`title="%s" frameborder="0" allow="accelerometer; autoplay; clipboard-write; `+
+				`encrypted-media; gyroscope; picture-in-picture" allowfullscreen></iframe>`,
+			width, height, post.VideoID, html.EscapeString(post.GetTitle()),
+		),
+	}
+
+	if post.Source != nil {
+		embed.AuthorName = post.Source.ChannelTitle
+		embed.AuthorURL = fmt.Sprintf("%s/source/%s/", baseURL, post.Source.PlaylistID)
+	}
+
+	thumb := post.Thumbnail
+	if post.Thumbnails != nil {
+		thumb = post.Thumbnails.FitThumb(int64(maxWidth), int64(maxHeight))
+	}
+
+	if thumb != nil && thumb.Url != "" {
+		embed.ThumbnailURL = thumb.Url
+		embed.ThumbnailWidth = thumb.Width
+		embed.ThumbnailHeight = thumb.Height
+	}
+
+	return embed
+}
+
+// Scale the default player size down to fit in the maximum dimensions.
+// Neither dimension rounds down to zero, that's no valid iframe.
+func playerSize(maxWidth, maxHeight int) (width, height int) {
+
+	width, height = playerWidth, playerHeight
+
+	if maxWidth > 0 && width > maxWidth {
+		width = maxWidth
+		height = max(width*playerHeight/playerWidth, 1)
+	}
+
+	if maxHeight > 0 && height > maxHeight {
+		height = maxHeight
+		width = max(height*playerWidth/playerHeight, 1)
+	}
+
+	return width, height
+}
+
+// Parse a positive integer, zero if invalid
+func positiveInt(s string) int {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
@@ -0,0 +1,104 @@
+package posts
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/vlatan/video-store/internal/config"
+	"github.com/vlatan/video-store/internal/models"
+	"google.golang.org/api/youtube/v3"
+)
+
+func TestParseOEmbedURL(t *testing.T) {
+
+	s := &Service{config: &config.Config{Domain: "example.com", Locales: []string{"de"}}}
+
+	tests := []struct {
+		name       string
+		url        string
+		wantLocale string
+		wantID     string
+		wantOk     bool
+	}{
+		{"post", "https://example.com/video/abcdefghijk/", "", "abcdefghijk", true},
+		{"no trailing slash", "https://example.com/video/abcdefghijk", "", "abcdefghijk", true},
+		{"www", "https://www.example.com/video/abcdefghijk/", "", "abcdefghijk", true},
+		{"localized", "https://example.com/de/video/abcdefghijk/", "de", "abcdefghijk", true},
+		{"unknown locale", "https://example.com/fr/video/abcdefghijk/", "", "", false},
+		{"other domain", "https://example.org/video/abcdefghijk/", "", "", false},
+		{"invalid ID", "https://example.com/video/abc/", "", "", false},
+		{"not a post", "https://example.com/category/music/", "", "", false},
+		{"no scheme", "example.com/video/abcdefghijk/", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			locale, videoID, ok := s.parseOEmbedURL(tt.url)
+			if locale != tt.wantLocale || videoID != tt.wantID || ok != tt.wantOk {
+				t.Errorf(
+					"got (%q, %q, %v), want (%q, %q, %v)",
+					locale, videoID, ok, tt.wantLocale, tt.wantID, tt.wantOk,
+				)
+			}
+		})
+	}
+}
+
+func TestNewOEmbed(t *testing.T) {
+
+	s := &Service{config: &config.Config{Protocol: "https", Domain: "example.com", AppName: "App"}}
+	post := &models.Post{
+		VideoID: "abcdefghijk",
+		Title:   `Quotes "and" <tags>`,
+		Source:  &models.Source{PlaylistID: "PL1", ChannelTitle: "Channel"},
+		Thumbnails: &models.Thumbnails{
+			Medium: &youtube.Thumbnail{Width: 320, Height: 180, Url: "medium"},
+			High:   &youtube.Thumbnail{Width: 480, Height: 360, Url: "high"},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		maxWidth   int
+		maxHeight  int
+		wantWidth  int
+		wantHeight int
+		wantThumb  string
+	}{
+		{"no limits", 0, 0, 560, 315, "high"},
+		{"bigger limits", 1000, 1000, 560, 315, "high"},
+		{"width limit", 400, 0, 400, 225, "medium"},
+		{"height limit", 0, 180, 320, 180, "medium"},
+		{"tiny", 100, 0, 100, 56, ""},
+		{"one pixel wide", 1, 0, 1, 1, ""},
+		{"one pixel high", 0, 1, 1, 1, ""},
+		{"one pixel", 1, 1, 1, 1, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			embed := s.newOEmbed(post, tt.maxWidth, tt.maxHeight)
+
+			if embed.Width != tt.wantWidth || embed.Height != tt.wantHeight {
+				t.Errorf(
+					"got player %dx%d, want %dx%d",
+					embed.Width, embed.Height, tt.wantWidth, tt.wantHeight,
+				)
+			}
+
+			if embed.ThumbnailURL != tt.wantThumb {
+				t.Errorf("got thumbnail %q, want %q", embed.ThumbnailURL, tt.wantThumb)
+			}
+
+			if embed.AuthorURL != "https://example.com/source/PL1/" {
+				t.Errorf("got author URL %q", embed.AuthorURL)
+			}
+
+			// The title must not break out of the attribute
+			if !strings.Contains(embed.HTML, `title="Quotes &#34;and&#34; &lt;tags&gt;"`) {
+				t.Errorf("unescaped title in %q", embed.HTML)
+			}
+		})
+	}
+}
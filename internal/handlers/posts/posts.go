@@ -4,38 +4,50 @@ import (
 	"github.com/vlatan/video-store/internal/config"
 	"github.com/vlatan/video-store/internal/drivers/rdb"
 	"github.com/vlatan/video-store/internal/integrations/gemini"
+	"github.com/vlatan/video-store/internal/integrations/indexnow"
 	"github.com/vlatan/video-store/internal/integrations/yt"
+	catsRepo "github.com/vlatan/video-store/internal/repositories/categories"
 	postsRepo "github.com/vlatan/video-store/internal/repositories/posts"
+	searchesRepo "github.com/vlatan/video-store/internal/repositories/searches"
 	usersRepo "github.com/vlatan/video-store/internal/repositories/users"
 	"github.com/vlatan/video-store/internal/ui"
 )
 
 type Service struct {
-	postsRepo *postsRepo.Repository
-	usersRepo *usersRepo.Repository
-	rdb       *rdb.Service
-	ui        ui.Service
-	config    *config.Config
-	yt        *yt.Service
-	gemini    *gemini.Service
+	postsRepo    *postsRepo.Repository
+	usersRepo    *usersRepo.Repository
+	catsRepo     *catsRepo.Repository
+	searchesRepo *searchesRepo.Repository
+	rdb          *rdb.Service
+	ui           ui.Service
+	config       *config.Config
+	yt           *yt.Service
+	gemini       *gemini.Service
+	indexNow     *indexnow.Service
 }
 
 func New(
 	postsRepo *postsRepo.Repository,
 	usersRepo *usersRepo.Repository,
+	catsRepo *catsRepo.Repository,
+	searchesRepo *searchesRepo.Repository,
 	rdb *rdb.Service,
 	ui ui.Service,
 	config *config.Config,
 	yt *yt.Service,
 	gemini *gemini.Service,
+	indexNow *indexnow.Service,
 ) *Service {
 	return &Service{
-		postsRepo: postsRepo,
-		usersRepo: usersRepo,
-		rdb:       rdb,
-		ui:        ui,
-		config:    config,
-		yt:        yt,
-		gemini:    gemini,
+		postsRepo:    postsRepo,
+		usersRepo:    usersRepo,
+		catsRepo:     catsRepo,
+		searchesRepo: searchesRepo,
+		rdb:          rdb,
+		ui:           ui,
+		config:       config,
+		yt:           yt,
+		gemini:       gemini,
+		indexNow:     indexNow,
 	}
 }
@@ -11,14 +11,46 @@ import (
 	"strings"
 	"time"
 
+	"github.com/vlatan/video-store/internal/drivers/rdb"
 	"github.com/vlatan/video-store/internal/integrations/gemini"
 	"github.com/vlatan/video-store/internal/models"
 	"github.com/vlatan/video-store/internal/utils"
+	"google.golang.org/genai"
 )
 
 // Validate video ID
 var validVideoID = regexp.MustCompile("^([-a-zA-Z0-9_]{11})$")
 
+// Contents sources to regenerate a post from
+const (
+	sourceVideo = "video"
+	sourceText  = "text"
+)
+
+// Time limit for the regeneration on admin's request
+const regenerateTimeout = 2 * time.Minute
+
+// Search analytics
+const (
+	maxQueryLength      = 100             // characters of the recorded query
+	recordTimeout       = 5 * time.Second // time limit to record a search
+	reportDays          = 30              // days covered by the report
+	trendingDays        = 7               // days compared for trending queries
+	trendingMinSearches = 3               // searches needed to be trending
+	reportLimit         = 50              // rows in each report table
+)
+
+// Search suggestions limits
+const (
+	minSuggestLength = 2  // characters typed before suggesting
+	maxSuggestLength = 64 // characters considered for suggestions
+	suggestPosts     = 6  // number of suggested posts
+	suggestTaxonomy  = 3  // number of suggested categories and sources each
+)
+
+// Maximum characters of a synonym term
+const maxSynonymLength = 128
+
 // Extract YouTube ID from URL
 func extractYouTubeID(rawURL string) (string, error) {
 	parsedURL, err := url.Parse(rawURL)
@@ -41,6 +73,29 @@ func extractYouTubeID(rawURL string) (string, error) {
 	return "", errors.New("could not extract the video ID")
 }
 
+// invalidateCache deletes the cached entries with any of the tags.
+// The error is only logged, the entries expire on their own eventually.
+func (s *Service) invalidateCache(ctx context.Context, tags ...string) {
+	if _, err := s.rdb.Invalidate(ctx, tags...); err != nil {
+		slog.ErrorContext(
+			ctx, "failed to invalidate the cache",
+			"tags", tags,
+			"error", err,
+		)
+	}
+}
+
+// editedPostTags returns the tags invalidated by the post's edit.
+// The post leaves the lists it is in, and may join the search results
+// and the lists of its new category.
+func editedPostTags(videoID, category string, categories models.Categories) []string {
+	tags := []string{rdb.PostTag(videoID), rdb.SearchTag}
+	if slug, ok := categories.Slug(category); ok {
+		tags = append(tags, rdb.CategoryTag(slug))
+	}
+	return tags
+}
+
 func (s *Service) generatePostContent(
 	r *http.Request,
 	post *models.Post,
@@ -84,6 +139,15 @@ func (s *Service) generatePostContent(
 		genaiResponse, err = s.gemini.GenerateContent(ctx, post, contents, retryConfig)
 	}
 
+	// Flag the post for review if the content failed the quality checks
+	if qualityErr, ok := errors.AsType[*gemini.QualityError](err); ok {
+		if _, flagErr := s.postsRepo.FlagPost(
+			ctx, post.VideoID, qualityErr.Issues, qualityErr.Response,
+		); flagErr != nil {
+			err = errors.Join(err, flagErr)
+		}
+	}
+
 	if err != nil {
 		return fmt.Errorf(
 			"failed to generate LLM content on path %q: %w",
@@ -102,5 +166,109 @@ func (s *Service) generatePostContent(
 			post.VideoID, err)
 	}
 
+	// The post has its title, summary and category now.
+	// The category lists are known from the request's cached categories,
+	// without them only the post and the search results are invalidated.
+	var categories models.Categories
+	if data := models.GetDataFromContext(r); data != nil {
+		categories = data.Categories
+	}
+	s.invalidateCache(ctx, editedPostTags(post.VideoID, post.Category.Name, categories)...)
+
+	// Record the new category proposed by the model
+	if genaiResponse.SuggestedCategory != "" {
+		_, err = s.catsRepo.SuggestCategory(ctx, post.VideoID, genaiResponse.SuggestedCategory)
+		if err != nil {
+			return fmt.Errorf(
+				"failed to record the suggested category on video %q: %w",
+				post.VideoID, err)
+		}
+	}
+
 	return nil
 }
+
+// regeneratePostContent generates new content for the post
+// from the video or from its text metadata, with optional guidance.
+// Doesn't save anything, the result is meant for a preview.
+func (s *Service) regeneratePostContent(
+	ctx context.Context,
+	post *models.Post,
+	guidance string,
+	source string) (*models.GenaiResponse, error) {
+
+	// Single attempt, the admin can always try again
+	retryConfig := &utils.RetryConfig{MaxRetries: 1}
+
+	var contents []*genai.Content
+	switch source {
+	case sourceText:
+		contents = s.gemini.MakeTextContents(post)
+	default:
+		var err error
+		contents, err = s.gemini.MakeVideoContents(post)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to create gemini contents on video %q; %w",
+				post.VideoID, err)
+		}
+	}
+
+	contents = s.gemini.WithGuidance(contents, guidance)
+	return s.gemini.GenerateContent(ctx, post, contents, retryConfig)
+}
+
+// regenerateErrorMessage returns a form error message for the regeneration error
+func regenerateErrorMessage(err error) string {
+
+	if errors.Is(err, gemini.ErrDailyLimitReached) ||
+		errors.Is(err, gemini.ErrMinuteLimitReached) {
+		return "The Gemini quota is exhausted, try again later"
+	}
+
+	if _, ok := errors.AsType[*gemini.BlockedError](err); ok {
+		return "The model refused the contents, try with the text source"
+	}
+
+	if qualityErr, ok := errors.AsType[*gemini.QualityError](err); ok {
+		return "The result failed the quality checks: " +
+			strings.Join(qualityErr.Issues, "; ")
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "The generation took too long, try with the text source"
+	}
+
+	return "Could not regenerate the post content"
+}
+
+// Record the search with its filters for the analytics in the background.
+// The user doesn't wait on it, any error is just logged.
+func (s *Service) recordSearch(
+	r *http.Request,
+	searchQuery string,
+	filters models.SearchFilters,
+	results int,
+	latency time.Duration) {
+
+	query := utils.NormalizeQuery(searchQuery, maxQueryLength)
+	if query == "" {
+		return
+	}
+
+	// Detach the request context so the recording outlives the request
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), recordTimeout)
+
+	go func() {
+		defer cancel()
+		_, err := s.searchesRepo.RecordSearch(ctx, query, filters.Key(), results, latency)
+		if err != nil {
+			slog.ErrorContext(
+				ctx, "failed to record the search",
+				"query", query,
+				"filters", filters.Key(),
+				"error", err,
+			)
+		}
+	}()
+}
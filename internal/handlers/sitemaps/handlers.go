@@ -2,13 +2,11 @@ package sitemaps
 
 import (
 	"html/template"
-	"log"
 	"log/slog"
 	"net/http"
 	"slices"
 	"sort"
 	"strconv"
-	"strings"
 
 	"github.com/vlatan/video-store/internal/models"
 )
@@ -31,31 +29,16 @@ func (s *Service) SitemapPartHandler(w http.ResponseWriter, r *http.Request) {
 	// Extract the part from URL, i.e. "post-19.xml"
 	partKey := r.PathValue("part")
 
-	// Check if this is xml page, base is now -> "post-19"
-	base, ok := strings.CutSuffix(partKey, ".xml")
-	if !ok {
+	// Validate the part key, i.e. "post-19.xml" or its chunk "post-19-1.xml"
+	match := validPartKey.FindStringSubmatch(partKey)
+	if match == nil || !slices.Contains(sitemapPartTypes, match[1]) {
 		http.NotFound(w, r)
 		return
 	}
 
-	// Find the last "-"
-	dashIdx := strings.LastIndex(base, "-")
-	if dashIdx == -1 {
-		http.NotFound(w, r)
-		return
-	}
-
-	// Validate the sitemap part type
-	prefix := base[:dashIdx]
-	if !slices.Contains(sitemapPartTypes, prefix) {
-		http.NotFound(w, r)
-		return
-	}
-
-	// Extract and validate the number -> "19"
-	numStr := base[dashIdx+1:]
-	partNum, err := strconv.Atoi(numStr)
-	if err != nil || partNum < 0 || partNum >= sitemapPartsNum {
+	// Validate the bucket number -> "19"
+	partNum, err := strconv.Atoi(match[2])
+	if err != nil || partNum >= sitemapPartsNum {
 		http.NotFound(w, r)
 		return
 	}
@@ -94,7 +77,7 @@ func (s *Service) SitemapIndexHandler(w http.ResponseWriter, r *http.Request) {
 	sitemap, err := s.GetSitemapIndex(r, sitemapRedisKey)
 
 	if err != nil {
-		log.Printf("Couldn't get sitemap index: %v", err)
+		slog.ErrorContext(r.Context(), "failed to get the sitemap index", "error", err)
 		http.NotFound(w, r)
 		return
 	}
@@ -1,6 +1,8 @@
 package sitemaps
 
 import (
+	"regexp"
+
 	"github.com/vlatan/video-store/internal/config"
 	"github.com/vlatan/video-store/internal/drivers/rdb"
 	postsRepo "github.com/vlatan/video-store/internal/repositories/posts"
@@ -12,6 +14,24 @@ const (
 	sitemapRedisKey = "sitemap:data"
 )
 
+// The sitemap limits, a bigger part continues in the next one
+// https://www.sitemaps.org/protocol.html#index
+const (
+	maxPartEntries = 50_000
+	maxPartBytes   = 50 << 20
+)
+
+// The bytes of the markup around the values, with some room
+// for the whitespace, used to estimate the size of a part
+const (
+	urlsetSize = 512
+	urlSize    = 128
+	videoSize  = 512
+)
+
+// Sitemap part key, the type, the bucket and the chunk if any
+var validPartKey = regexp.MustCompile(`^([a-z]+)-(\d{2})(?:-(\d{1,3}))?\.xml$`)
+
 var sitemapPartTypes = []string{
 	"post",
 	"misc",
@@ -32,12 +52,15 @@ func New(
 	config *config.Config,
 ) *Service {
 
-	args := make([]any, 0, 1+len(sitemapPartTypes))
+	args := make([]any, 0, 2+len(sitemapPartTypes))
 	args = append(args, sitemapPartsNum)
 	for _, t := range sitemapPartTypes {
 		args = append(args, t)
 	}
 
+	// The locales of the localized posts
+	args = append(args, config.Locales)
+
 	return &Service{
 		postsRepo: postsRepo,
 		rdb:       rdb,
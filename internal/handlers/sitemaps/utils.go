@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/vlatan/video-store/internal/models"
@@ -29,15 +30,46 @@ func (s *Service) getSitemapIndexFromDB(r *http.Request, args ...any) (models.Si
 
 	// Get base absolute URL
 	baseURL := fmt.Sprintf("%s://%s", s.config.Protocol, s.config.Domain)
+	return splitSitemap(baseURL, data, maxPartEntries, maxPartBytes)
+}
+
+// Split the sitemap items in parts by type and bucket. A part which would
+// go over the entries or the bytes limit continues in the next chunk,
+// i.e. "post-03.xml", "post-03-1.xml", "post-03-2.xml" etc.
+// Additionally process the last modified time for each part,
+// and adjust the item and part location with an absolute URL.
+func splitSitemap(baseURL string, data []*models.SitemapItem, maxEntries, maxBytes int) (models.SitemapIndex, error) {
 
-	// Populate the sitemap index map, contenining sitemap parts.
-	// Additionally process the last modified time for each part,
-	// and adjust the item and part location with an absolute URL.
+	type chunk struct {
+		num, entries, bytes int
+	}
+
+	var err error
 	result := make(models.SitemapIndex)
+	chunks := make(map[string]*chunk)
+
 	for _, item := range data {
 
-		partKey := fmt.Sprintf("%s-%02d.xml", item.Type, item.BucketId)
 		item.Location = baseURL + item.Location
+		size := itemSize(item)
+
+		// Start a new chunk of the bucket if this one is full
+		bucketKey := fmt.Sprintf("%s-%02d", item.Type, item.BucketId)
+		c, ok := chunks[bucketKey]
+		if !ok {
+			c = &chunk{bytes: urlsetSize}
+			chunks[bucketKey] = c
+		} else if c.entries >= maxEntries || c.bytes+size > maxBytes {
+			*c = chunk{num: c.num + 1, bytes: urlsetSize}
+		}
+
+		c.entries++
+		c.bytes += size
+
+		partKey := bucketKey + ".xml"
+		if c.num > 0 {
+			partKey = fmt.Sprintf("%s-%d.xml", bucketKey, c.num)
+		}
 
 		// If part exists adjust last modifed time for that part, and
 		// append the new item its to entries.
@@ -60,6 +92,41 @@ func (s *Service) getSitemapIndexFromDB(r *http.Request, args ...any) (models.Si
 	return result, nil
 }
 
+// Estimate the bytes of the item in the sitemap, on the high side
+func itemSize(item *models.SitemapItem) int {
+
+	size := urlSize + escapedLen(item.Location) + len(item.LastModified)
+	if v := item.Video; v != nil {
+		size += videoSize +
+			escapedLen(v.ThumbnailLoc) +
+			escapedLen(v.Title) +
+			escapedLen(v.Description) +
+			escapedLen(v.PlayerLoc) +
+			escapedLen(v.PublicationDate) +
+			escapedLen(v.Category) +
+			len(strconv.Itoa(v.Duration))
+	}
+
+	return size
+}
+
+// The length of the string escaped for XML, the templates escape
+// the quotes and the plus sign as numeric references too
+func escapedLen(s string) int {
+	n := len(s)
+	for _, r := range s {
+		switch r {
+		case '&':
+			n += len("&amp;") - 1
+		case '<', '>':
+			n += len("&lt;") - 1
+		case '"', '\'', '+':
+			n += len("&#34;") - 1
+		}
+	}
+	return n
+}
+
 // Get the entire sitemap either from Redis or DB
 func (s *Service) GetSitemapIndex(r *http.Request, sitemapKey string) (models.SitemapIndex, error) {
 
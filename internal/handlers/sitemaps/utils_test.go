@@ -0,0 +1,114 @@
+package sitemaps
+
+import (
+	"html/template"
+	"strings"
+	"testing"
+
+	"github.com/vlatan/video-store/internal/models"
+)
+
+func TestSplitSitemap(t *testing.T) {
+
+	newItems := func(n int, bucket int) []*models.SitemapItem {
+		items := make([]*models.SitemapItem, n)
+		for i := range items {
+			items[i] = &models.SitemapItem{
+				Type:         "post",
+				BucketId:     bucket,
+				Location:     "/video/abcdefghijk/",
+				LastModified: "2025-01-01",
+			}
+		}
+		return items
+	}
+
+	// The size of an item once its location is absolute
+	size := itemSize(&models.SitemapItem{
+		Location:     "https://example.com/video/abcdefghijk/",
+		LastModified: "2025-01-01",
+	})
+
+	tests := []struct {
+		name       string
+		items      []*models.SitemapItem
+		maxEntries int
+		maxBytes   int
+		expected   map[string]int // entries per part
+	}{
+		{
+			"within limits",
+			append(newItems(3, 0), newItems(2, 1)...),
+			10, 1 << 20,
+			map[string]int{"post-00.xml": 3, "post-01.xml": 2},
+		},
+		{
+			"entries limit",
+			newItems(5, 3),
+			2, 1 << 20,
+			map[string]int{"post-03.xml": 2, "post-03-1.xml": 2, "post-03-2.xml": 1},
+		},
+		{
+			"bytes limit",
+			newItems(3, 0),
+			10, urlsetSize + 2*size + 1,
+			map[string]int{"post-00.xml": 2, "post-00-1.xml": 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			index, err := splitSitemap("https://example.com", tt.items, tt.maxEntries, tt.maxBytes)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(index) != len(tt.expected) {
+				t.Fatalf("got %d parts, want %d", len(index), len(tt.expected))
+			}
+
+			for key, entries := range tt.expected {
+
+				part, ok := index[key]
+				if !ok {
+					t.Fatalf("missing part %q", key)
+				}
+
+				if len(part.Entries) != entries {
+					t.Errorf("part %q: got %d entries, want %d", key, len(part.Entries), entries)
+				}
+
+				if want := "https://example.com/" + key; part.Location != want {
+					t.Errorf("got location %q, want %q", part.Location, want)
+				}
+
+				if !validPartKey.MatchString(key) {
+					t.Errorf("the handler rejects the part key %q", key)
+				}
+			}
+		})
+	}
+}
+
+func TestEscapedLen(t *testing.T) {
+
+	tests := []string{
+		"",
+		"plain text",
+		`Tom & Jerry's "best" <episodes> +1`,
+		"Ćirilica",
+	}
+
+	for _, s := range tests {
+		t.Run(s, func(t *testing.T) {
+			escaped := template.HTMLEscapeString(s)
+			if got := escapedLen(s); got < len(escaped) {
+				t.Errorf("got %d, less than the escaped length %d", got, len(escaped))
+			}
+			if strings.ContainsAny(s, "&<>") && escapedLen(s) == len(s) {
+				t.Error("the escaping is not counted")
+			}
+		})
+	}
+}
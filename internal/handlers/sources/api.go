@@ -1,6 +1,7 @@
 package sources
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -56,11 +57,13 @@ func (s *Service) SourcePostsAPI(w http.ResponseWriter, r *http.Request) {
 			s.rdb,
 			redisKey,
 			s.config.CacheTimeout,
-			func() (models.Posts, error) {
+			func(ctx context.Context) (models.Posts, error) {
 				return s.postsRepo.GetSourcePosts(
-					r.Context(), sourceID, cursor, orderBy,
+					ctx, sourceID, cursor, orderBy,
 				)
 			},
+			rdb.WithTags(rdb.SourceTag(sourceID)),
+			rdb.WithSingleflight(),
 		)
 	}
 
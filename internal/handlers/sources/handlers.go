@@ -1,6 +1,7 @@
 package sources
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -32,9 +33,12 @@ func (s *Service) SourcesHandler(w http.ResponseWriter, r *http.Request) {
 			s.rdb,
 			"sources",
 			s.config.CacheTimeout,
-			func() (models.Sources, error) {
-				return s.sourcesRepo.GetAllSources(r.Context())
+			func(ctx context.Context) (models.Sources, error) {
+				return s.sourcesRepo.GetAllSources(ctx)
 			},
+			rdb.WithTags(rdb.SourcesTag),
+			rdb.WithSingleflight(),
+			rdb.WithSoftTTL(s.config.CacheSoftTimeout),
 		)
 	}
 
@@ -225,11 +229,15 @@ func (s *Service) SourcePostsHandler(w http.ResponseWriter, r *http.Request) {
 			s.rdb,
 			redisKey,
 			s.config.CacheTimeout,
-			func() (models.Posts, error) {
+			func(ctx context.Context) (models.Posts, error) {
 				return s.postsRepo.GetSourcePosts(
-					r.Context(), sourceID, "", orderBy,
+					ctx, sourceID, "", orderBy,
 				)
 			},
+			rdb.WithTags(rdb.SourceTag(sourceID)),
+			rdb.WithSingleflight(),
+			rdb.WithLock(),
+			rdb.WithSoftTTL(s.config.CacheSoftTimeout),
 		)
 	}
 
@@ -253,5 +261,6 @@ func (s *Service) SourcePostsHandler(w http.ResponseWriter, r *http.Request) {
 		data.Posts.Title = "Other Uploads"
 	}
 	data.Title = data.Posts.Title
+	data.FeedPath = fmt.Sprintf("/source/%s/", sourceID)
 	s.ui.RenderHTML(w, r, "source.html", data)
 }
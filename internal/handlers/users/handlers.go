@@ -1,14 +1,34 @@
 package users
 
 import (
+	"fmt"
 	"log/slog"
 	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
 	"time"
 
 	"github.com/vlatan/video-store/internal/models"
 	"github.com/vlatan/video-store/internal/utils"
 )
 
+// Saved searches limits
+const (
+	maxSavedSearches    = 20  // per user
+	maxSavedQueryLength = 200 // characters of the saved query
+	notificationsLimit  = 50  // latest notifications on the page
+)
+
+// API tokens limits
+const (
+	maxTokens          = 20 // per user
+	maxTokenNameLength = 64 // characters of the token name
+)
+
+// Allowed token lifetimes in days, zero means no expiry
+var tokenExpiryDays = []int{0, 30, 90, 365}
+
 // Handle the user favorites page
 func (s *Service) UserFavoritesHandler(w http.ResponseWriter, r *http.Request) {
 
@@ -82,3 +102,285 @@ func (s *Service) UsersHandler(w http.ResponseWriter, r *http.Request) {
 	data.Title = "Users"
 	s.ui.RenderHTML(w, r, "admin.html", data)
 }
+
+// Handle the user saved searches page with the latest notifications
+func (s *Service) SavedSearchesHandler(w http.ResponseWriter, r *http.Request) {
+
+	// Generate template data
+	data := models.GetDataFromContext(r)
+	userID := data.CurrentUser.ID
+
+	searches, err := s.searchesRepo.GetUserSavedSearches(r.Context(), userID)
+	if err == nil {
+		data.Notifications, err = s.searchesRepo.GetUserNotifications(
+			r.Context(), userID, notificationsLimit,
+		)
+	}
+
+	if err != nil {
+		slog.ErrorContext(
+			r.Context(), "failed to get user's saved searches from DB",
+			"path", r.URL.Path,
+			"userId", userID,
+			"error", err,
+		)
+		utils.HttpError(w, http.StatusInternalServerError)
+		return
+	}
+
+	// The notifications are seen now, still show them as new on this page
+	if data.UnreadNotifications > 0 {
+		s.readNotifications(r, userID)
+	}
+
+	data.SavedSearches = searches
+	data.Title = "Your Saved Searches"
+	s.ui.RenderHTML(w, r, "saved_searches.html", data)
+}
+
+// Save the current search of the user
+func (s *Service) SaveSearchHandler(w http.ResponseWriter, r *http.Request) {
+
+	if err := r.ParseForm(); err != nil {
+		utils.HttpError(w, http.StatusBadRequest)
+		return
+	}
+
+	currentUser := models.GetUserFromContext(r)
+
+	// The filters come in the same format as on the search page
+	filters, _ := url.ParseQuery(r.FormValue("filters"))
+	search := models.SavedSearch{
+		UserID:      currentUser.ID,
+		Query:       utils.NormalizeQuery(r.FormValue("q"), maxSavedQueryLength),
+		Filters:     models.ParseSearchFilters(filters),
+		EmailDigest: r.FormValue("email") != "",
+	}
+
+	if search.Query == "" {
+		utils.HttpError(w, http.StatusBadRequest)
+		return
+	}
+
+	searches, err := s.searchesRepo.GetUserSavedSearches(r.Context(), currentUser.ID)
+	if err != nil {
+		slog.ErrorContext(
+			r.Context(), "failed to get user's saved searches from DB",
+			"path", r.URL.Path,
+			"userId", currentUser.ID,
+			"error", err,
+		)
+		utils.HttpError(w, http.StatusInternalServerError)
+		return
+	}
+
+	message := models.FlashMessage{
+		Message:  "The search has been saved! You'll be notified on new matching videos.",
+		Category: "info",
+	}
+
+	// Saving the same search again only updates its email preference
+	exists := slices.ContainsFunc(searches, func(ss models.SavedSearch) bool {
+		return ss.Key() == search.Key()
+	})
+
+	if !exists && len(searches) >= maxSavedSearches {
+		message.Message = fmt.Sprintf(
+			"You can save up to %d searches, please delete some first.",
+			maxSavedSearches,
+		)
+		s.ui.StoreFlashMessage(w, r, &message)
+		http.Redirect(w, r, "/user/searches/", http.StatusSeeOther)
+		return
+	}
+
+	if _, err = s.searchesRepo.SaveSearch(r.Context(), search); err != nil {
+		slog.ErrorContext(
+			r.Context(), "failed to save the search",
+			"path", r.URL.Path,
+			"userId", currentUser.ID,
+			"error", err,
+		)
+		utils.HttpError(w, http.StatusInternalServerError)
+		return
+	}
+
+	s.ui.StoreFlashMessage(w, r, &message)
+	http.Redirect(w, r, search.URL(), http.StatusSeeOther)
+}
+
+// Delete a saved search of the user
+func (s *Service) DeleteSavedSearchHandler(w http.ResponseWriter, r *http.Request) {
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	currentUser := models.GetUserFromContext(r)
+
+	rowsAffected, err := s.searchesRepo.DeleteSavedSearch(r.Context(), currentUser.ID, id)
+	if err != nil {
+		slog.ErrorContext(
+			r.Context(), "failed to delete the saved search",
+			"path", r.URL.Path,
+			"userId", currentUser.ID,
+			"error", err,
+		)
+		utils.HttpError(w, http.StatusInternalServerError)
+		return
+	}
+
+	if rowsAffected == 0 {
+		http.NotFound(w, r)
+		return
+	}
+
+	// The unread notifications of the search are gone too
+	s.clearUnreadCache(r, currentUser.ID)
+
+	message := models.FlashMessage{
+		Message:  "The saved search has been deleted!",
+		Category: "info",
+	}
+
+	s.ui.StoreFlashMessage(w, r, &message)
+	http.Redirect(w, r, "/user/searches/", http.StatusSeeOther)
+}
+
+// Handle the user API tokens page
+func (s *Service) TokensHandler(w http.ResponseWriter, r *http.Request) {
+	s.renderTokens(w, r, "")
+}
+
+// Create a new API token of the user, show it once on the tokens page
+func (s *Service) NewTokenHandler(w http.ResponseWriter, r *http.Request) {
+
+	if err := r.ParseForm(); err != nil {
+		utils.HttpError(w, http.StatusBadRequest)
+		return
+	}
+
+	currentUser := models.GetUserFromContext(r)
+
+	// A leaked token must not be able to mint more tokens
+	if currentUser.IsTokenAuth() {
+		utils.HttpError(w, http.StatusForbidden)
+		return
+	}
+
+	days, err := strconv.Atoi(r.FormValue("expires"))
+	if err != nil || !slices.Contains(tokenExpiryDays, days) {
+		utils.HttpError(w, http.StatusBadRequest)
+		return
+	}
+
+	token := models.APIToken{
+		UserID: currentUser.ID,
+		Name:   tokenName(r.FormValue("name")),
+		Scopes: models.ParseScopes(r.Form["scopes"]),
+	}
+
+	// Only the admin can grant the admin scope
+	if !currentUser.IsAdmin() {
+		token.Scopes = slices.DeleteFunc(token.Scopes, func(scope string) bool {
+			return scope == models.ScopeAdmin
+		})
+	}
+
+	if days > 0 {
+		expiresAt := time.Now().AddDate(0, 0, days)
+		token.ExpiresAt = &expiresAt
+	}
+
+	message := models.FlashMessage{Category: "info"}
+	switch {
+	case token.Name == "":
+		message.Message = "Please name the token."
+	case len(token.Scopes) == 0:
+		message.Message = "Please pick at least one scope."
+	}
+
+	if message.Message != "" {
+		s.ui.StoreFlashMessage(w, r, &message)
+		http.Redirect(w, r, "/user/tokens/", http.StatusSeeOther)
+		return
+	}
+
+	tokens, err := s.usersRepo.GetUserTokens(r.Context(), currentUser.ID)
+	if err != nil {
+		slog.ErrorContext(
+			r.Context(), "failed to get user's API tokens from DB",
+			"path", r.URL.Path,
+			"userId", currentUser.ID,
+			"error", err,
+		)
+		utils.HttpError(w, http.StatusInternalServerError)
+		return
+	}
+
+	if len(tokens) >= maxTokens {
+		message.Message = fmt.Sprintf(
+			"You can have up to %d tokens, please revoke some first.",
+			maxTokens,
+		)
+		s.ui.StoreFlashMessage(w, r, &message)
+		http.Redirect(w, r, "/user/tokens/", http.StatusSeeOther)
+		return
+	}
+
+	value, hash := models.NewToken()
+	token.Prefix = value[:len(models.TokenPrefix)+6]
+
+	if _, err = s.usersRepo.InsertToken(r.Context(), token, hash); err != nil {
+		slog.ErrorContext(
+			r.Context(), "failed to insert the API token",
+			"path", r.URL.Path,
+			"userId", currentUser.ID,
+			"error", err,
+		)
+		utils.HttpError(w, http.StatusInternalServerError)
+		return
+	}
+
+	// Render rather than redirect, the token is not stored anywhere to show it later
+	s.renderTokens(w, r, value)
+}
+
+// Revoke an API token of the user
+func (s *Service) DeleteTokenHandler(w http.ResponseWriter, r *http.Request) {
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	currentUser := models.GetUserFromContext(r)
+
+	rowsAffected, err := s.usersRepo.DeleteToken(r.Context(), currentUser.ID, id)
+	if err != nil {
+		slog.ErrorContext(
+			r.Context(), "failed to delete the API token",
+			"path", r.URL.Path,
+			"userId", currentUser.ID,
+			"error", err,
+		)
+		utils.HttpError(w, http.StatusInternalServerError)
+		return
+	}
+
+	if rowsAffected == 0 {
+		http.NotFound(w, r)
+		return
+	}
+
+	message := models.FlashMessage{
+		Message:  "The API token has been revoked!",
+		Category: "info",
+	}
+
+	s.ui.StoreFlashMessage(w, r, &message)
+	http.Redirect(w, r, "/user/tokens/", http.StatusSeeOther)
+}
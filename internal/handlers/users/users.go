@@ -5,33 +5,37 @@ import (
 	"github.com/vlatan/video-store/internal/drivers/rdb"
 	"github.com/vlatan/video-store/internal/integrations/r2"
 	postsRepo "github.com/vlatan/video-store/internal/repositories/posts"
+	searchesRepo "github.com/vlatan/video-store/internal/repositories/searches"
 	usersRepo "github.com/vlatan/video-store/internal/repositories/users"
 	"github.com/vlatan/video-store/internal/ui"
 )
 
 type Service struct {
-	usersRepo *usersRepo.Repository
-	postsRepo *postsRepo.Repository
-	rdb       *rdb.Service
-	r2s       r2.Service
-	ui        ui.Service
-	config    *config.Config
+	usersRepo    *usersRepo.Repository
+	postsRepo    *postsRepo.Repository
+	searchesRepo *searchesRepo.Repository
+	rdb          *rdb.Service
+	r2s          r2.Service
+	ui           ui.Service
+	config       *config.Config
 }
 
 func New(
 	usersRepo *usersRepo.Repository,
 	postsRepo *postsRepo.Repository,
+	searchesRepo *searchesRepo.Repository,
 	rdb *rdb.Service,
 	r2s r2.Service,
 	ui ui.Service,
 	config *config.Config,
 ) *Service {
 	return &Service{
-		usersRepo: usersRepo,
-		postsRepo: postsRepo,
-		rdb:       rdb,
-		r2s:       r2s,
-		ui:        ui,
-		config:    config,
+		usersRepo:    usersRepo,
+		postsRepo:    postsRepo,
+		searchesRepo: searchesRepo,
+		rdb:          rdb,
+		r2s:          r2s,
+		ui:           ui,
+		config:       config,
 	}
 }
@@ -1,9 +1,12 @@
 package users
 
 import (
+	"fmt"
 	"log/slog"
 	"net/http"
 	"runtime"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/vlatan/video-store/internal/models"
@@ -53,3 +56,74 @@ func (s *Service) SetAvatars(
 
 	return g.Wait()
 }
+
+// Mark the user's notifications as read
+func (s *Service) readNotifications(r *http.Request, userID int) {
+
+	if _, err := s.searchesRepo.ReadNotifications(r.Context(), userID); err != nil {
+		slog.ErrorContext(
+			r.Context(), "failed to mark the notifications as read",
+			"path", r.URL.Path,
+			"userId", userID,
+			"error", err,
+		)
+		return
+	}
+
+	s.clearUnreadCache(r, userID)
+}
+
+// Delete the cached number of the user's unread notifications
+func (s *Service) clearUnreadCache(r *http.Request, userID int) {
+
+	redisKey := fmt.Sprintf(models.UnreadNotificationsKey, userID)
+	if err := s.rdb.Client.Del(r.Context(), redisKey).Err(); err != nil {
+		slog.ErrorContext(
+			r.Context(), "failed to delete the cache on notifications",
+			"path", r.URL.Path,
+			"userId", userID,
+			"error", err,
+		)
+	}
+}
+
+// Render the user's API tokens page, with the just created token if any
+func (s *Service) renderTokens(w http.ResponseWriter, r *http.Request, newToken string) {
+
+	// Generate template data
+	data := models.GetDataFromContext(r)
+
+	tokens, err := s.usersRepo.GetUserTokens(r.Context(), data.CurrentUser.ID)
+	if err != nil {
+		slog.ErrorContext(
+			r.Context(), "failed to get user's API tokens from DB",
+			"path", r.URL.Path,
+			"userId", data.CurrentUser.ID,
+			"error", err,
+		)
+		utils.HttpError(w, http.StatusInternalServerError)
+		return
+	}
+
+	// Only the admin can grant the admin scope
+	data.TokenScopes = models.TokenScopes
+	if !data.CurrentUser.IsAdmin() {
+		data.TokenScopes = slices.DeleteFunc(slices.Clone(models.TokenScopes), func(scope string) bool {
+			return scope == models.ScopeAdmin
+		})
+	}
+
+	data.APITokens = tokens
+	data.NewAPIToken = newToken
+	data.Title = "Your API Tokens"
+	s.ui.RenderHTML(w, r, "tokens.html", data)
+}
+
+// Collapse the whitespace in the token name and truncate it
+func tokenName(name string) string {
+	name = strings.Join(strings.Fields(name), " ")
+	if runes := []rune(name); len(runes) > maxTokenNameLength {
+		name = strings.TrimSpace(string(runes[:maxTokenNameLength]))
+	}
+	return name
+}
@@ -2,6 +2,8 @@ package gemini
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/vlatan/video-store/internal/models"
@@ -55,3 +57,26 @@ func (s *Service) MakeTextContents(video *models.Post) []*genai.Content {
 		genai.NewContentFromParts(parts, genai.RoleUser),
 	}
 }
+
+// Maximum length of the editor's guidance in characters
+const maxGuidanceLength = 500
+
+// WithGuidance appends the editor's extra guidance to a copy of the contents.
+// Returns the contents as they are if there's no guidance.
+func (s *Service) WithGuidance(contents []*genai.Content, guidance string) []*genai.Content {
+
+	guidance = strings.TrimSpace(guidance)
+	if guidance == "" {
+		return contents
+	}
+
+	// Keep the guidance reasonably short
+	if runes := []rune(guidance); len(runes) > maxGuidanceLength {
+		guidance = string(runes[:maxGuidanceLength])
+	}
+
+	text := "Additional guidance from the editor, follow it as long as " +
+		"it doesn't conflict with your instructions: " + sanitizePrompt(guidance)
+
+	return append(slices.Clone(contents), genai.NewContentFromText(text, genai.RoleUser))
+}
@@ -0,0 +1,77 @@
+package gemini
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/vlatan/video-store/internal/models"
+	"github.com/vlatan/video-store/internal/utils"
+	"google.golang.org/genai"
+)
+
+// EmbedPosts creates embeddings for the title and summary of the given posts.
+// Retries number of times depending on the retry config passed.
+// The embeddings are returned in the same order as the posts.
+func (s *Service) EmbedPosts(
+	ctx context.Context,
+	posts []*models.Post,
+	rc *utils.RetryConfig,
+) ([][]float32, error) {
+
+	if len(posts) == 0 {
+		return nil, nil
+	}
+
+	contents := make([]*genai.Content, len(posts))
+	for i, post := range posts {
+		contents[i] = genai.NewContentFromText(embeddingText(post), genai.RoleUser)
+	}
+
+	dimensions := s.config.EmbeddingDimensions
+	embedConfig := &genai.EmbedContentConfig{
+		TaskType:             "SEMANTIC_SIMILARITY",
+		OutputDimensionality: &dimensions,
+	}
+
+	// Make the API call
+	result, err := utils.Retry(ctx, rc,
+		func() (*genai.EmbedContentResponse, error) {
+			return s.client.Models.EmbedContent(
+				ctx,
+				s.config.GeminiEmbeddingModel,
+				contents,
+				embedConfig,
+			)
+		},
+		utils.IsContextErr,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result.Embeddings) != len(posts) {
+		return nil, fmt.Errorf(
+			"expected %d embeddings, got %d",
+			len(posts), len(result.Embeddings),
+		)
+	}
+
+	embeddings := make([][]float32, len(result.Embeddings))
+	for i, embedding := range result.Embeddings {
+		embeddings[i] = embedding.Values
+	}
+
+	return embeddings, nil
+}
+
+// embeddingText prepares the text to be embedded for a given post
+func embeddingText(post *models.Post) string {
+	var sb strings.Builder
+	sb.WriteString("Title: " + sanitizePrompt(post.GetTitle()))
+	if post.Summary != "" {
+		sb.WriteString("\nSummary: " + sanitizePrompt(post.Summary))
+	}
+	return sb.String()
+}
@@ -2,7 +2,9 @@ package gemini
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/vlatan/video-store/internal/models"
 	"google.golang.org/genai"
 )
 
@@ -24,3 +26,18 @@ func (b *BlockedError) Error() string {
 		b.Feedback.BlockReason,
 	)
 }
+
+// QualityError is returned when the generated content
+// fails the quality checks even after the corrective prompts
+type QualityError struct {
+	Issues   []string
+	Response *models.GenaiResponse
+}
+
+// Implement error interface
+func (q *QualityError) Error() string {
+	return fmt.Sprintf(
+		"gemini response failed the quality checks: %s",
+		strings.Join(q.Issues, "; "),
+	)
+}
@@ -2,11 +2,14 @@ package gemini
 
 import (
 	"context"
+	"log/slog"
+	"slices"
 
 	"github.com/vlatan/video-store/internal/config"
 	"github.com/vlatan/video-store/internal/drivers/rdb"
 	"github.com/vlatan/video-store/internal/models"
 	"github.com/vlatan/video-store/internal/repositories/categories"
+	"github.com/vlatan/video-store/internal/tracing"
 
 	"google.golang.org/genai"
 )
@@ -18,8 +21,11 @@ func New(
 	redisService *rdb.Service,
 	catsRepo *categories.Repository) (*Service, error) {
 
-	// Configure new client
-	client, err := genai.NewClient(ctx, &genai.ClientConfig{APIKey: cfg.GeminiAPIKey})
+	// Configure new client, tracing the API calls
+	client, err := genai.NewClient(ctx, &genai.ClientConfig{
+		APIKey:     cfg.GeminiAPIKey,
+		HTTPClient: tracing.Client(nil),
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -31,35 +37,86 @@ func New(
 	}
 
 	s := &Service{
-		config:  cfg,
-		client:  client,
-		limiter: limiter,
+		config:   cfg,
+		client:   client,
+		limiter:  limiter,
+		rdb:      redisService,
+		catsRepo: catsRepo,
 	}
 
+	// Configure genai with the current categories
+	if err = s.refreshCategories(ctx); err != nil {
+		return nil, err
+	}
+
+	// Configure genai for translations
+	temp, topP := float32(0.0), float32(0.1)
+	s.transConfig = &genai.GenerateContentConfig{
+		Temperature:      &temp,
+		TopP:             &topP,
+		ResponseMIMEType: "application/json",
+		SafetySettings:   safetySettings,
+		ResponseSchema:   translationSchema(),
+	}
+
+	return s, nil
+}
+
+// ConsumeQuota attempts to consume 1 request from the daily and minute buckets.
+// It returns a sentinel error if any of the quotas are full.
+func (s *Service) ConsumeQuota(ctx context.Context) error {
+	return s.limiter.ConsumeQuota(ctx)
+}
+
+// Exhausted returns true if the daily limit has already been hit
+func (s *Service) Exhausted(ctx context.Context) bool {
+	return s.limiter.Exhausted(ctx)
+}
+
+// Usage returns the requests spent today and in the current minute
+func (s *Service) Usage(ctx context.Context) (daily, minute int64, err error) {
+	return s.limiter.Usage(ctx)
+}
+
+// refreshCategories reconfigures genai with the categories from cache or DB.
+// Busting the categories cache updates the category enum on all the replicas,
+// the config is rebuilt only if the categories changed.
+func (s *Service) refreshCategories(ctx context.Context) error {
+
 	// Get the categories from cache or DB
 	categories, err := rdb.GetCachedData(
 		ctx,
-		redisService,
+		s.rdb,
 		"categories",
 		s.config.CacheTimeout,
-		func() (models.Categories, error) {
-			return catsRepo.GetCategories(ctx)
+		func(ctx context.Context) (models.Categories, error) {
+			return s.catsRepo.GetCategories(ctx)
 		},
+		rdb.WithTags(rdb.CategoriesTag),
 	)
 
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	// Extract the category names
-	s.catNames = make([]string, len(categories))
+	catNames := make([]string, len(categories))
 	for i, cat := range categories {
-		s.catNames[i] = cat.Name
+		catNames[i] = cat.Name
+	}
+
+	// Nothing changed since the last refresh
+	s.mu.RLock()
+	unchanged := s.genaiConfig != nil && slices.Equal(s.catNames, catNames)
+	s.mu.RUnlock()
+
+	if unchanged {
+		return nil
 	}
 
 	// Configure genai
 	temp, topP := float32(0.0), float32(0.1)
-	s.genaiConfig = &genai.GenerateContentConfig{
+	genaiConfig := &genai.GenerateContentConfig{
 		Temperature: &temp,
 		TopP:        &topP,
 
@@ -68,21 +125,29 @@ func New(
 		// Tools: []*genai.Tool{{GoogleSearch: &genai.GoogleSearch{}}},
 
 		SafetySettings:    safetySettings,
-		ResponseSchema:    s.responseSchema(),
+		ResponseSchema:    responseSchema(catNames),
 		SystemInstruction: s.systemInstruction(),
 		MediaResolution:   genai.MediaResolutionLow,
 	}
 
-	return s, nil
-}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.catNames = catNames
+	s.genaiConfig = genaiConfig
 
-// ConsumeQuota attempts to consume 1 request from the daily and minute buckets.
-// It returns a sentinel error if any of the quotas are full.
-func (s *Service) ConsumeQuota(ctx context.Context) error {
-	return s.limiter.ConsumeQuota(ctx)
+	return nil
 }
 
-// Exhausted returns true if the daily limit has already been hit
-func (s *Service) Exhausted(ctx context.Context) bool {
-	return s.limiter.Exhausted(ctx)
+// categories returns the current category names and the genai config using them.
+// They are refreshed from the cache first, so the categories accepted on another
+// replica are used too. If the refresh fails the last known ones are used.
+func (s *Service) categories(ctx context.Context) ([]string, *genai.GenerateContentConfig) {
+
+	if err := s.refreshCategories(ctx); err != nil {
+		slog.ErrorContext(ctx, "failed to refresh the gemini categories", "error", err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.catNames, s.genaiConfig
 }
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/vlatan/video-store/internal/models"
 	"github.com/vlatan/video-store/internal/utils"
@@ -17,6 +19,7 @@ import (
 func (s *Service) generateContent(
 	ctx context.Context,
 	contents []*genai.Content,
+	genaiConfig *genai.GenerateContentConfig,
 ) (*genai.GenerateContentResponse, error) {
 
 	// Consume minute and daily quotas before calling the API
@@ -28,7 +31,7 @@ func (s *Service) generateContent(
 		ctx,
 		s.config.GeminiModel,
 		contents,
-		s.genaiConfig,
+		genaiConfig,
 	)
 
 	if err != nil {
@@ -47,6 +50,8 @@ func (s *Service) generateContent(
 // GenerateContent generates content using Gemini.
 // Retries number of times depending on the retry config passed.
 // Unmarshals the result if any and returns a genai response object.
+// If the response fails the quality checks the model is re-prompted
+// with the issues found, up to the configured number of corrections.
 func (s *Service) GenerateContent(
 	ctx context.Context,
 	video *models.Post,
@@ -54,30 +59,58 @@ func (s *Service) GenerateContent(
 	rc *utils.RetryConfig,
 ) (*models.GenaiResponse, error) {
 
-	// Make the API call
-	result, err := utils.Retry(ctx, rc,
-		func() (*genai.GenerateContentResponse, error) {
-			return s.generateContent(ctx, contents)
-		},
-		// Exit immediately if no candidates returned or RPD limit reached
-		func(err error) bool {
-			var target *BlockedError
-			return errors.As(err, &target) || errors.Is(err, ErrDailyLimitReached)
-		},
-	)
+	// Don't mutate the caller's contents with the corrective prompts
+	contents = slices.Clone(contents)
 
-	if err != nil {
-		return nil, err
-	}
+	// Stick to the same categories during the corrections
+	catNames, genaiConfig := s.categories(ctx)
 
-	var response models.GenaiResponse
-	if err = json.Unmarshal([]byte(result.Text()), &response); err != nil {
-		return nil, err
-	}
+	for correction := 0; ; correction++ {
+
+		// Make the API call
+		result, err := utils.Retry(ctx, rc,
+			func() (*genai.GenerateContentResponse, error) {
+				return s.generateContent(ctx, contents, genaiConfig)
+			},
+			// Exit immediately if no candidates returned or RPD limit reached
+			func(err error) bool {
+				var target *BlockedError
+				return errors.As(err, &target) || errors.Is(err, ErrDailyLimitReached)
+			},
+		)
 
-	response.Title = utils.NormalizeTitle(response.Title, utils.VideoTitleCutoffs)
-	response.OriginalTitle = utils.NormalizeTitle(response.OriginalTitle, utils.VideoTitleCutoffs)
-	response.Summary = utils.NormalizeDescription(response.Summary)
+		if err != nil {
+			return nil, err
+		}
+
+		var response models.GenaiResponse
+		if err = json.Unmarshal([]byte(result.Text()), &response); err != nil {
+			return nil, err
+		}
+
+		// Check the raw response before normalizing it
+		issues := validateResponse(&response, catNames)
+		if len(issues) == 0 {
+			response.Title = utils.NormalizeTitle(response.Title, utils.VideoTitleCutoffs)
+			response.OriginalTitle = utils.NormalizeTitle(response.OriginalTitle, utils.VideoTitleCutoffs)
+			response.Summary = utils.NormalizeDescription(response.Summary)
+			response.SuggestedCategory = normalizeSuggestion(response.SuggestedCategory, catNames)
+			return &response, nil
+		}
+
+		if correction >= s.config.GeminiMaxCorrections {
+			return nil, &QualityError{Issues: issues, Response: &response}
+		}
+
+		// Re-prompt the model with the issues found
+		contents = append(contents, result.Candidates[0].Content, correctionContent(issues))
+	}
+}
 
-	return &response, nil
+// correctionContent creates a corrective prompt listing the issues found
+func correctionContent(issues []string) *genai.Content {
+	text := "Your previous answer has the following problems:\n- " +
+		strings.Join(issues, "\n- ") +
+		"\nFix all of them and answer again with the complete JSON."
+	return genai.NewContentFromText(text, genai.RoleUser)
 }
@@ -3,6 +3,7 @@ package gemini
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
 	_ "time/tzdata" // embed the timezone database into the binary
@@ -85,3 +86,25 @@ func (gl *GeminiLimiter) Exhausted(ctx context.Context) bool {
 
 	return val >= gl.cfg.GeminiRPD
 }
+
+// Usage returns the requests spent today and in the current minute
+func (gl *GeminiLimiter) Usage(ctx context.Context) (daily, minute int64, err error) {
+	now := time.Now().In(gl.loc)
+	dailyKey := rpd + now.Format("2006-01-02")
+	minuteKey := rpm + now.Format("2006-01-02-15-04")
+
+	values, err := gl.rdb.Client.MGet(ctx, dailyKey, minuteKey).Result()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// The missing keys are nil, nothing spent yet
+	counts := make([]int64, len(values))
+	for i, v := range values {
+		if s, ok := v.(string); ok {
+			counts[i], _ = strconv.ParseInt(s, 10, 64)
+		}
+	}
+
+	return counts[0], counts[1], nil
+}
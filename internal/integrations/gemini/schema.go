@@ -4,8 +4,8 @@ import (
 	"google.golang.org/genai"
 )
 
-// produceSchema defines the JSON schema for the response
-func (s *Service) responseSchema() *genai.Schema {
+// responseSchema defines the JSON schema for the response
+func responseSchema(catNames []string) *genai.Schema {
 	return &genai.Schema{
 		Type: genai.TypeObject,
 		Properties: map[string]*genai.Schema{
@@ -30,9 +30,15 @@ func (s *Service) responseSchema() *genai.Schema {
 			},
 			"category": {
 				Type:        genai.TypeString,
-				Enum:        s.catNames,
+				Enum:        catNames,
 				Description: "Select only ONE category.",
 			},
+			"suggested_category": {
+				Type: genai.TypeString,
+				Description: "Only if none of the categories fits the video well, " +
+					"propose a new short and broad category name in title case. " +
+					"Otherwise leave it empty.",
+			},
 		},
 		Required: []string{"summary", "category"},
 	}
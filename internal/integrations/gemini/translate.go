@@ -0,0 +1,83 @@
+package gemini
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/vlatan/video-store/internal/models"
+	"github.com/vlatan/video-store/internal/utils"
+	"google.golang.org/genai"
+)
+
+// translationSchema defines the JSON schema for the translation response
+func translationSchema() *genai.Schema {
+	return &genai.Schema{
+		Type: genai.TypeObject,
+		Properties: map[string]*genai.Schema{
+			"title": {
+				Type:        genai.TypeString,
+				Description: "The translated title. Keep the proper names as they are.",
+			},
+			"summary": {
+				Type:        genai.TypeString,
+				Description: "The translated summary. Keep the tone, the meaning and the paragraph structure.",
+			},
+		},
+		Required: []string{"title", "summary"},
+	}
+}
+
+// Translate translates the post's title and summary into the given locale.
+// Retries number of times depending on the retry config passed.
+func (s *Service) Translate(
+	ctx context.Context,
+	post *models.Post,
+	locale string,
+	rc *utils.RetryConfig,
+) (*models.GenaiTranslation, error) {
+
+	parts := []*genai.Part{
+		genai.NewPartFromText(fmt.Sprintf(
+			"Translate the following title and summary from English "+
+				"into the language with the BCP 47 code %q.", locale,
+		)),
+		genai.NewPartFromText("Title: " + post.GetTitle()),
+		genai.NewPartFromText("Summary: " + post.Summary),
+	}
+
+	contents := []*genai.Content{
+		genai.NewContentFromParts(parts, genai.RoleUser),
+	}
+
+	// Make the API call
+	result, err := utils.Retry(ctx, rc,
+		func() (*genai.GenerateContentResponse, error) {
+			return s.generateContent(ctx, contents, s.transConfig)
+		},
+		// Exit immediately if no candidates returned or RPD limit reached
+		func(err error) bool {
+			var target *BlockedError
+			return errors.As(err, &target) || errors.Is(err, ErrDailyLimitReached)
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var translation models.GenaiTranslation
+	if err = json.Unmarshal([]byte(result.Text()), &translation); err != nil {
+		return nil, err
+	}
+
+	translation.Title = strings.TrimSpace(translation.Title)
+	translation.Summary = utils.NormalizeDescription(strings.TrimSpace(translation.Summary))
+	if translation.Summary == "" {
+		return nil, errors.New("gemini returned an empty translated summary")
+	}
+
+	return &translation, nil
+}
@@ -1,15 +1,25 @@
 package gemini
 
 import (
+	"sync"
+
 	"github.com/vlatan/video-store/internal/config"
+	"github.com/vlatan/video-store/internal/drivers/rdb"
+	"github.com/vlatan/video-store/internal/repositories/categories"
 	"google.golang.org/genai"
 )
 
 // Gemini service
 type Service struct {
 	config      *config.Config
-	genaiConfig *genai.GenerateContentConfig
+	transConfig *genai.GenerateContentConfig
 	client      *genai.Client
 	limiter     *GeminiLimiter
+	rdb         *rdb.Service
+	catsRepo    *categories.Repository
+
+	// Guards the category names and the genai config using them
+	mu          sync.RWMutex
+	genaiConfig *genai.GenerateContentConfig
 	catNames    []string
 }
@@ -2,11 +2,15 @@ package gemini
 
 import (
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/vlatan/video-store/internal/models"
 )
 
+// Maximum length of a suggested category name in characters
+const maxSuggestionLength = 64
+
 // Find category paragraph
 var catRegex = regexp.MustCompile(`(?i)\bCATEGORY:\s*.*`)
 
@@ -44,3 +48,21 @@ func matchCategory(text string, cats models.Categories) string {
 
 	return ""
 }
+
+// normalizeSuggestion cleans up the suggested category name.
+// Returns an empty string if the name is too long or already a category.
+func normalizeSuggestion(name string, catNames []string) string {
+
+	name = strings.Join(strings.Fields(name), " ")
+	if len([]rune(name)) > maxSuggestionLength {
+		return ""
+	}
+
+	if slices.ContainsFunc(catNames, func(catName string) bool {
+		return strings.EqualFold(catName, name)
+	}) {
+		return ""
+	}
+
+	return name
+}
@@ -1,6 +1,7 @@
 package gemini
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/vlatan/video-store/internal/models"
@@ -85,3 +86,29 @@ func TestParseResponse(t *testing.T) {
 		})
 	}
 }
+
+func TestNormalizeSuggestion(t *testing.T) {
+
+	catNames := []string{"Science", "History"}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"empty", "", ""},
+		{"new category", "Engineering", "Engineering"},
+		{"extra whitespace", "  Space   Exploration ", "Space Exploration"},
+		{"existing category", "Science", ""},
+		{"existing category different case", "history", ""},
+		{"too long", strings.Repeat("Long ", 20), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizeSuggestion(tt.input, catNames); got != tt.expected {
+				t.Errorf("got %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
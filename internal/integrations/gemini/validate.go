@@ -0,0 +1,160 @@
+package gemini
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/vlatan/video-store/internal/models"
+)
+
+// Summary length bounds in words
+const (
+	minSummaryWords = 40
+	maxSummaryWords = 250
+)
+
+// Minimum share of common English words in an English text
+const minEnglishRatio = 0.15
+
+// Find timestamps like 1:23 or 01:23:45
+var timestampRegex = regexp.MustCompile(`\b\d{1,2}:\d{2}(:\d{2})?\b`)
+
+// Find the text referencing the video itself
+var selfReferenceRegex = regexp.MustCompile(
+	`(?i)\b(this|the) (video|documentary|film|clip|footage)\b`,
+)
+
+// Find three or more consecutive uppercase words
+var shoutingRegex = regexp.MustCompile(`\b[A-Z]{2,}(\s+[A-Z]{2,}){2,}\b`)
+
+// Banned characters or phrases in the summary
+var bannedPhrases = []string{"—", "**", "##", "http://", "https://"}
+
+// Common English words used to detect the language of a text
+var englishWords = map[string]bool{
+	"the": true, "of": true, "and": true, "a": true, "to": true,
+	"in": true, "is": true, "was": true, "that": true, "for": true,
+	"it": true, "as": true, "with": true, "his": true, "her": true,
+	"their": true, "on": true, "by": true, "at": true, "from": true,
+	"an": true, "this": true, "who": true, "which": true, "are": true,
+	"were": true, "be": true, "has": true, "had": true, "its": true,
+}
+
+// Words that can stay lowercase in a title case title
+var titleSmallWords = map[string]bool{
+	"a": true, "an": true, "the": true, "and": true, "but": true,
+	"or": true, "nor": true, "for": true, "so": true, "yet": true,
+	"at": true, "by": true, "in": true, "of": true, "off": true,
+	"on": true, "out": true, "to": true, "up": true, "as": true,
+	"per": true, "via": true, "vs": true, "vs.": true, "from": true,
+	"into": true, "with": true,
+}
+
+// validateResponse checks the raw genai response against the quality rules.
+// Returns a list of human readable issues, empty if the response is fine.
+func validateResponse(res *models.GenaiResponse, catNames []string) []string {
+
+	var issues []string
+
+	words := strings.Fields(res.Summary)
+	switch {
+	case len(words) < minSummaryWords:
+		issues = append(issues, fmt.Sprintf(
+			"the summary is too short (%d words), write at least %d words",
+			len(words), minSummaryWords,
+		))
+	case len(words) > maxSummaryWords:
+		issues = append(issues, fmt.Sprintf(
+			"the summary is too long (%d words), write at most %d words",
+			len(words), maxSummaryWords,
+		))
+	}
+
+	for _, phrase := range bannedPhrases {
+		if strings.Contains(res.Summary, phrase) {
+			issues = append(issues, fmt.Sprintf("the summary contains %q", phrase))
+		}
+	}
+
+	if timestampRegex.MatchString(res.Summary) {
+		issues = append(issues, "the summary contains timestamps")
+	}
+
+	if shoutingRegex.MatchString(res.Summary) {
+		issues = append(issues, "the summary contains uppercase formatting")
+	}
+
+	if selfReferenceRegex.MatchString(res.Summary) {
+		issues = append(issues, "the summary references the video itself")
+	}
+
+	if len(words) > 0 && !isEnglish(words) {
+		issues = append(issues, "the summary is not written in English")
+	}
+
+	if res.OriginalTitle != "" && !isTitleCase(res.OriginalTitle) {
+		issues = append(issues, "the original title is not in title case")
+	}
+
+	if !slices.Contains(catNames, res.Category) {
+		issues = append(issues, fmt.Sprintf(
+			"the category %q is not one of the allowed categories",
+			res.Category,
+		))
+	}
+
+	return issues
+}
+
+// isEnglish guesses if the words are English by the share of common English words
+func isEnglish(words []string) bool {
+	var common int
+	for _, word := range words {
+		word = strings.ToLower(strings.TrimFunc(word, unicode.IsPunct))
+		if englishWords[word] {
+			common++
+		}
+	}
+
+	return float64(common)/float64(len(words)) >= minEnglishRatio
+}
+
+// isTitleCase checks if every significant word in the title is capitalized,
+// and the title is not written entirely in uppercase.
+func isTitleCase(title string) bool {
+
+	if strings.ToUpper(title) == title && strings.ToLower(title) != title {
+		return false
+	}
+
+	for i, word := range strings.Fields(title) {
+
+		// Find the first letter in the word
+		var first rune
+		for _, r := range word {
+			if unicode.IsLetter(r) {
+				first = r
+				break
+			}
+		}
+
+		// Skip words without letters, numbers or symbols
+		if first == 0 {
+			continue
+		}
+
+		// Small words can be lowercase if not the first word
+		if i > 0 && titleSmallWords[strings.ToLower(word)] {
+			continue
+		}
+
+		if unicode.IsLower(first) {
+			return false
+		}
+	}
+
+	return true
+}
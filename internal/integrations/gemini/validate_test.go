@@ -0,0 +1,99 @@
+package gemini
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/vlatan/video-store/internal/models"
+)
+
+func TestValidateResponse(t *testing.T) {
+
+	catNames := []string{"Science", "History"}
+	goodSummary := strings.Repeat("The scientists of the expedition traveled to the remote island. ", 5)
+
+	tests := []struct {
+		name     string
+		response models.GenaiResponse
+		issues   int
+	}{
+		{
+			"valid response",
+			models.GenaiResponse{
+				OriginalTitle: "The Secrets of the Deep",
+				Summary:       goodSummary,
+				Category:      "Science",
+			},
+			0,
+		},
+		{
+			"too short summary",
+			models.GenaiResponse{Summary: "The island of the expedition.", Category: "Science"},
+			1,
+		},
+		{
+			"banned em dash and timestamp",
+			models.GenaiResponse{Summary: goodSummary + "At 12:30 — a storm hit.", Category: "History"},
+			2,
+		},
+		{
+			"self reference",
+			models.GenaiResponse{Summary: goodSummary + "This documentary explores it.", Category: "History"},
+			1,
+		},
+		{
+			"uppercase formatting",
+			models.GenaiResponse{Summary: goodSummary + "THE FINAL FRONTIER awaits.", Category: "History"},
+			1,
+		},
+		{
+			"not english",
+			models.GenaiResponse{
+				Summary:  strings.Repeat("Los científicos viajaron a una isla remota durante meses. ", 6),
+				Category: "Science",
+			},
+			1,
+		},
+		{
+			"lowercase original title",
+			models.GenaiResponse{OriginalTitle: "secrets of the deep", Summary: goodSummary, Category: "Science"},
+			1,
+		},
+		{
+			"unknown category",
+			models.GenaiResponse{Summary: goodSummary, Category: "Cooking"},
+			1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := validateResponse(&tt.response, catNames)
+			if len(got) != tt.issues {
+				t.Errorf("got %d issues %q, want %d", len(got), got, tt.issues)
+			}
+		})
+	}
+}
+
+func TestIsTitleCase(t *testing.T) {
+	tests := []struct {
+		name, input string
+		expected    bool
+	}{
+		{"title case", "The Rise and Fall of Rome", true},
+		{"title with subtitle", "Rome: The Rise of an Empire", true},
+		{"lowercase word", "The rise and Fall of Rome", false},
+		{"lowercase first small word", "the Rise of Rome", false},
+		{"all uppercase", "THE RISE OF ROME", false},
+		{"numbers and quotes", "1945: 'Operation Downfall'", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isTitleCase(tt.input); got != tt.expected {
+				t.Errorf("got %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package indexnow
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/vlatan/video-store/internal/config"
+	indexNowRepo "github.com/vlatan/video-store/internal/repositories/indexnow"
+	"github.com/vlatan/video-store/internal/tracing"
+)
+
+const (
+	batchSize      = 10_000 // URLs per submission, the protocol's maximum
+	maxAttempts    = 5      // failed submissions before a URL is given up
+	requestTimeout = 30 * time.Second
+)
+
+type Service struct {
+	config  *config.Config
+	repo    *indexNowRepo.Repository
+	client  *http.Client
+	baseURL string
+}
+
+// Create new IndexNow service notifying the search engines of the changed URLs
+func New(config *config.Config, repo *indexNowRepo.Repository) *Service {
+
+	client := tracing.Client(nil)
+	client.Timeout = requestTimeout
+
+	return &Service{
+		config:  config,
+		repo:    repo,
+		client:  client,
+		baseURL: fmt.Sprintf("%s://%s", config.Protocol, config.Domain),
+	}
+}
+
+// Enabled reports whether the URLs are submitted
+func (s *Service) Enabled() bool {
+	return s.config.IndexNowKey != "" && len(s.config.IndexNowURLs) > 0
+}
+
+// KeyPath is the path of the key file verifying the site ownership
+func (s *Service) KeyPath() string {
+	return "/" + s.config.IndexNowKey + ".txt"
+}
+
+// Queue the changed paths for the next submission.
+// The queueing is best effort, the errors are only logged.
+func (s *Service) Queue(ctx context.Context, paths ...string) {
+
+	if !s.Enabled() || len(paths) == 0 {
+		return
+	}
+
+	urls := make([]string, len(paths))
+	for i, path := range paths {
+		urls[i] = s.baseURL + path
+	}
+
+	if _, err := s.repo.QueueURLs(ctx, urls); err != nil {
+		slog.ErrorContext(
+			ctx, "failed to queue the IndexNow URLs",
+			"urls", urls,
+			"error", err,
+		)
+	}
+}
+
+// QueuePosts queues the post pages in all the locales
+func (s *Service) QueuePosts(ctx context.Context, videoIDs ...string) {
+
+	paths := make([]string, 0, len(videoIDs)*(1+len(s.config.Locales)))
+	for _, videoID := range videoIDs {
+		paths = append(paths, fmt.Sprintf("/video/%s/", videoID))
+		for _, locale := range s.config.Locales {
+			paths = append(paths, fmt.Sprintf("/%s/video/%s/", locale, videoID))
+		}
+	}
+
+	s.Queue(ctx, paths...)
+}
+
+// Submit the pending URLs in batches, returns the number of submitted URLs.
+// Stops on the first failed batch, its URLs are retried on the next run.
+func (s *Service) Submit(ctx context.Context) (int, error) {
+
+	if !s.Enabled() {
+		return 0, nil
+	}
+
+	var submitted int
+	for {
+
+		urls, queuedAt, err := s.repo.PendingURLs(ctx, maxAttempts, batchSize)
+		if err != nil || len(urls) == 0 {
+			return submitted, err
+		}
+
+		if err = s.send(ctx, urls); err != nil {
+			if _, markErr := s.repo.MarkFailed(ctx, urls, err.Error()); markErr != nil {
+				return submitted, fmt.Errorf("%w; %w", err, markErr)
+			}
+			return submitted, err
+		}
+
+		if _, err = s.repo.MarkSubmitted(ctx, urls, queuedAt); err != nil {
+			return submitted, err
+		}
+
+		submitted += len(urls)
+	}
+}
+
+// IndexNow request body
+// https://www.indexnow.org/documentation
+type submission struct {
+	Host        string   `json:"host"`
+	Key         string   `json:"key"`
+	KeyLocation string   `json:"keyLocation"`
+	URLList     []string `json:"urlList"`
+}
+
+// Send the URLs to all the endpoints
+func (s *Service) send(ctx context.Context, urls []string) error {
+
+	body, err := json.Marshal(submission{
+		Host:        s.config.Domain,
+		Key:         s.config.IndexNowKey,
+		KeyLocation: s.baseURL + s.KeyPath(),
+		URLList:     urls,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	for _, endpoint := range s.config.IndexNowURLs {
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+		resp, err := s.client.Do(req)
+		if err != nil {
+			return err
+		}
+
+		// Drain the body so the connection can be reused
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+		resp.Body.Close()
+
+		// 200 when submitted, 202 when the key is yet to be validated
+		if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+			return fmt.Errorf("%s responded with %s", endpoint, resp.Status)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package indexnow
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/vlatan/video-store/internal/config"
+)
+
+func TestSend(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{"submitted", http.StatusOK, false},
+		{"key pending validation", http.StatusAccepted, false},
+		{"invalid key", http.StatusForbidden, true},
+		{"too many requests", http.StatusTooManyRequests, true},
+	}
+
+	urls := []string{"https://example.com/video/abcdefghijk/", "https://example.com/page/about/"}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// A local stand-in for the search engine
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+				if r.Method != http.MethodPost {
+					t.Errorf("got method %q, want POST", r.Method)
+				}
+
+				var got submission
+				if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+					t.Errorf("failed to decode the submission: %v", err)
+				}
+
+				want := submission{
+					Host:        "example.com",
+					Key:         "abcdef123456",
+					KeyLocation: "https://example.com/abcdef123456.txt",
+					URLList:     urls,
+				}
+
+				if got.Host != want.Host || got.Key != want.Key ||
+					got.KeyLocation != want.KeyLocation || !slices.Equal(got.URLList, want.URLList) {
+					t.Errorf("got submission %+v, want %+v", got, want)
+				}
+
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			s := New(&config.Config{
+				Protocol:     "https",
+				Domain:       "example.com",
+				IndexNowKey:  "abcdef123456",
+				IndexNowURLs: []string{server.URL},
+			}, nil)
+
+			if err := s.send(context.Background(), urls); (err != nil) != tt.wantErr {
+				t.Errorf("got error %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEnabled(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		key      string
+		urls     []string
+		expected bool
+	}{
+		{"no key", "", []string{"https://api.indexnow.org/indexnow"}, false},
+		{"no endpoints", "abcdef123456", nil, false},
+		{"enabled", "abcdef123456", []string{"https://api.indexnow.org/indexnow"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(&config.Config{IndexNowKey: tt.key, IndexNowURLs: tt.urls}, nil)
+			if got := s.Enabled(); got != tt.expected {
+				t.Errorf("got %t, want %t", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vlatan/video-store/internal/config"
+)
+
+// Normalize the line endings to CRLF
+var crlf = strings.NewReplacer("\r\n", "\r\n", "\n", "\r\n")
+
+// ErrDisabled is returned when no SMTP server is configured
+var ErrDisabled = errors.New("the SMTP server is not configured")
+
+type Service struct {
+	config *config.Config
+	addr   string
+	auth   smtp.Auth
+}
+
+// Create new mail service sending plain text emails over SMTP
+func New(config *config.Config) *Service {
+
+	s := &Service{
+		config: config,
+		addr:   net.JoinHostPort(config.SMTPHost, strconv.Itoa(int(config.SMTPPort))),
+	}
+
+	if config.SMTPUsername != "" {
+		s.auth = smtp.PlainAuth("", config.SMTPUsername, config.SMTPPassword, config.SMTPHost)
+	}
+
+	return s
+}
+
+// Enabled reports whether the emails can be sent
+func (s *Service) Enabled() bool {
+	return s.config.SMTPHost != "" && s.config.MailFrom != ""
+}
+
+// Send sends a plain text email to a single recipient
+func (s *Service) Send(ctx context.Context, to, subject, body string) error {
+
+	if !s.Enabled() {
+		return ErrDisabled
+	}
+
+	// net/smtp doesn't take a context, at least check it before sending
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	from, err := mail.ParseAddress(s.config.MailFrom)
+	if err != nil {
+		return fmt.Errorf("invalid sender address %q; %w", s.config.MailFrom, err)
+	}
+
+	recipient, err := mail.ParseAddress(to)
+	if err != nil {
+		return fmt.Errorf("invalid recipient address %q; %w", to, err)
+	}
+
+	msg := message(from, recipient, subject, body, time.Now())
+	return smtp.SendMail(s.addr, s.auth, from.Address, []string{recipient.Address}, msg)
+}
+
+// Compose the email message with the headers
+func message(from, to *mail.Address, subject, body string, date time.Time) []byte {
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", from.String())
+	fmt.Fprintf(&b, "To: %s\r\n", to.String())
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	b.WriteString("\r\n")
+
+	// The SMTP lines end with CRLF
+	b.WriteString(crlf.Replace(body))
+
+	return b.Bytes()
+}
@@ -0,0 +1,58 @@
+package mail
+
+import (
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMessage(t *testing.T) {
+
+	from := &mail.Address{Name: "Video Store", Address: "noreply@example.com"}
+	to := &mail.Address{Address: "user@example.com"}
+	date := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		subject  string
+		body     string
+		contains []string
+	}{
+		{
+			"ascii subject",
+			"New videos",
+			"Hello",
+			[]string{
+				"From: \"Video Store\" <noreply@example.com>\r\n",
+				"To: <user@example.com>\r\n",
+				"Subject: New videos\r\n",
+				"Date: Thu, 02 Jan 2025 03:04:05 +0000\r\n",
+				"\r\n\r\nHello",
+			},
+		},
+		{
+			"encoded subject",
+			"Nouvelles vidéos",
+			"Hello",
+			[]string{"Subject: =?utf-8?q?Nouvelles_vid=C3=A9os?=\r\n"},
+		},
+		{
+			"crlf body",
+			"New videos",
+			"first\nsecond\r\nthird",
+			[]string{"\r\n\r\nfirst\r\nsecond\r\nthird"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := string(message(from, to, tt.subject, tt.body, date))
+			for _, want := range tt.contains {
+				if !strings.Contains(got, want) {
+					t.Errorf("message = %q, want it to contain %q", got, want)
+				}
+			}
+		})
+	}
+}
@@ -9,8 +9,10 @@ import (
 	"time"
 
 	"github.com/vlatan/video-store/internal/config"
+	"github.com/vlatan/video-store/internal/tracing"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
 	awsConfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
@@ -49,7 +51,13 @@ func New(ctx context.Context, cfg *config.Config) (Service, error) {
 	// Create SDK config for an R2 service
 	// An ordinary AWS SDK config would look like:
 	// sdkConfig, err := awsConfig.LoadDefaultConfig(ctx)
+	// The SDK's default HTTP client, tracing the requests
+	buildable := awshttp.NewBuildableClient()
+	httpClient := tracing.Client(buildable.GetTransport())
+	httpClient.Timeout = buildable.GetTimeout()
+
 	sdkConfig, err := awsConfig.LoadDefaultConfig(ctx,
+		awsConfig.WithHTTPClient(httpClient),
 		awsConfig.WithCredentialsProvider(
 			credentials.NewStaticCredentialsProvider(
 				cfg.R2AccessKeyId, cfg.R2SecretAccessKey, ""),
@@ -0,0 +1,89 @@
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"os"
+	"slices"
+
+	"github.com/vlatan/video-store/internal/tracing"
+)
+
+type ctxKey struct{}
+
+// Setup sets the default logger writing JSON lines to stdout.
+// The records get the attributes carried by the context and the trace IDs.
+// The bare log package writes through it too.
+func Setup(debug bool) *slog.Logger {
+
+	var opts *slog.HandlerOptions
+	if debug {
+		opts = &slog.HandlerOptions{Level: slog.LevelDebug}
+	}
+
+	handler := slog.NewJSONHandler(os.Stdout, opts)
+	logger := slog.New(NewHandler(tracing.NewLogHandler(handler)))
+	slog.SetDefault(logger)
+
+	return logger
+}
+
+// With returns a copy of the context carrying the attributes,
+// every record logged with the context, or a context derived from it, gets them.
+// The arguments are the key-value pairs or attributes, like in slog.Logger.With.
+func With(ctx context.Context, args ...any) context.Context {
+
+	var record slog.Record
+	record.Add(args...)
+
+	attrs := slices.Clone(Attrs(ctx))
+	record.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+
+	return context.WithValue(ctx, ctxKey{}, attrs)
+}
+
+// Attrs returns the attributes the context carries
+func Attrs(ctx context.Context) []slog.Attr {
+	attrs, _ := ctx.Value(ctxKey{}).([]slog.Attr)
+	return attrs
+}
+
+// NewID generates a random ID for the requests and the runs
+func NewID() string {
+	b := make([]byte, 8)
+	rand.Read(b) // never returns an error
+	return hex.EncodeToString(b)
+}
+
+// Handler adds the attributes carried by the context to the records
+type Handler struct {
+	slog.Handler
+}
+
+// NewHandler wraps the handler
+func NewHandler(h slog.Handler) *Handler {
+	return &Handler{h}
+}
+
+// Handle adds the context attributes to the record
+func (h *Handler) Handle(ctx context.Context, record slog.Record) error {
+	if attrs := Attrs(ctx); len(attrs) > 0 {
+		record.AddAttrs(attrs...)
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+// WithAttrs keeps the wrapper around the new handler
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &Handler{h.Handler.WithAttrs(attrs)}
+}
+
+// WithGroup keeps the wrapper around the new handler
+func (h *Handler) WithGroup(name string) slog.Handler {
+	return &Handler{h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,75 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+func TestWith(t *testing.T) {
+
+	parent := With(context.Background(), "requestId", "abc")
+	child := With(parent, slog.Int("user", 5))
+
+	if got := len(Attrs(parent)); got != 1 {
+		t.Errorf("got %d attributes in the parent, want 1", got)
+	}
+
+	attrs := Attrs(child)
+	if len(attrs) != 2 {
+		t.Fatalf("got %d attributes in the child, want 2", len(attrs))
+	}
+
+	if attrs[0].Key != "requestId" || attrs[1].Key != "user" {
+		t.Errorf("got attributes %v", attrs)
+	}
+}
+
+func TestHandler(t *testing.T) {
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want any
+	}{
+		{"no attributes", context.Background(), nil},
+		{"context attributes", With(context.Background(), "runId", "xyz"), "xyz"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			var buf bytes.Buffer
+			logger := slog.New(NewHandler(slog.NewJSONHandler(&buf, nil))).With("key", "value")
+			logger.InfoContext(tt.ctx, "message")
+
+			var record map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+				t.Fatal(err)
+			}
+
+			if record["runId"] != tt.want {
+				t.Errorf("got run ID %v, want %v", record["runId"], tt.want)
+			}
+
+			if record["key"] != "value" {
+				t.Error("the logger lost its attributes")
+			}
+		})
+	}
+}
+
+func TestNewID(t *testing.T) {
+
+	id := NewID()
+	if _, err := hex.DecodeString(id); err != nil || len(id) != 16 {
+		t.Errorf("got ID %q, want 16 hex characters", id)
+	}
+
+	if id == NewID() {
+		t.Error("got the same ID twice")
+	}
+}
@@ -0,0 +1,164 @@
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+)
+
+// How long a collector may wait on Redis during a scrape
+const scrapeTimeout = 2 * time.Second
+
+func newDesc(name, help string, labels ...string) *prometheus.Desc {
+	return prometheus.NewDesc(prometheus.BuildFQName(namespace, "", name), help, labels, nil)
+}
+
+// DB POOL
+// ###################################################################
+
+type dbPoolCollector struct {
+	pool             *pgxpool.Pool
+	acquiredConns    *prometheus.Desc
+	idleConns        *prometheus.Desc
+	totalConns       *prometheus.Desc
+	maxConns         *prometheus.Desc
+	acquires         *prometheus.Desc
+	emptyAcquires    *prometheus.Desc
+	canceledAcquires *prometheus.Desc
+	acquireSeconds   *prometheus.Desc
+}
+
+// NewDBPoolCollector reports the pgx pool stats
+func NewDBPoolCollector(pool *pgxpool.Pool) prometheus.Collector {
+	return &dbPoolCollector{
+		pool:             pool,
+		acquiredConns:    newDesc("db_pool_acquired_connections", "Connections currently in use."),
+		idleConns:        newDesc("db_pool_idle_connections", "Idle connections in the pool."),
+		totalConns:       newDesc("db_pool_total_connections", "All the connections in the pool."),
+		maxConns:         newDesc("db_pool_max_connections", "Maximum size of the pool."),
+		acquires:         newDesc("db_pool_acquires_total", "Successful connection acquires."),
+		emptyAcquires:    newDesc("db_pool_empty_acquires_total", "Acquires which waited for a connection."),
+		canceledAcquires: newDesc("db_pool_canceled_acquires_total", "Acquires canceled by the context."),
+		acquireSeconds:   newDesc("db_pool_acquire_seconds_total", "Total time spent acquiring connections."),
+	}
+}
+
+func (c *dbPoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.acquiredConns
+	ch <- c.idleConns
+	ch <- c.totalConns
+	ch <- c.maxConns
+	ch <- c.acquires
+	ch <- c.emptyAcquires
+	ch <- c.canceledAcquires
+	ch <- c.acquireSeconds
+}
+
+func (c *dbPoolCollector) Collect(ch chan<- prometheus.Metric) {
+	stat := c.pool.Stat()
+	ch <- prometheus.MustNewConstMetric(c.acquiredConns, prometheus.GaugeValue, float64(stat.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(c.idleConns, prometheus.GaugeValue, float64(stat.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(c.totalConns, prometheus.GaugeValue, float64(stat.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(c.maxConns, prometheus.GaugeValue, float64(stat.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(c.acquires, prometheus.CounterValue, float64(stat.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.emptyAcquires, prometheus.CounterValue, float64(stat.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.canceledAcquires, prometheus.CounterValue, float64(stat.CanceledAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.acquireSeconds, prometheus.CounterValue, stat.AcquireDuration().Seconds())
+}
+
+// REDIS POOL
+// ###################################################################
+
+type redisPoolCollector struct {
+	client     *redis.Client
+	totalConns *prometheus.Desc
+	idleConns  *prometheus.Desc
+	staleConns *prometheus.Desc
+	hits       *prometheus.Desc
+	misses     *prometheus.Desc
+	timeouts   *prometheus.Desc
+}
+
+// NewRedisPoolCollector reports the go-redis pool stats
+func NewRedisPoolCollector(client *redis.Client) prometheus.Collector {
+	return &redisPoolCollector{
+		client:     client,
+		totalConns: newDesc("redis_pool_total_connections", "All the connections in the pool."),
+		idleConns:  newDesc("redis_pool_idle_connections", "Idle connections in the pool."),
+		staleConns: newDesc("redis_pool_stale_connections_total", "Stale connections removed from the pool."),
+		hits:       newDesc("redis_pool_hits_total", "Times a free connection was found in the pool."),
+		misses:     newDesc("redis_pool_misses_total", "Times a free connection was not found in the pool."),
+		timeouts:   newDesc("redis_pool_timeouts_total", "Times a wait for a connection timed out."),
+	}
+}
+
+func (c *redisPoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.totalConns
+	ch <- c.idleConns
+	ch <- c.staleConns
+	ch <- c.hits
+	ch <- c.misses
+	ch <- c.timeouts
+}
+
+func (c *redisPoolCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.client.PoolStats()
+	ch <- prometheus.MustNewConstMetric(c.totalConns, prometheus.GaugeValue, float64(stats.TotalConns))
+	ch <- prometheus.MustNewConstMetric(c.idleConns, prometheus.GaugeValue, float64(stats.IdleConns))
+	ch <- prometheus.MustNewConstMetric(c.staleConns, prometheus.CounterValue, float64(stats.StaleConns))
+	ch <- prometheus.MustNewConstMetric(c.hits, prometheus.CounterValue, float64(stats.Hits))
+	ch <- prometheus.MustNewConstMetric(c.misses, prometheus.CounterValue, float64(stats.Misses))
+	ch <- prometheus.MustNewConstMetric(c.timeouts, prometheus.CounterValue, float64(stats.Timeouts))
+}
+
+// GEMINI QUOTA
+// ###################################################################
+
+// QuotaUsage reads the requests spent today and in the current minute
+type QuotaUsage func(ctx context.Context) (daily, minute int64, err error)
+
+type geminiCollector struct {
+	usage      QuotaUsage
+	dailyLimit int64
+	minLimit   int64
+	used       *prometheus.Desc
+	limit      *prometheus.Desc
+}
+
+// NewGeminiCollector reports the Gemini requests against the daily and minute limits
+func NewGeminiCollector(usage QuotaUsage, dailyLimit, minuteLimit int64) prometheus.Collector {
+	return &geminiCollector{
+		usage:      usage,
+		dailyLimit: dailyLimit,
+		minLimit:   minuteLimit,
+		used:       newDesc("gemini_quota_used_requests", "Gemini requests spent in the window.", "window"),
+		limit:      newDesc("gemini_quota_limit_requests", "Gemini requests allowed in the window.", "window"),
+	}
+}
+
+func (c *geminiCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.used
+	ch <- c.limit
+}
+
+func (c *geminiCollector) Collect(ch chan<- prometheus.Metric) {
+
+	ch <- prometheus.MustNewConstMetric(c.limit, prometheus.GaugeValue, float64(c.dailyLimit), "day")
+	ch <- prometheus.MustNewConstMetric(c.limit, prometheus.GaugeValue, float64(c.minLimit), "minute")
+
+	ctx, cancel := context.WithTimeout(context.Background(), scrapeTimeout)
+	defer cancel()
+
+	daily, minute, err := c.usage(ctx)
+	if err != nil {
+		slog.Error("failed to read the Gemini quota usage", "error", err)
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.used, prometheus.GaugeValue, float64(daily), "day")
+	ch <- prometheus.MustNewConstMetric(c.used, prometheus.GaugeValue, float64(minute), "minute")
+}
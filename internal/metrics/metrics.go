@@ -0,0 +1,145 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Prefix of all the app metrics
+const namespace = "video_store"
+
+// The app's own registry, free of any third party defaults
+var registry = newRegistry()
+
+var factory = promauto.With(registry)
+
+// Create the registry with the Go runtime and process metrics
+func newRegistry() *prometheus.Registry {
+	r := prometheus.NewRegistry()
+	r.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return r
+}
+
+var (
+	httpRequests = factory.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests by route pattern, method and status.",
+		},
+		[]string{"route", "method", "status"},
+	)
+
+	httpDuration = factory.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by route pattern, method and status.",
+			Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+		},
+		[]string{"route", "method", "status"},
+	)
+
+	cacheRequests = factory.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_requests_total",
+			Help:      "Redis cache lookups by key family and result.",
+		},
+		[]string{"family", "result"},
+	)
+)
+
+// Handler serves the metrics in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// Register the collectors reading the state at scrape time
+func Register(cs ...prometheus.Collector) error {
+	for _, c := range cs {
+		if err := registry.Register(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ObserveRequest counts the request and its latency.
+// The pattern is the one the mux matched, empty if none.
+func ObserveRequest(pattern, method string, status int, elapsed time.Duration) {
+	labels := prometheus.Labels{
+		"route":  routeLabel(pattern),
+		"method": methodLabel(method),
+		"status": strconv.Itoa(status),
+	}
+	httpRequests.With(labels).Inc()
+	httpDuration.With(labels).Observe(elapsed.Seconds())
+}
+
+// ObserveCache counts the cache hit or miss of the key
+func ObserveCache(key string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	cacheRequests.WithLabelValues(keyFamily(key), result).Inc()
+}
+
+// The route pattern without the method, which is a label of its own
+func routeLabel(pattern string) string {
+	if pattern == "" {
+		return "unmatched"
+	}
+	if _, path, ok := strings.Cut(pattern, " "); ok {
+		return path
+	}
+	return pattern
+}
+
+// The known methods, anything else is grouped so clients can't blow up the series
+func methodLabel(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+		http.MethodPatch, http.MethodDelete, http.MethodOptions:
+		return method
+	default:
+		return "OTHER"
+	}
+}
+
+// Key namespaces whose second segment is constant too
+var namespaces = []string{"home", "posts", "feed", "sitemap"}
+
+// keyFamily drops the IDs, slugs, queries and cursors out of the cache key.
+// The namespaced keys keep the two leading segments, "posts:search:..." is "posts:search".
+// The rest are "kind:ID:subkind:...", "post:abc:related_posts" is "post:related_posts".
+func keyFamily(key string) string {
+
+	segments := strings.SplitN(key, ":", 4)
+	if len(segments) == 1 {
+		return key
+	}
+
+	for _, ns := range namespaces {
+		if segments[0] == ns {
+			return segments[0] + ":" + segments[1]
+		}
+	}
+
+	if len(segments) > 2 {
+		return segments[0] + ":" + segments[2]
+	}
+
+	return segments[0]
+}
@@ -0,0 +1,106 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestKeyFamily(t *testing.T) {
+
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"categories", "categories"},
+		{"post:abcdefghijk", "post"},
+		{"post:abcdefghijk:related_posts", "post:related_posts"},
+		{"post:abcdefghijk:locale:fr", "post:locale"},
+		{"home:posts:likes:cursor:abc", "home:posts"},
+		{"category:music:posts:likes:cursor:abc", "category:posts"},
+		{"posts:search:home:filters:x:cursor:y", "posts:search"},
+		{"feed:category:music", "feed:category"},
+		{"user:5:notifications", "user:notifications"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := keyFamily(tt.key); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestObserveRequest(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		pattern string
+		method  string
+		status  int
+		route   string
+		label   string
+	}{
+		{"pattern with method", "GET /video/{video}/{$}", http.MethodGet, 200, "/video/{video}/{$}", "GET"},
+		{"pattern without method", "/static/", http.MethodHead, 304, "/static/", "HEAD"},
+		{"unmatched", "", "PROPFIND", 404, "unmatched", "OTHER"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			counter := httpRequests.WithLabelValues(tt.route, tt.label, strconv.Itoa(tt.status))
+			before := testutil.ToFloat64(counter)
+
+			ObserveRequest(tt.pattern, tt.method, tt.status, time.Millisecond)
+
+			if got := testutil.ToFloat64(counter) - before; got != 1 {
+				t.Errorf("got %v new requests, want 1", got)
+			}
+		})
+	}
+}
+
+func TestObserveCache(t *testing.T) {
+
+	hits := cacheRequests.WithLabelValues("post:related_posts", "hit")
+	misses := cacheRequests.WithLabelValues("post:related_posts", "miss")
+	hitsBefore, missesBefore := testutil.ToFloat64(hits), testutil.ToFloat64(misses)
+
+	ObserveCache("post:abcdefghijk:related_posts", true)
+	ObserveCache("post:abcdefghijk:related_posts", false)
+	ObserveCache("post:bcdefghijkl:related_posts", false)
+
+	if got := testutil.ToFloat64(hits) - hitsBefore; got != 1 {
+		t.Errorf("got %v hits, want 1", got)
+	}
+
+	if got := testutil.ToFloat64(misses) - missesBefore; got != 2 {
+		t.Errorf("got %v misses, want 2", got)
+	}
+}
+
+func TestHandler(t *testing.T) {
+
+	ObserveRequest("GET /{$}", http.MethodGet, 200, time.Millisecond)
+
+	w := httptest.NewRecorder()
+	Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	body := w.Body.String()
+	for _, want := range []string{
+		"video_store_http_requests_total",
+		"video_store_http_request_duration_seconds_bucket",
+		"go_goroutines",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("the metrics are missing %q", want)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+)
+
+// The worker runs in its own short lived process,
+// so it leaves the run outcomes in Redis for the app to report
+const (
+	workerRunsKey    = "worker:metrics:runs"     // run count by outcome
+	workerLastRunKey = "worker:metrics:last_run" // the latest run
+	workerVideosKey  = "worker:metrics:videos"   // video count by action
+)
+
+// WorkerRun is the outcome of a single worker run
+type WorkerRun struct {
+	Success  bool
+	Started  time.Time
+	Duration time.Duration
+	Videos   map[string]int64 // changed videos by action
+}
+
+// RecordWorkerRun leaves the outcome of the run in Redis
+func RecordWorkerRun(ctx context.Context, client *redis.Client, run WorkerRun) error {
+
+	outcome, success := "failure", 0
+	if run.Success {
+		outcome, success = "success", 1
+	}
+
+	pipe := client.TxPipeline()
+	pipe.HIncrBy(ctx, workerRunsKey, outcome, 1)
+	pipe.HSet(ctx, workerLastRunKey,
+		"finished_at", run.Started.Add(run.Duration).Unix(),
+		"duration_seconds", run.Duration.Seconds(),
+		"success", success,
+	)
+
+	for action, count := range run.Videos {
+		if count > 0 {
+			pipe.HIncrBy(ctx, workerVideosKey, action, count)
+		}
+	}
+
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+type workerCollector struct {
+	client       *redis.Client
+	runs         *prometheus.Desc
+	videos       *prometheus.Desc
+	lastFinished *prometheus.Desc
+	lastDuration *prometheus.Desc
+	lastSuccess  *prometheus.Desc
+}
+
+// NewWorkerCollector reports the worker run outcomes left in Redis
+func NewWorkerCollector(client *redis.Client) prometheus.Collector {
+	return &workerCollector{
+		client:       client,
+		runs:         newDesc("worker_runs_total", "Worker runs by outcome.", "outcome"),
+		videos:       newDesc("worker_videos_total", "Videos changed by the worker by action.", "action"),
+		lastFinished: newDesc("worker_last_run_timestamp_seconds", "When the latest worker run finished."),
+		lastDuration: newDesc("worker_last_run_duration_seconds", "How long the latest worker run took."),
+		lastSuccess:  newDesc("worker_last_run_success", "Whether the latest worker run succeeded."),
+	}
+}
+
+func (c *workerCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.runs
+	ch <- c.videos
+	ch <- c.lastFinished
+	ch <- c.lastDuration
+	ch <- c.lastSuccess
+}
+
+func (c *workerCollector) Collect(ch chan<- prometheus.Metric) {
+
+	ctx, cancel := context.WithTimeout(context.Background(), scrapeTimeout)
+	defer cancel()
+
+	pipe := c.client.Pipeline()
+	runs := pipe.HGetAll(ctx, workerRunsKey)
+	videos := pipe.HGetAll(ctx, workerVideosKey)
+	lastRun := pipe.HGetAll(ctx, workerLastRunKey)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		slog.Error("failed to read the worker metrics", "error", err)
+		return
+	}
+
+	for outcome, value := range runs.Val() {
+		collectFloat(ch, c.runs, prometheus.CounterValue, value, outcome)
+	}
+
+	for action, value := range videos.Val() {
+		collectFloat(ch, c.videos, prometheus.CounterValue, value, action)
+	}
+
+	last := lastRun.Val()
+	collectFloat(ch, c.lastFinished, prometheus.GaugeValue, last["finished_at"])
+	collectFloat(ch, c.lastDuration, prometheus.GaugeValue, last["duration_seconds"])
+	collectFloat(ch, c.lastSuccess, prometheus.GaugeValue, last["success"])
+}
+
+// Send the metric if the Redis value is a number, the missing fields are skipped
+func collectFloat(
+	ch chan<- prometheus.Metric,
+	desc *prometheus.Desc,
+	valueType prometheus.ValueType,
+	value string,
+	labels ...string) {
+
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(desc, valueType, f, labels...)
+}
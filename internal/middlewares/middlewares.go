@@ -2,40 +2,42 @@ package middlewares
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
-	"os"
+	"regexp"
 	"runtime/debug"
 	"strings"
+	"time"
 
 	"github.com/vlatan/video-store/internal/config"
+	"github.com/vlatan/video-store/internal/drivers/rdb"
+	"github.com/vlatan/video-store/internal/logging"
+	"github.com/vlatan/video-store/internal/metrics"
 	"github.com/vlatan/video-store/internal/models"
+	"github.com/vlatan/video-store/internal/tracing"
 	"github.com/vlatan/video-store/internal/ui"
 	"github.com/vlatan/video-store/internal/utils"
 
 	"github.com/gorilla/csrf"
+	"github.com/jackc/pgx/v5"
 	"github.com/klauspost/compress/gzhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Service struct {
 	ui     ui.Service
+	rdb    *rdb.Service
 	config *config.Config
 }
 
 // New creates new middlewares service
-func New(ui ui.Service, config *config.Config) *Service {
-
-	var opts *slog.HandlerOptions
-	if config.Debug {
-		opts = &slog.HandlerOptions{Level: slog.LevelDebug}
-	}
-
-	handler := slog.NewJSONHandler(os.Stdout, opts)
-	logger := slog.New(handler)
-	slog.SetDefault(logger)
-
+func New(ui ui.Service, rdb *rdb.Service, config *config.Config) *Service {
 	return &Service{
 		ui:     ui,
+		rdb:    rdb,
 		config: config,
 	}
 }
@@ -68,12 +70,22 @@ func (s *Service) IsAdmin(next http.HandlerFunc) http.HandlerFunc {
 	}
 }
 
-// LoadUser gets the user from session and stores it in the context
+// LoadUser gets the user from the API token or from session
+// and stores it in the context
 func (s *Service) LoadUser(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 
+		// The token requests don't use the session at all
+		if token, ok := models.BearerToken(r); ok {
+			s.loadTokenUser(w, r, token, next)
+			return
+		}
+
 		// Get user from session and store in context
-		user := s.ui.GetUserFromSession(w, r) // Can be nil
+		spanCtx, span := tracing.Start(r.Context(), "LoadUser")
+		user := s.ui.GetUserFromSession(w, r.WithContext(spanCtx)) // Can be nil
+		span.End()
+
 		ctx := context.WithValue(r.Context(), models.UserContextKey, user)
 
 		next.ServeHTTP(w, r.WithContext(ctx))
@@ -87,7 +99,9 @@ func (s *Service) LoadData(next http.Handler) http.Handler {
 		// Get user from context
 		user := models.GetUserFromContext(r)
 		// Generate the default data
-		data := s.ui.NewData(w, r)
+		spanCtx, span := tracing.Start(r.Context(), "LoadData")
+		data := s.ui.NewData(w, r.WithContext(spanCtx))
+		span.End()
 		// Attach the user to be able to be accessed from data too
 		data.CurrentUser = user
 		// Store data to context
@@ -97,6 +111,43 @@ func (s *Service) LoadData(next http.Handler) http.Handler {
 	})
 }
 
+// Authenticate the request with the API token,
+// the token must have the scope the request method needs
+func (s *Service) loadTokenUser(w http.ResponseWriter, r *http.Request, token string, next http.Handler) {
+
+	spanCtx, span := tracing.Start(r.Context(), "LoadTokenUser")
+	user, err := s.ui.GetUserFromToken(r.WithContext(spanCtx), token)
+	span.End()
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+		utils.HttpError(w, http.StatusUnauthorized)
+		return
+	}
+
+	if err != nil {
+		slog.ErrorContext(
+			r.Context(), "failed to get the token user from DB",
+			"path", r.URL.Path,
+			"error", err,
+		)
+		utils.HttpError(w, http.StatusInternalServerError)
+		return
+	}
+
+	if scope := models.RequiredScope(r.Method); !user.HasScope(scope) {
+		w.Header().Set(
+			"WWW-Authenticate",
+			fmt.Sprintf(`Bearer error="insufficient_scope", scope=%q`, scope),
+		)
+		utils.HttpError(w, http.StatusForbidden)
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), models.UserContextKey, user)
+	next.ServeHTTP(w, r.WithContext(ctx))
+}
+
 // CloseBody closes the body after a request
 func (s *Service) CloseBody(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -241,6 +292,13 @@ func (s *Service) HandleErrors(next http.Handler) http.Handler {
 			return
 		}
 
+		// The API handlers already wrote a detailed JSON error
+		if strings.HasPrefix(r.URL.Path, "/api/") &&
+			recorder.body.Len() > 0 &&
+			recorder.Header().Get("Content-Type") == "application/json" {
+			return
+		}
+
 		// This is an error
 		// Clear any previously buffered body
 		recorder.body.Reset()
@@ -284,6 +342,13 @@ func (s *Service) CsrfProtection(next http.Handler) http.Handler {
 			return
 		}
 
+		// The API tokens are never sent by the browsers on their own,
+		// so the token requests can't be forged
+		if user.IsTokenAuth() {
+			next.ServeHTTP(w, r)
+			return
+		}
+
 		// Set plain text (HTTP) schema if necessary
 		if s.config.Protocol != "https" {
 			r = csrf.PlaintextHTTPRequest(r)
@@ -335,26 +400,6 @@ func (s *Service) Logging(next http.Handler) http.Handler {
 			return
 		}
 
-		// Prioritize CF-Connecting-IP as recommended by Cloudflare
-		srcIp := r.Header.Get("CF-Connecting-IP")
-
-		// Fallback to True-Client-IP
-		if srcIp == "" {
-			srcIp = r.Header.Get("True-Client-IP")
-		}
-
-		// Fallback to X-Forwarded-For
-		if srcIp == "" {
-			xForwardedFor := r.Header.Get("X-Forwarded-For")
-			parts := strings.Split(xForwardedFor, ",")
-			srcIp = strings.TrimSpace(parts[0])
-		}
-
-		// Fallback to RemoteAddr
-		if srcIp == "" {
-			srcIp = r.RemoteAddr
-		}
-
 		st := NewStatusTracker(w)
 		next.ServeHTTP(st, r)
 
@@ -366,7 +411,7 @@ func (s *Service) Logging(next http.Handler) http.Handler {
 			"path", r.URL.Path,
 			"query", r.URL.Query(),
 			"clientUa", r.Header.Get("User-Agent"),
-			"srcIp", srcIp,
+			"srcIp", clientIP(r, s.config.TrustedProxies),
 			"status", st.status,
 		)
 	})
@@ -382,3 +427,68 @@ func (s *Service) ApplyToAll(middlewares ...func(http.Handler) http.Handler) fun
 		return final
 	}
 }
+
+// Metrics counts the requests and their latency by the matched route pattern.
+// The pattern is read after serving, so the middlewares between this one and the mux,
+// like TraceRoute, must pass the same request on, not a copy of it.
+func (s *Service) Metrics(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		start := time.Now()
+		st := NewStatusTracker(w)
+
+		// A panic leaves the handler unfinished, the panic recovery serves 500
+		var finished bool
+		defer func() {
+			status := st.status
+			if !finished {
+				status = http.StatusInternalServerError
+			}
+			metrics.ObserveRequest(r.Pattern, r.Method, status, time.Since(start))
+		}()
+
+		next.ServeHTTP(st, r)
+		finished = true
+	})
+}
+
+// Trace starts the server span of the request, unless healthcheck or static file
+func (s *Service) Trace(next http.Handler) http.Handler {
+	return tracing.Handler(next, func(r *http.Request) bool {
+		return r.URL.Path == "/healthcheck" || utils.IsStatic(r.URL.Path)
+	})
+}
+
+// TraceRoute names the server span after the matched route pattern.
+// It goes innermost, around the mux, which sets the pattern on the request it gets.
+func (s *Service) TraceRoute(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r)
+		tracing.SetRoute(r.Context(), r.Method, r.Pattern)
+	})
+}
+
+// Accept the incoming request IDs only if short and plain
+var validRequestID = regexp.MustCompile(`^[a-zA-Z0-9._:-]{1,128}$`)
+
+// RequestID identifies the request in its log lines and in the response.
+// It keeps the ID of the proxy in front, X-Request-ID or Cloudflare's CF-Ray, if any.
+func (s *Service) RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		id := r.Header.Get("X-Request-ID")
+		if !validRequestID.MatchString(id) {
+			id = r.Header.Get("CF-Ray")
+		}
+
+		if !validRequestID.MatchString(id) {
+			id = logging.NewID()
+		}
+
+		w.Header().Set("X-Request-ID", id)
+		trace.SpanFromContext(r.Context()).SetAttributes(attribute.String("request.id", id))
+
+		ctx := logging.With(r.Context(), "requestId", id)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
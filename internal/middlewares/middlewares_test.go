@@ -3,9 +3,12 @@ package middlewares
 import (
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/vlatan/video-store/internal/config"
+	"github.com/vlatan/video-store/internal/logging"
+	"github.com/vlatan/video-store/internal/metrics"
 )
 
 func TestCanonicalRedirect(t *testing.T) {
@@ -121,3 +124,105 @@ func TestCanonicalRedirect(t *testing.T) {
 		})
 	}
 }
+
+func TestMetrics(t *testing.T) {
+
+	s := &Service{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /video/{video}/{$}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	mux.HandleFunc("GET /panic/{$}", func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+
+	tests := []struct {
+		name      string
+		url       string
+		wantPanic bool
+	}{
+		{"matched route", "/video/abc/", false},
+		{"panicking route", "/panic/", true},
+	}
+
+	want := []string{
+		`route="/video/{video}/{$}",status="404"`,
+		`route="/panic/{$}",status="500"`,
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			defer func() {
+				if p := recover(); (p != nil) != tt.wantPanic {
+					t.Errorf("got panic %v, want panic %v", p, tt.wantPanic)
+				}
+			}()
+
+			r := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			s.Metrics(mux).ServeHTTP(httptest.NewRecorder(), r)
+		})
+	}
+
+	// Scrape the metrics
+	w := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	for _, series := range want {
+		if !strings.Contains(w.Body.String(), series) {
+			t.Errorf("the metrics are missing %s", series)
+		}
+	}
+}
+
+func TestRequestID(t *testing.T) {
+
+	s := &Service{}
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    string // empty for a generated ID
+	}{
+		{"incoming ID", map[string]string{"X-Request-ID": "abc-123"}, "abc-123"},
+		{"Cloudflare ray", map[string]string{"CF-Ray": "8f1e2d3c4b5a6978-FRA"}, "8f1e2d3c4b5a6978-FRA"},
+		{"invalid ID", map[string]string{"X-Request-ID": "bad id\n"}, ""},
+		{"no ID", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			var ctxID string
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for _, a := range logging.Attrs(r.Context()) {
+					if a.Key == "requestId" {
+						ctxID = a.Value.String()
+					}
+				}
+			})
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			for k, v := range tt.headers {
+				r.Header.Set(k, v)
+			}
+
+			w := httptest.NewRecorder()
+			s.RequestID(next).ServeHTTP(w, r)
+
+			got := w.Header().Get("X-Request-ID")
+			if tt.want != "" && got != tt.want {
+				t.Errorf("got ID %q, want %q", got, tt.want)
+			}
+
+			if tt.want == "" && len(got) != 16 {
+				t.Errorf("got ID %q, want a generated one", got)
+			}
+
+			if ctxID != got {
+				t.Errorf("got ID %q in the context, want %q", ctxID, got)
+			}
+		})
+	}
+}
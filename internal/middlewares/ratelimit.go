@@ -0,0 +1,224 @@
+package middlewares
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/vlatan/video-store/internal/models"
+	"github.com/vlatan/video-store/internal/utils"
+)
+
+// RatePolicy allows Limit requests per Period to a group of routes,
+// all of which can be spent in a single burst
+type RatePolicy struct {
+	Name   string // namespace of the Redis keys
+	Limit  int
+	Period time.Duration
+}
+
+// The route policies
+var (
+	SearchLimit  = RatePolicy{Name: "search", Limit: 30, Period: time.Minute}
+	SuggestLimit = RatePolicy{Name: "suggest", Limit: 120, Period: time.Minute}
+	ActionLimit  = RatePolicy{Name: "action", Limit: 30, Period: time.Minute}
+	APILimit     = RatePolicy{Name: "api", Limit: 300, Period: time.Minute}
+)
+
+// The outcome of a single rate limit check
+type rateResult struct {
+	allowed    bool
+	remaining  int
+	retryAfter time.Duration // until the next request is allowed, if denied
+	reset      time.Duration // until the full limit is available again
+}
+
+// GCRA (generic cell rate algorithm) in a single atomic step.
+// The key holds the theoretical arrival time (TAT) in milliseconds.
+// Every request pushes the TAT one emission interval further,
+// a request is denied when the TAT would land beyond one period from now.
+// The Redis clock is used so all the app instances agree on the time.
+var gcra = redis.NewScript(`
+local period = tonumber(ARGV[1])
+local interval = period / tonumber(ARGV[2])
+
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+local tat = tonumber(redis.call("GET", KEYS[1])) or now
+if tat < now then
+	tat = now
+end
+
+local newTat = tat + interval
+local allowAt = newTat - period
+
+if allowAt > now then
+	return {0, 0, math.ceil(allowAt - now), math.ceil(tat - now)}
+end
+
+redis.call("SET", KEYS[1], tostring(newTat), "PX", math.ceil(newTat - now))
+return {1, math.floor((now - allowAt) / interval), 0, math.ceil(newTat - now)}
+`)
+
+// RateLimit throttles the route per user, or per client IP for the anonymous requests.
+// The admins are not limited. On Redis failure the request is let through.
+func (s *Service) RateLimit(policy RatePolicy, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		user := models.GetUserFromContext(r)
+		if user.IsAdmin() {
+			next(w, r)
+			return
+		}
+
+		key := rateLimitKey(policy, user, clientIP(r, s.config.TrustedProxies))
+		result, err := s.allow(r.Context(), key, policy)
+		if err != nil {
+			slog.ErrorContext(
+				r.Context(), "failed to check the rate limit",
+				"policy", policy.Name,
+				"error", err,
+			)
+			next(w, r)
+			return
+		}
+
+		setRateLimitHeaders(w.Header(), policy, result)
+
+		if !result.allowed {
+			// Don't let the caches serve the rejection to everyone
+			w.Header().Set("Cache-Control", "no-store")
+			w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(result.retryAfter)))
+			utils.HttpError(w, http.StatusTooManyRequests)
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+// Run the GCRA script for the key
+func (s *Service) allow(ctx context.Context, key string, policy RatePolicy) (rateResult, error) {
+
+	values, err := gcra.Run(
+		ctx,
+		s.rdb.Client,
+		[]string{key},
+		policy.Period.Milliseconds(),
+		policy.Limit,
+	).Int64Slice()
+
+	if err != nil {
+		return rateResult{}, err
+	}
+
+	if len(values) != 4 {
+		return rateResult{}, fmt.Errorf("unexpected rate limit result %v", values)
+	}
+
+	return rateResult{
+		allowed:    values[0] == 1,
+		remaining:  int(values[1]),
+		retryAfter: time.Duration(values[2]) * time.Millisecond,
+		reset:      time.Duration(values[3]) * time.Millisecond,
+	}, nil
+}
+
+// The Redis key of the caller, the user if authenticated, the client IP otherwise
+func rateLimitKey(policy RatePolicy, user *models.User, ip string) string {
+	if user.IsAuthenticated() && user.ID != 0 {
+		return fmt.Sprintf("ratelimit:%s:user:%d", policy.Name, user.ID)
+	}
+	return fmt.Sprintf("ratelimit:%s:ip:%s", policy.Name, ip)
+}
+
+// Set the RateLimit header fields
+// https://datatracker.ietf.org/doc/draft-ietf-httpapi-ratelimit-headers/
+func setRateLimitHeaders(h http.Header, policy RatePolicy, result rateResult) {
+	h.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", policy.Limit, ceilSeconds(policy.Period)))
+	h.Set("RateLimit-Limit", strconv.Itoa(policy.Limit))
+	h.Set("RateLimit-Remaining", strconv.Itoa(max(result.remaining, 0)))
+	h.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.reset)))
+}
+
+// The duration in whole seconds, rounded up
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// clientIP extracts the IP of the client behind the trusted proxies.
+// The client IP headers are anyone's to set, so they're only read
+// on the requests coming from a trusted proxy.
+func clientIP(r *http.Request, trusted []netip.Prefix) string {
+
+	// The peer, without the port
+	remote := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		remote = host
+	}
+
+	if !isTrusted(remote, trusted) {
+		return remote
+	}
+
+	// Prioritize CF-Connecting-IP as recommended by Cloudflare
+	if ip, ok := parseIP(r.Header.Get("CF-Connecting-IP")); ok {
+		return ip.String()
+	}
+
+	// Fallback to True-Client-IP
+	if ip, ok := parseIP(r.Header.Get("True-Client-IP")); ok {
+		return ip.String()
+	}
+
+	// Fallback to X-Forwarded-For, every proxy appends the peer it got the request from.
+	// The right-most hop which is not a trusted proxy is the client,
+	// anything left of it could have been sent by the client itself.
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		ip, ok := parseIP(hops[i])
+		if !ok {
+			break
+		}
+		if !isTrusted(ip.String(), trusted) {
+			return ip.String()
+		}
+	}
+
+	return remote
+}
+
+// Parse the IP from a header value
+func parseIP(value string) (netip.Addr, bool) {
+	ip, err := netip.ParseAddr(strings.TrimSpace(value))
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return ip.Unmap(), true
+}
+
+// Check if the IP is in any of the trusted proxy networks
+func isTrusted(value string, trusted []netip.Prefix) bool {
+
+	ip, ok := parseIP(value)
+	if !ok {
+		return false
+	}
+
+	for _, prefix := range trusted {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,245 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/vlatan/video-store/internal/config"
+	"github.com/vlatan/video-store/internal/containers"
+	"github.com/vlatan/video-store/internal/drivers/rdb"
+	"github.com/vlatan/video-store/internal/models"
+)
+
+func TestClientIP(t *testing.T) {
+
+	trusted := []netip.Prefix{
+		netip.MustParsePrefix("9.9.9.0/24"),
+		netip.MustParsePrefix("2001:db8::/32"),
+	}
+
+	tests := []struct {
+		name       string
+		headers    map[string]string
+		remoteAddr string
+		want       string
+	}{
+		{"cloudflare first", map[string]string{"CF-Connecting-IP": "1.1.1.1", "True-Client-IP": "2.2.2.2"}, "9.9.9.9:1234", "1.1.1.1"},
+		{"true client ip", map[string]string{"True-Client-IP": "2.2.2.2", "X-Forwarded-For": "3.3.3.3"}, "9.9.9.9:1234", "2.2.2.2"},
+		{"invalid header ip", map[string]string{"CF-Connecting-IP": "bogus", "X-Forwarded-For": "3.3.3.3"}, "9.9.9.9:1234", "3.3.3.3"},
+		{"right-most untrusted hop", map[string]string{"X-Forwarded-For": " 3.3.3.3 , 4.4.4.4, 9.9.9.8"}, "9.9.9.9:1234", "4.4.4.4"},
+		{"all hops trusted", map[string]string{"X-Forwarded-For": "9.9.9.8"}, "9.9.9.9:1234", "9.9.9.9"},
+		{"invalid hop", map[string]string{"X-Forwarded-For": "3.3.3.3, bogus"}, "9.9.9.9:1234", "9.9.9.9"},
+		{"untrusted peer headers", map[string]string{"CF-Connecting-IP": "1.1.1.1", "X-Forwarded-For": "3.3.3.3"}, "8.8.8.8:1234", "8.8.8.8"},
+		{"remote addr without port", nil, "9.9.9.9:1234", "9.9.9.9"},
+		{"trusted ipv6 peer", map[string]string{"X-Forwarded-For": "3.3.3.3"}, "[2001:db8::1]:1234", "3.3.3.3"},
+		{"ipv6 remote addr", nil, "[2001:db9::1]:1234", "2001:db9::1"},
+		{"remote addr without port already", nil, "8.8.8.8", "8.8.8.8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = tt.remoteAddr
+			for k, v := range tt.headers {
+				r.Header.Set(k, v)
+			}
+
+			if got := clientIP(r, trusted); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRateLimitKey(t *testing.T) {
+
+	tests := []struct {
+		name string
+		user *models.User
+		want string
+	}{
+		{"anonymous", nil, "ratelimit:search:ip:1.1.1.1"},
+		{"user", &models.User{ID: 7, ProviderUserId: "abc"}, "ratelimit:search:user:7"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rateLimitKey(SearchLimit, tt.user, "1.1.1.1"); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetRateLimitHeaders(t *testing.T) {
+
+	policy := RatePolicy{Name: "test", Limit: 30, Period: time.Minute}
+
+	tests := []struct {
+		name   string
+		result rateResult
+		want   map[string]string
+	}{
+		{
+			name:   "allowed",
+			result: rateResult{allowed: true, remaining: 29, reset: 2 * time.Second},
+			want: map[string]string{
+				"RateLimit-Policy":    "30;w=60",
+				"RateLimit-Limit":     "30",
+				"RateLimit-Remaining": "29",
+				"RateLimit-Reset":     "2",
+			},
+		},
+		{
+			name:   "reset rounded up",
+			result: rateResult{allowed: false, reset: 59*time.Second + time.Millisecond},
+			want: map[string]string{
+				"RateLimit-Remaining": "0",
+				"RateLimit-Reset":     "60",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := http.Header{}
+			setRateLimitHeaders(h, policy, tt.result)
+			for k, v := range tt.want {
+				if got := h.Get(k); got != v {
+					t.Errorf("%s: got %q, want %q", k, got, v)
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimitFailsOpen(t *testing.T) {
+
+	// Nothing listens on the port, every check fails
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1})
+	defer client.Close()
+
+	s := &Service{rdb: &rdb.Service{Client: client}, config: &config.Config{}}
+
+	var called bool
+	handler := s.RateLimit(SearchLimit, func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+
+	r := httptest.NewRequestWithContext(context.Background(), http.MethodGet, "/api/search/", nil)
+	w := httptest.NewRecorder()
+	handler(w, r)
+
+	if !called {
+		t.Error("the request was not let through")
+	}
+
+	if w.Code != http.StatusOK {
+		t.Errorf("got status %d, want %d", w.Code, http.StatusOK)
+	}
+
+	if got := w.Header().Get("RateLimit-Limit"); got != "" {
+		t.Errorf("got RateLimit-Limit %q on failure, want none", got)
+	}
+}
+
+// setupTestRedis creates a Redis container for the tests running the GCRA script.
+// The test is skipped if no container can be created, e.g. no Docker.
+func setupTestRedis(t *testing.T) *rdb.Service {
+
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	cfg := &config.Config{}
+	container, err := containers.SetupTestRedis(ctx, cfg)
+	if err != nil {
+		t.Skipf("failed to create Redis container; %v", err)
+	}
+
+	t.Cleanup(func() { container.Terminate(context.Background()) })
+
+	service, err := rdb.New(cfg)
+	if err != nil {
+		t.Fatalf("failed to create Redis client; %v", err)
+	}
+
+	t.Cleanup(func() { service.Client.Close() })
+	return service
+}
+
+func TestGCRA(t *testing.T) {
+
+	s := &Service{rdb: setupTestRedis(t), config: &config.Config{}}
+
+	// One request per second, all three can be spent at once
+	policy := RatePolicy{Name: "gcra", Limit: 3, Period: 3 * time.Second}
+	key := rateLimitKey(policy, nil, "1.1.1.1")
+
+	// The burst
+	for want := 2; want >= 0; want-- {
+		result, err := s.allow(context.Background(), key, policy)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !result.allowed || result.remaining != want {
+			t.Fatalf("got %+v, want allowed with %d remaining", result, want)
+		}
+	}
+
+	// Spent, the next request is allowed once an interval passes
+	denied, err := s.allow(context.Background(), key, policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if denied.allowed || denied.retryAfter <= 0 || denied.retryAfter > time.Second {
+		t.Fatalf("got %+v, want denied for up to a second", denied)
+	}
+
+	if denied.reset <= 2*time.Second || denied.reset > policy.Period {
+		t.Errorf("got reset %v, want about the whole period", denied.reset)
+	}
+
+	// The handler rejects it with the time to retry
+	var called bool
+	handler := s.RateLimit(policy, func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/api/search/", nil)
+	r.RemoteAddr = "1.1.1.1:1234"
+	w := httptest.NewRecorder()
+	handler(w, r)
+
+	if called || w.Code != http.StatusTooManyRequests {
+		t.Fatalf("got status %d, want %d", w.Code, http.StatusTooManyRequests)
+	}
+
+	if got := w.Header().Get("Retry-After"); got != "1" {
+		t.Errorf("got Retry-After %q, want %q", got, "1")
+	}
+
+	// Refilled by one request after the wait
+	time.Sleep(denied.retryAfter + 50*time.Millisecond)
+
+	result, err := s.allow(context.Background(), key, policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !result.allowed || result.remaining != 0 {
+		t.Errorf("got %+v, want allowed with none remaining", result)
+	}
+
+	if result, err = s.allow(context.Background(), key, policy); err != nil || result.allowed {
+		t.Errorf("got %+v, error %v, want denied", result, err)
+	}
+}
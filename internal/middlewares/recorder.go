@@ -77,3 +77,9 @@ func (r *responseRecorder) flush() {
 		)
 	}
 }
+
+// Unwrap returns the underlying response writer,
+// so the http.ResponseController can reach it.
+func (r *responseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -28,3 +28,9 @@ func (st *statusTracker) Write(b []byte) (int, error) {
 	}
 	return st.ResponseWriter.Write(b)
 }
+
+// Unwrap returns the underlying response writer,
+// so the http.ResponseController can reach it.
+func (st *statusTracker) Unwrap() http.ResponseWriter {
+	return st.ResponseWriter
+}
@@ -20,3 +20,6 @@ const (
 	FieldTypeInput FieldType = iota
 	FieldTypeTextarea
 )
+
+// Redis key caching the number of the user's unread notifications
+const UnreadNotificationsKey = "user:%d:notifications"
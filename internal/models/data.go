@@ -22,10 +22,17 @@ type FileInfo struct {
 
 // The response from the Genai API
 type GenaiResponse struct {
-	Title         string `json:"video_title"`
-	OriginalTitle string `json:"original_title"`
-	Summary       string `json:"summary"`
-	Category      string `json:"category"`
+	Title             string `json:"video_title"`
+	OriginalTitle     string `json:"original_title"`
+	Summary           string `json:"summary"`
+	Category          string `json:"category"`
+	SuggestedCategory string `json:"suggested_category,omitempty"`
+}
+
+// The translation response from the Genai API
+type GenaiTranslation struct {
+	Title   string `json:"title"`
+	Summary string `json:"summary"`
 }
 
 // Flash message object to store to session for the next page
@@ -43,8 +50,9 @@ type HTMLErrorData struct {
 
 // Specific data for the JSON response
 type JSONErrorData struct {
-	Error string `json:"error"`
-	Code  int    `json:"code"`
+	Error   string `json:"error"`
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
 }
 
 type FieldType int
@@ -71,24 +79,38 @@ type Form struct {
 	Title    *FormGroup
 	Content  *FormGroup
 	Category *FormGroup
+	Source   *FormGroup
 	Error    *FlashMessage
 }
 
 // Data struct to pass to templates
 type TemplateData struct {
-	Title           string
-	CurrentPost     *Post
-	CurrentPage     *Page
-	CurrentUser     *User
-	CurrentURI      string
-	CanonicalURL    string
-	Sources         []Source
-	Categories      []Category
-	FlashMessages   []*FlashMessage
-	SearchQuery     string
-	CSRFField       template.HTML
-	XMLDeclarations []template.HTML
-	SitemapItems    []*SitemapItem
+	Title               string
+	CurrentPost         *Post
+	CurrentPage         *Page
+	CurrentUser         *User
+	CurrentURI          string
+	CanonicalURL        string
+	FeedPath            string // path of the page's own feeds, if any
+	Sources             []Source
+	Categories          []Category
+	FlashMessages       []*FlashMessage
+	SearchQuery         string
+	SearchFilters       SearchFilters
+	CSRFField           template.HTML
+	XMLDeclarations     []template.HTML
+	SitemapItems        []*SitemapItem
+	FlaggedPosts        []FlaggedPost
+	SuggestedCategories []SuggestedCategory
+	SearchReport        *SearchReport
+	SavedSearches       []SavedSearch
+	SynonymGroups       []SynonymGroup
+	Notifications       []SearchNotification
+	UnreadNotifications int
+	APITokens           []APIToken
+	NewAPIToken         string // shown only once, right after the creation
+	TokenScopes         []string
+	Regenerated         *GenaiResponse
 	StaticFiles
 	*config.Config
 	*HTMLErrorData
@@ -106,6 +128,14 @@ func (td *TemplateData) AddVersion(path string) string {
 	return path
 }
 
+// Lang returns the language of the current page
+func (td *TemplateData) Lang() string {
+	if td.CurrentPost != nil && td.CurrentPost.Locale != "" {
+		return td.CurrentPost.Locale
+	}
+	return "en"
+}
+
 // Split string helper function for templates
 func (td *TemplateData) Split(s, sep string) []string {
 	return strings.Split(s, sep)
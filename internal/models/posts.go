@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"html/template"
 	"time"
+
+	"github.com/vlatan/video-store/internal/drivers/rdb"
 )
 
 type Post struct {
@@ -34,6 +36,14 @@ type Post struct {
 	CreatedAt       *time.Time      `json:"created_at,omitempty"`
 	UpdatedAt       *time.Time      `json:"updated_at,omitempty"`
 	Duration        ISO8601Duration `json:"duration,omitempty"`
+	Locale          string          `json:"locale,omitempty"`
+	Locales         []string        `json:"locales,omitempty"`
+
+	// Search result snippet with the matched terms marked, already HTML escaped
+	Snippet template.HTML `json:"snippet,omitempty"`
+
+	// Category proposed by the model, not persisted with the post
+	SuggestedCategory string `json:"-"`
 }
 
 // MarshalBinary implements the encoding.BinaryMarshaler interface
@@ -46,6 +56,12 @@ func (p *Post) UnmarshalBinary(data []byte) error {
 	return json.Unmarshal(data, p)
 }
 
+// CacheTags implements the rdb.Tagger interface,
+// editing the post deletes its cached copies
+func (p Post) CacheTags() []string {
+	return []string{rdb.PostTag(p.VideoID)}
+}
+
 // GetTitle gets the post title preferring the original title first
 func (p *Post) GetTitle() string {
 	if p.OriginalTitle != "" {
@@ -54,12 +70,30 @@ func (p *Post) GetTitle() string {
 	return p.Title
 }
 
+// Post flagged for admin review after its generated content
+// failed the quality checks
+type FlaggedPost struct {
+	VideoID   string
+	Title     string
+	Issues    []string
+	Response  *GenaiResponse
+	CreatedAt *time.Time
+}
+
 type Posts struct {
 	Title      string `json:"title,omitempty"`
 	Items      []Post `json:"items"`
 	NextCursor string `json:"next_cursor"`
 	TotalNum   int    `json:"total_num,omitempty"`
 	TimeTook   string `json:"time_took,omitempty"`
+
+	// Facet counts on the search results, only on the first page
+	Facets *SearchFacets `json:"facets,omitempty"`
+
+	// Spelling correction of the search query, the results are
+	// for the correction because the original query found nothing
+	Correction string `json:"correction,omitempty"`
+	Corrected  bool   `json:"corrected,omitempty"`
 }
 
 // MarshalBinary implements the encoding.BinaryMarshaler interface
@@ -67,6 +101,16 @@ func (p Posts) MarshalBinary() (data []byte, err error) {
 	return json.Marshal(p)
 }
 
+// CacheTags implements the rdb.Tagger interface,
+// editing any of the posts deletes the cached list
+func (p Posts) CacheTags() []string {
+	tags := make([]string, len(p.Items))
+	for i, post := range p.Items {
+		tags[i] = rdb.PostTag(post.VideoID)
+	}
+	return tags
+}
+
 // UnmarshalBinary implements the encoding.BinaryUnmarshaler interface
 func (p *Posts) UnmarshalBinary(data []byte) error {
 	return json.Unmarshal(data, p)
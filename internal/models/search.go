@@ -0,0 +1,393 @@
+package models
+
+import (
+	"encoding/json"
+	"maps"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vlatan/video-store/internal/utils"
+)
+
+// Search filter query parameters
+const (
+	FilterCategory  = "category"
+	FilterSource    = "source"
+	FilterDuration  = "duration"
+	FilterYearFrom  = "year_from"
+	FilterYearTo    = "year_to"
+	FilterMinRating = "min_rating"
+)
+
+// Video length bucket, the bounds are in minutes, zero max means no upper bound
+type DurationBucket struct {
+	Name  string
+	Label string
+	Min   int
+	Max   int
+}
+
+// Whitelisted video length buckets
+var DurationBuckets = []DurationBucket{
+	{"short", "Under 20 min", 0, 20},
+	{"medium", "20 - 60 min", 20, 60},
+	{"long", "60 - 90 min", 60, 90},
+	{"feature", "Over 90 min", 90, 0},
+}
+
+// Whitelisted minimum average ratings
+var RatingThresholds = []int{4, 3, 2, 1}
+
+// Valid category slug or source playlist ID
+var validFilterValue = regexp.MustCompile(`^[-a-zA-Z0-9_]{1,64}$`)
+
+// Filters narrowing down the search results
+type SearchFilters struct {
+	Category  string // category slug
+	Source    string // source playlist ID
+	Duration  string // duration bucket name
+	YearFrom  int
+	YearTo    int
+	MinRating int
+}
+
+// ParseSearchFilters extracts the filters from the URL query values.
+// Invalid values are silently dropped, the same as if they were not provided.
+func ParseSearchFilters(values url.Values) SearchFilters {
+
+	var f SearchFilters
+
+	if v := values.Get(FilterCategory); validFilterValue.MatchString(v) {
+		f.Category = v
+	}
+
+	if v := values.Get(FilterSource); validFilterValue.MatchString(v) {
+		f.Source = v
+	}
+
+	if _, ok := GetDurationBucket(values.Get(FilterDuration)); ok {
+		f.Duration = values.Get(FilterDuration)
+	}
+
+	f.YearFrom = parseYear(values.Get(FilterYearFrom))
+	f.YearTo = parseYear(values.Get(FilterYearTo))
+
+	// Swap the years if in wrong order
+	if f.YearFrom > 0 && f.YearTo > 0 && f.YearFrom > f.YearTo {
+		f.YearFrom, f.YearTo = f.YearTo, f.YearFrom
+	}
+
+	if rating, err := strconv.Atoi(values.Get(FilterMinRating)); err == nil {
+		for _, threshold := range RatingThresholds {
+			if rating == threshold {
+				f.MinRating = rating
+			}
+		}
+	}
+
+	return f
+}
+
+// IsZero reports whether no filters are set
+func (f SearchFilters) IsZero() bool {
+	return f == SearchFilters{}
+}
+
+// Values returns the set filters as URL query values
+func (f SearchFilters) Values() url.Values {
+
+	values := url.Values{}
+
+	if f.Category != "" {
+		values.Set(FilterCategory, f.Category)
+	}
+
+	if f.Source != "" {
+		values.Set(FilterSource, f.Source)
+	}
+
+	if f.Duration != "" {
+		values.Set(FilterDuration, f.Duration)
+	}
+
+	if f.YearFrom > 0 {
+		values.Set(FilterYearFrom, strconv.Itoa(f.YearFrom))
+	}
+
+	if f.YearTo > 0 {
+		values.Set(FilterYearTo, strconv.Itoa(f.YearTo))
+	}
+
+	if f.MinRating > 0 {
+		values.Set(FilterMinRating, strconv.Itoa(f.MinRating))
+	}
+
+	return values
+}
+
+// Key returns a normalized representation of the filters suitable for cache keys.
+// The same set of filters always produces the same key regardless of the params order.
+func (f SearchFilters) Key() string {
+	return f.Values().Encode()
+}
+
+// GetDurationBucket finds the duration bucket by name
+func GetDurationBucket(name string) (DurationBucket, bool) {
+	for _, bucket := range DurationBuckets {
+		if bucket.Name == name {
+			return bucket, true
+		}
+	}
+	return DurationBucket{}, false
+}
+
+// Parse a year, zero if invalid or out of a sensible range
+func parseYear(value string) int {
+	year, err := strconv.Atoi(value)
+	if err != nil || year < 1900 || year > time.Now().Year()+1 {
+		return 0
+	}
+	return year
+}
+
+// Single facet value with the number of matching posts
+type Facet struct {
+	Value  string `json:"value"`
+	Label  string `json:"label"`
+	Count  int    `json:"count"`
+	URL    string `json:"-"`
+	Active bool   `json:"-"`
+}
+
+// Facet counts on the search results
+type SearchFacets struct {
+	Categories []Facet `json:"categories,omitempty"`
+	Sources    []Facet `json:"sources,omitempty"`
+	Durations  []Facet `json:"durations,omitempty"`
+	Decades    []Facet `json:"decades,omitempty"`
+	Ratings    []Facet `json:"ratings,omitempty"`
+}
+
+// Titled group of facets
+type FacetGroup struct {
+	Title string
+	Items []Facet
+}
+
+// Groups returns the non-empty facet groups in display order
+func (sf *SearchFacets) Groups() []FacetGroup {
+
+	groups := []FacetGroup{
+		{"Category", sf.Categories},
+		{"Length", sf.Durations},
+		{"Decade", sf.Decades},
+		{"Rating", sf.Ratings},
+		{"Source", sf.Sources},
+	}
+
+	return slices.DeleteFunc(groups, func(g FacetGroup) bool {
+		return len(g.Items) == 0
+	})
+}
+
+// WithLinks returns a copy of the facets with the URL set on each facet,
+// toggling the facet's filter on top of the current search query and filters.
+// The facets may be shared among the concurrent requests, so they're left intact.
+func (sf *SearchFacets) WithLinks(path, query string, filters SearchFilters) *SearchFacets {
+
+	linked := &SearchFacets{
+		Categories: slices.Clone(sf.Categories),
+		Sources:    slices.Clone(sf.Sources),
+		Durations:  slices.Clone(sf.Durations),
+		Decades:    slices.Clone(sf.Decades),
+		Ratings:    slices.Clone(sf.Ratings),
+	}
+
+	current := filters.Values()
+	current.Set("q", query)
+
+	single := func(param string) func(Facet) map[string]string {
+		return func(facet Facet) map[string]string {
+			return map[string]string{param: facet.Value}
+		}
+	}
+
+	setLinks(linked.Categories, path, current, single(FilterCategory))
+	setLinks(linked.Sources, path, current, single(FilterSource))
+	setLinks(linked.Durations, path, current, single(FilterDuration))
+	setLinks(linked.Ratings, path, current, single(FilterMinRating))
+
+	// The decade facet spans the whole decade, the current one until this year,
+	// the later years are not valid filters
+	setLinks(linked.Decades, path, current, func(facet Facet) map[string]string {
+		decade, _ := strconv.Atoi(facet.Value)
+		return map[string]string{
+			FilterYearFrom: facet.Value,
+			FilterYearTo:   strconv.Itoa(min(decade+9, time.Now().Year())),
+		}
+	})
+
+	return linked
+}
+
+// Set the facets URLs, an active facet links to the results without it
+func setLinks(
+	facets []Facet,
+	path string,
+	current url.Values,
+	params func(Facet) map[string]string) {
+
+	for i, facet := range facets {
+
+		facetParams := params(facet)
+
+		active := true
+		for k, v := range facetParams {
+			if current.Get(k) != v {
+				active = false
+			}
+		}
+
+		values := maps.Clone(current)
+		for k, v := range facetParams {
+			if active {
+				values.Del(k)
+			} else {
+				values.Set(k, v)
+			}
+		}
+
+		facets[i].Active = active
+		facets[i].URL = path + "?" + values.Encode()
+	}
+}
+
+// Search suggestion while the user types
+type Suggestion struct {
+	Kind  string `json:"kind"`
+	Label string `json:"label"`
+	URL   string `json:"url"`
+}
+
+type Suggestions []Suggestion
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface
+func (s Suggestions) MarshalBinary() (data []byte, err error) {
+	return json.Marshal(s)
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface
+func (s *Suggestions) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, s)
+}
+
+// Aggregated stats on a normalized search query
+type SearchStat struct {
+	Query       string
+	Searches    int
+	ZeroResults int
+	Results     int     // the latest number of results
+	AvgLatency  float64 // in milliseconds
+	Clicks      int
+	Previous    int     // searches in the previous period
+	Trend       float64 // growth compared to the previous period
+}
+
+// ClickThrough returns the percent of searches followed by a click on a post
+func (s SearchStat) ClickThrough() float64 {
+	if s.Searches == 0 {
+		return 0
+	}
+	return float64(s.Clicks) / float64(s.Searches) * 100
+}
+
+// Post clicked from the search results
+type SearchClick struct {
+	VideoID     string
+	Title       string
+	SourceTitle string
+	Clicks      int
+	Queries     int // number of distinct queries leading to the post
+}
+
+// Admin report on what the users search for
+type SearchReport struct {
+	Days        int
+	Top         []SearchStat
+	Trending    []SearchStat
+	ZeroResults []SearchStat
+	Clicked     []SearchClick
+}
+
+// Search saved by a user to be notified on the new matching posts
+type SavedSearch struct {
+	ID          int
+	UserID      int
+	Query       string
+	Filters     SearchFilters
+	EmailDigest bool
+	Unread      int // number of unread notifications
+	CreatedAt   time.Time
+}
+
+// URL returns the link to the search results of the saved search
+func (s SavedSearch) URL() string {
+	values := s.Filters.Values()
+	values.Set("q", s.Query)
+	return "/search/?" + values.Encode()
+}
+
+// Key returns the query and the filters identifying the same search
+func (s SavedSearch) Key() string {
+	return s.Query + "?" + s.Filters.Key()
+}
+
+// ParseFilters parses the stored encoded filters into the saved search
+func (s *SavedSearch) ParseFilters(encoded string) {
+	values, _ := url.ParseQuery(encoded)
+	s.Filters = ParseSearchFilters(values)
+}
+
+// New post matching a saved search
+type SearchNotification struct {
+	ID          int
+	SavedSearch SavedSearch
+	VideoID     string
+	Title       string
+	Read        bool
+	CreatedAt   time.Time
+}
+
+// Unsent notifications of a user who wants them in an email digest
+type SearchDigest struct {
+	UserID        int
+	Name          string
+	Email         string
+	Notifications []SearchNotification
+}
+
+// Group of equivalent search terms, e.g. WWII = World War II
+type SynonymGroup struct {
+	ID        int
+	Terms     []string
+	CreatedAt time.Time
+}
+
+// ParseSynonyms parses the comma separated terms of a synonym group.
+// The terms are normalized and deduplicated, the empty ones dropped.
+func ParseSynonyms(input string, maxLen int) []string {
+
+	var terms []string
+	for term := range strings.SplitSeq(input, ",") {
+		term = utils.NormalizeQuery(term, maxLen)
+		if term != "" && !slices.Contains(terms, term) {
+			terms = append(terms, term)
+		}
+	}
+
+	return terms
+}
@@ -0,0 +1,217 @@
+package models
+
+import (
+	"net/url"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParseSearchFilters(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		query    string
+		expected SearchFilters
+	}{
+		{"no filters", "q=war", SearchFilters{}},
+		{"all filters",
+			"category=history&source=PL123&duration=feature&year_from=1990&year_to=1999&min_rating=4",
+			SearchFilters{"history", "PL123", "feature", 1990, 1999, 4},
+		},
+		{"swapped years", "year_from=1999&year_to=1990", SearchFilters{YearFrom: 1990, YearTo: 1999}},
+		{"invalid category", "category=his%20tory", SearchFilters{}},
+		{"unknown duration", "duration=epic", SearchFilters{}},
+		{"year out of range", "year_from=1200", SearchFilters{}},
+		{"unknown rating", "min_rating=5", SearchFilters{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := ParseSearchFilters(values); got != tt.expected {
+				t.Errorf("got %+v, want %+v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSearchFiltersKey(t *testing.T) {
+
+	a, _ := url.ParseQuery("duration=long&category=history&q=war")
+	b, _ := url.ParseQuery("category=history&duration=long&bogus=1")
+
+	keyA := ParseSearchFilters(a).Key()
+	keyB := ParseSearchFilters(b).Key()
+
+	if keyA != keyB {
+		t.Errorf("got different keys %q and %q", keyA, keyB)
+	}
+
+	if want := "category=history&duration=long"; keyA != want {
+		t.Errorf("got %q, want %q", keyA, want)
+	}
+}
+
+func TestSearchFacetsDecadeToggle(t *testing.T) {
+
+	year := time.Now().Year()
+	current := strconv.Itoa(year / 10 * 10)
+
+	tests := []struct {
+		name   string
+		decade string
+	}{
+		{"past decade", "1990"},
+		{"current decade", current},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			// Click the decade facet
+			facets := (&SearchFacets{Decades: []Facet{{Value: tt.decade}}}).
+				WithLinks("/search/", "war", SearchFilters{})
+			if facets.Decades[0].Active {
+				t.Fatal("the decade facet is active without the filter")
+			}
+
+			link, err := url.Parse(facets.Decades[0].URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// The resulting search shows the facet as active
+			filters := ParseSearchFilters(link.Query())
+			if filters.YearFrom == 0 || filters.YearTo == 0 || filters.YearTo > year {
+				t.Fatalf("got invalid years filter %+v", filters)
+			}
+
+			facets = (&SearchFacets{Decades: []Facet{{Value: tt.decade}}}).
+				WithLinks("/search/", "war", filters)
+			if !facets.Decades[0].Active {
+				t.Fatalf("the decade facet is not active with the filter %+v", filters)
+			}
+
+			// Clicking it again removes the filter
+			link, err = url.Parse(facets.Decades[0].URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := ParseSearchFilters(link.Query()); got != (SearchFilters{}) {
+				t.Errorf("got filters %+v after the toggle, want none", got)
+			}
+		})
+	}
+}
+
+func TestSearchFacetsWithLinksConcurrent(t *testing.T) {
+
+	// The same facets shared among the identical searches, as the singleflight does
+	shared := &SearchFacets{
+		Categories: []Facet{{Value: "history", Label: "History", Count: 3}},
+		Sources:    []Facet{{Value: "PL123", Label: "Docs", Count: 2}},
+		Durations:  []Facet{{Value: "feature", Label: "Feature", Count: 1}},
+		Decades:    []Facet{{Value: "1990", Label: "1990s", Count: 4}},
+		Ratings:    []Facet{{Value: "4", Label: "4+", Count: 5}},
+	}
+
+	filters := SearchFilters{Category: "history"}
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Go(func() {
+			linked := shared.WithLinks("/search/", "war", filters)
+			for _, group := range linked.Groups() {
+				for _, facet := range group.Items {
+					if facet.URL == "" {
+						t.Errorf("facet %q without a link", facet.Value)
+					}
+				}
+			}
+
+			// Reading the shared facets while the others link them
+			for _, group := range shared.Groups() {
+				for _, facet := range group.Items {
+					_ = facet.URL
+				}
+			}
+		})
+	}
+	wg.Wait()
+
+	for _, group := range shared.Groups() {
+		for _, facet := range group.Items {
+			if facet.URL != "" || facet.Active {
+				t.Errorf("the shared facet %q got linked", facet.Value)
+			}
+		}
+	}
+}
+
+func TestSavedSearch(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		query   string
+		filters string
+		url     string
+	}{
+		{"no filters", "war", "", "/search/?q=war"},
+		{"filters", "cold war", "min_rating=4&category=history",
+			"/search/?category=history&min_rating=4&q=cold+war",
+		},
+		{"invalid filters", "war", "duration=epic&%zz", "/search/?q=war"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			search := SavedSearch{Query: tt.query}
+			search.ParseFilters(tt.filters)
+
+			if got := search.URL(); got != tt.url {
+				t.Errorf("got URL %q, want %q", got, tt.url)
+			}
+
+			// The stored filters parse back into the same search
+			again := SavedSearch{Query: tt.query}
+			again.ParseFilters(search.Filters.Key())
+			if again.Key() != search.Key() {
+				t.Errorf("got key %q, want %q", again.Key(), search.Key())
+			}
+		})
+	}
+}
+
+func TestParseSynonyms(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		input    string
+		maxLen   int
+		expected []string
+	}{
+		{"empty", " , ", 0, nil},
+		{"normalized", "WWII,  World   War II ,Second World War", 0,
+			[]string{"wwii", "world war ii", "second world war"},
+		},
+		{"duplicates", "USSR, ussr, Soviet Union", 0, []string{"ussr", "soviet union"}},
+		{"truncated", "abcdefgh, xyz", 5, []string{"abcde", "xyz"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseSynonyms(tt.input, tt.maxLen); !slices.Equal(got, tt.expected) {
+				t.Errorf("got %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -7,6 +7,19 @@ type SitemapItem struct {
 	BucketId     int
 	Location     string
 	LastModified string
+	Video        *SitemapVideo `json:",omitempty"`
+}
+
+// Video details of a post sitemap item
+// https://developers.google.com/search/docs/crawling-indexing/sitemaps/video-sitemaps
+type SitemapVideo struct {
+	ThumbnailLoc    string
+	Title           string
+	Description     string
+	PlayerLoc       string
+	Duration        int // seconds, zero if unknown
+	PublicationDate string
+	Category        string
 }
 
 type SitemapPart struct {
@@ -14,6 +14,23 @@ type Category struct {
 
 type Categories []Category
 
+// Slug finds the slug of the category by its name
+func (cats Categories) Slug(name string) (string, bool) {
+	for _, cat := range cats {
+		if cat.Name == name {
+			return cat.Slug, true
+		}
+	}
+	return "", false
+}
+
+// Category proposed by the model, aggregated across the posts
+type SuggestedCategory struct {
+	Name   string
+	Count  int
+	Titles []string
+}
+
 // MarshalBinary implements the encoding.BinaryMarshaler interface
 func (cats Categories) MarshalBinary() (data []byte, err error) {
 	return json.Marshal(cats)
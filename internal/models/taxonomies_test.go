@@ -0,0 +1,33 @@
+package models
+
+import "testing"
+
+func TestCategoriesSlug(t *testing.T) {
+
+	categories := Categories{
+		{Name: "Science & Technology", Slug: "science-and-technology"},
+		{Name: "History", Slug: "history"},
+	}
+
+	tests := []struct {
+		name     string
+		category string
+		wantSlug string
+		wantOk   bool
+	}{
+		{"found", "History", "history", true},
+		{"found with symbols", "Science & Technology", "science-and-technology", true},
+		{"case sensitive", "history", "", false},
+		{"missing", "Music", "", false},
+		{"empty", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slug, ok := categories.Slug(tt.category)
+			if slug != tt.wantSlug || ok != tt.wantOk {
+				t.Errorf("got (%q, %t), want (%q, %t)", slug, ok, tt.wantSlug, tt.wantOk)
+			}
+		})
+	}
+}
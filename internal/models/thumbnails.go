@@ -52,6 +52,31 @@ func (t *Thumbnails) MaxThumb() (result *Thumbnail) {
 	return result
 }
 
+// Get the biggest thumbnail fitting the maximum dimensions, zero means no limit
+func (t *Thumbnails) FitThumb(maxWidth, maxHeight int64) (result *Thumbnail) {
+
+	thumbs := []*youtube.Thumbnail{
+		t.Default,
+		t.Medium,
+		t.High,
+		t.Standard,
+		t.Maxres,
+	}
+
+	var width int64
+	for _, thumb := range thumbs {
+		if thumb == nil || thumb.Width == 0 || thumb.Width <= width ||
+			(maxWidth > 0 && thumb.Width > maxWidth) ||
+			(maxHeight > 0 && thumb.Height > maxHeight) {
+			continue
+		}
+		result = (*Thumbnail)(thumb)
+		width = thumb.Width
+	}
+
+	return result
+}
+
 // Equal checks two thumbnails equality
 func (a *Thumbnail) Equal(b *Thumbnail) bool {
 	if a == nil && b == nil {
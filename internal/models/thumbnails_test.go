@@ -72,3 +72,37 @@ func TestThumbnailsEqual(t *testing.T) {
 		})
 	}
 }
+
+func TestFitThumb(t *testing.T) {
+
+	thumbs := Thumbnails{
+		Default: &youtube.Thumbnail{Width: 120, Height: 90, Url: "default"},
+		Medium:  &youtube.Thumbnail{Width: 320, Height: 180, Url: "medium"},
+		High:    &youtube.Thumbnail{Width: 480, Height: 360, Url: "high"},
+	}
+
+	tests := []struct {
+		name      string
+		maxWidth  int64
+		maxHeight int64
+		expected  string
+	}{
+		{"no limits", 0, 0, "high"},
+		{"width limit", 400, 0, "medium"},
+		{"height limit", 0, 200, "medium"},
+		{"both limits", 480, 100, "default"},
+		{"too small", 100, 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			if thumb := thumbs.FitThumb(tt.maxWidth, tt.maxHeight); thumb != nil {
+				got = thumb.Url
+			}
+			if got != tt.expected {
+				t.Errorf("got %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
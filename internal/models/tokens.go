@@ -0,0 +1,82 @@
+package models
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// API token scopes
+const (
+	ScopeRead  = "read"  // the safe requests, GET, HEAD and OPTIONS
+	ScopeWrite = "write" // the requests changing data, POST etc.
+	ScopeAdmin = "admin" // the admin pages and actions, on top of read and write
+)
+
+// The token scopes in display order
+var TokenScopes = []string{ScopeRead, ScopeWrite, ScopeAdmin}
+
+// The tokens start with this, so the leaked ones are easy to scan for
+const TokenPrefix = "vst_"
+
+// Personal API token, its value is shown only once on creation
+type APIToken struct {
+	ID         int
+	UserID     int
+	Name       string
+	Prefix     string // start of the token to recognize it by
+	Scopes     []string
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	CreatedAt  *time.Time
+}
+
+// Expired reports whether the token can't be used anymore
+func (t APIToken) Expired() bool {
+	return t.ExpiresAt != nil && !t.ExpiresAt.After(time.Now())
+}
+
+// NewToken generates a random 130 bits token, returns the token and its hash
+func NewToken() (string, []byte) {
+	token := TokenPrefix + rand.Text()
+	return token, HashToken(token)
+}
+
+// HashToken hashes the token for storage and lookup.
+// The tokens are random and long, no need for a slow hash.
+func HashToken(token string) []byte {
+	hash := sha256.Sum256([]byte(token))
+	return hash[:]
+}
+
+// BearerToken extracts the token from the Authorization header
+func BearerToken(r *http.Request) (string, bool) {
+
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// ParseScopes keeps only the known scopes, in display order
+func ParseScopes(scopes []string) []string {
+	return slices.DeleteFunc(slices.Clone(TokenScopes), func(scope string) bool {
+		return !slices.Contains(scopes, scope)
+	})
+}
+
+// RequiredScope returns the scope the request method needs
+func RequiredScope(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return ScopeRead
+	default:
+		return ScopeWrite
+	}
+}
@@ -0,0 +1,122 @@
+package models
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/vlatan/video-store/internal/config"
+)
+
+func TestBearerToken(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		header string
+		want   string
+		wantOk bool
+	}{
+		{"no header", "", "", false},
+		{"bearer", "Bearer vst_abc", "vst_abc", true},
+		{"case insensitive scheme", "bearer vst_abc", "vst_abc", true},
+		{"surrounding spaces", "Bearer  vst_abc ", "vst_abc", true},
+		{"basic auth", "Basic dXNlcjpwYXNz", "", false},
+		{"empty token", "Bearer ", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				r.Header.Set("Authorization", tt.header)
+			}
+
+			got, ok := BearerToken(r)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("got (%q, %v), want (%q, %v)", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestParseScopes(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		scopes []string
+		want   []string
+	}{
+		{"none", nil, []string{}},
+		{"unknown dropped", []string{"read", "root"}, []string{"read"}},
+		{"display order", []string{"admin", "read"}, []string{"read", "admin"}},
+		{"duplicates", []string{"write", "write"}, []string{"write"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseScopes(tt.scopes); !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewToken(t *testing.T) {
+
+	token, hash := NewToken()
+	if !strings.HasPrefix(token, TokenPrefix) {
+		t.Errorf("token %q is missing the %q prefix", token, TokenPrefix)
+	}
+
+	if !bytes.Equal(hash, HashToken(token)) {
+		t.Error("the hash doesn't match the token")
+	}
+
+	if other, _ := NewToken(); other == token {
+		t.Error("two tokens are the same")
+	}
+}
+
+func TestUserScopes(t *testing.T) {
+
+	cfg := &config.Config{AdminProviderUserId: "1", AdminProvider: "google"}
+	admin := func(tokenID int, scopes ...string) *User {
+		return &User{
+			ProviderUserId: "1",
+			Provider:       "google",
+			TokenID:        tokenID,
+			Scopes:         scopes,
+			Config:         cfg,
+		}
+	}
+
+	tests := []struct {
+		name      string
+		user      *User
+		method    string
+		wantScope bool
+		wantAdmin bool
+	}{
+		{"session user", admin(0), http.MethodPost, true, true},
+		{"read token reading", admin(1, ScopeRead), http.MethodGet, true, false},
+		{"read token writing", admin(1, ScopeRead), http.MethodPost, false, false},
+		{"write token writing", admin(1, ScopeWrite), http.MethodDelete, true, false},
+		{"admin token", admin(1, ScopeRead, ScopeAdmin), http.MethodHead, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			if got := tt.user.HasScope(RequiredScope(tt.method)); got != tt.wantScope {
+				t.Errorf("got scope allowed %v, want %v", got, tt.wantScope)
+			}
+
+			if got := tt.user.IsAdmin(); got != tt.wantAdmin {
+				t.Errorf("got admin %v, want %v", got, tt.wantAdmin)
+			}
+		})
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"slices"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -48,6 +49,8 @@ type User struct {
 	Expiry         time.Time
 	LastSeen       *time.Time
 	CreatedAt      *time.Time
+	TokenID        int      // the API token the user authenticated with, if any
+	Scopes         []string // the API token scopes
 	*config.Config
 }
 
@@ -56,11 +59,22 @@ func (u *User) IsAuthenticated() bool {
 	return u != nil && u.ProviderUserId != ""
 }
 
-// Check if the user is Admin
+// Check if the user is Admin, with an API token only if it has the admin scope
 func (u *User) IsAdmin() bool {
 	return u.IsAuthenticated() &&
 		u.ProviderUserId == u.Config.AdminProviderUserId &&
-		u.Provider == u.Config.AdminProvider
+		u.Provider == u.Config.AdminProvider &&
+		u.HasScope(ScopeAdmin)
+}
+
+// Check if the user authenticated with an API token
+func (u *User) IsTokenAuth() bool {
+	return u != nil && u.TokenID != 0
+}
+
+// Check if the user is allowed the scope, the session users are allowed all
+func (u *User) HasScope(scope string) bool {
+	return !u.IsTokenAuth() || slices.Contains(u.Scopes, scope)
 }
 
 // Set the user analytics ID
@@ -6,6 +6,14 @@ import (
 	"github.com/vlatan/video-store/internal/models"
 )
 
+// Check if category exists, with or without posts
+func (r *Repository) CategoryExists(ctx context.Context, slug string) bool {
+	var result int
+	const query = "SELECT 1 FROM category WHERE slug = $1;"
+	err := r.db.Pool.QueryRow(ctx, query, slug).Scan(&result)
+	return err == nil
+}
+
 // Get all valid categories
 func (r *Repository) GetCategories(ctx context.Context) (models.Categories, error) {
 
@@ -0,0 +1,6 @@
+UPDATE post
+SET category_id = $1
+FROM category_suggestion AS cs
+WHERE cs.video_id = post.video_id
+AND LOWER(cs.name) = LOWER($2)
+RETURNING post.video_id;
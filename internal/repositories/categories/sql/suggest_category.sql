@@ -0,0 +1,4 @@
+INSERT INTO category_suggestion (video_id, name)
+VALUES ($1, $2)
+ON CONFLICT (video_id) DO UPDATE
+SET name = EXCLUDED.name;
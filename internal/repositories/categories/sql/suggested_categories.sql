@@ -0,0 +1,11 @@
+SELECT
+    MIN(cs.name) AS name,
+    COUNT(*) AS posts_count,
+    (ARRAY_AGG(
+        COALESCE(post.original_title, post.title)
+        ORDER BY cs.created_at DESC
+    ))[1:5] AS titles -- a few sample titles
+FROM category_suggestion AS cs
+JOIN post ON post.video_id = cs.video_id
+GROUP BY LOWER(cs.name)
+ORDER BY posts_count DESC, name;
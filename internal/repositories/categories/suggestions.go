@@ -0,0 +1,149 @@
+package categories
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/vlatan/video-store/internal/models"
+)
+
+// Record the category suggested by the model for a post
+func (r *Repository) SuggestCategory(ctx context.Context, videoID, name string) (int64, error) {
+
+	query, err := r.GetQuery("suggest_category.sql", nil)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := r.db.Pool.Exec(ctx, query, videoID, name)
+	return result.RowsAffected(), err
+}
+
+// Get the suggested categories aggregated across the posts
+func (r *Repository) GetSuggestedCategories(ctx context.Context) ([]models.SuggestedCategory, error) {
+
+	query, err := r.GetQuery("suggested_categories.sql", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Pool.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var suggestions []models.SuggestedCategory
+	for rows.Next() {
+		var sc models.SuggestedCategory
+		if err := rows.Scan(&sc.Name, &sc.Count, &sc.Titles); err != nil {
+			return nil, err
+		}
+
+		suggestions = append(suggestions, sc)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return suggestions, nil
+}
+
+// Create the category, or use the existing one with the same slug or name,
+// and reassign to it all the posts with the matching suggestion.
+// Sets the name of the existing category on the given one.
+// Returns the video IDs of the reassigned posts.
+func (r *Repository) AcceptSuggestedCategory(
+	ctx context.Context,
+	suggestion string,
+	category *models.Category) ([]string, error) {
+
+	// Start transaction
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Rollback if something goes wrong.
+	// Release the connection in any case.
+	defer func() {
+		rbErr := tx.Rollback(ctx)
+		if rbErr != nil && !errors.Is(rbErr, pgx.ErrTxClosed) {
+			slog.ErrorContext(
+				ctx, "transaction rollback on suggested category failed",
+				"suggestion", suggestion,
+				"error", rbErr,
+			)
+		}
+	}()
+
+	// Both the name and the slug are unique,
+	// i.e. "Sci Fi" has the same slug as the existing "Sci-Fi"
+	const insertQuery = `
+		WITH existing AS (
+			SELECT id, name FROM category WHERE slug = $2
+		), inserted AS (
+			INSERT INTO category (name, slug)
+			SELECT $1, $2 WHERE NOT EXISTS (SELECT 1 FROM existing)
+			ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+			RETURNING id, name
+		)
+		SELECT id, name FROM existing
+		UNION ALL
+		SELECT id, name FROM inserted
+	`
+
+	var categoryID int
+	err = tx.QueryRow(ctx, insertQuery, category.Name, category.Slug).Scan(&categoryID, &category.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	query, err := r.GetQuery("reassign_posts.sql", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Query(ctx, query, categoryID, suggestion)
+	if err != nil {
+		return nil, err
+	}
+
+	var videoIDs []string
+	for rows.Next() {
+		var videoID string
+		if err = rows.Scan(&videoID); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		videoIDs = append(videoIDs, videoID)
+	}
+
+	// Release the rows before the next statement in the transaction
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	const deleteQuery = "DELETE FROM category_suggestion WHERE LOWER(name) = LOWER($1);"
+	if _, err = tx.Exec(ctx, deleteQuery, suggestion); err != nil {
+		return nil, err
+	}
+
+	// Commit the changes
+	if err = tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return videoIDs, nil
+}
+
+// Dismiss all the posts' suggestions matching the suggested category
+func (r *Repository) DismissSuggestedCategory(ctx context.Context, suggestion string) (int64, error) {
+	const query = "DELETE FROM category_suggestion WHERE LOWER(name) = LOWER($1);"
+	result, err := r.db.Pool.Exec(ctx, query, suggestion)
+	return result.RowsAffected(), err
+}
@@ -0,0 +1,103 @@
+package indexnow
+
+import (
+	"context"
+	"embed"
+	"io/fs"
+	"text/template"
+	"time"
+
+	"github.com/vlatan/video-store/internal/drivers/database"
+	repo "github.com/vlatan/video-store/internal/repositories"
+)
+
+//go:embed sql/*.sql
+var sqlFS embed.FS
+
+type Repository struct {
+	db      *database.Service
+	queries *template.Template
+}
+
+func New(db *database.Service, fsys fs.FS) (*Repository, error) {
+
+	if fsys == nil {
+		fsys = sqlFS
+	}
+
+	queries, err := template.ParseFS(fsys, "sql/*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	return &Repository{db, queries}, nil
+}
+
+func (r *Repository) GetQuery(name string, sqlParts any) (string, error) {
+	return repo.GetQuery(r.queries, name, sqlParts)
+}
+
+// Queue the changed URLs for submission
+func (r *Repository) QueueURLs(ctx context.Context, urls []string) (int64, error) {
+
+	query, err := r.GetQuery("queue_urls.sql", nil)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := r.db.Pool.Exec(ctx, query, urls)
+	return result.RowsAffected(), err
+}
+
+// Get the oldest pending URLs with the time the newest of them was queued
+func (r *Repository) PendingURLs(ctx context.Context, maxAttempts, limit int) ([]string, time.Time, error) {
+
+	var queuedAt time.Time
+	query, err := r.GetQuery("pending_urls.sql", nil)
+	if err != nil {
+		return nil, queuedAt, err
+	}
+
+	rows, err := r.db.Pool.Query(ctx, query, maxAttempts, limit)
+	if err != nil {
+		return nil, queuedAt, err
+	}
+
+	// Close rows on exit
+	defer rows.Close()
+
+	var urls []string
+	for rows.Next() {
+		var url string
+		if err = rows.Scan(&url, &queuedAt); err != nil {
+			return nil, queuedAt, err
+		}
+		urls = append(urls, url)
+	}
+
+	return urls, queuedAt, rows.Err()
+}
+
+// Mark the URLs submitted, except the ones queued again after queuedAt
+func (r *Repository) MarkSubmitted(ctx context.Context, urls []string, queuedAt time.Time) (int64, error) {
+
+	query, err := r.GetQuery("mark_submitted.sql", nil)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := r.db.Pool.Exec(ctx, query, urls, queuedAt)
+	return result.RowsAffected(), err
+}
+
+// Record the failed submission of the URLs
+func (r *Repository) MarkFailed(ctx context.Context, urls []string, reason string) (int64, error) {
+
+	query, err := r.GetQuery("mark_failed.sql", nil)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := r.db.Pool.Exec(ctx, query, urls, reason)
+	return result.RowsAffected(), err
+}
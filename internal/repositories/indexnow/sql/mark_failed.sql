@@ -0,0 +1,6 @@
+-- Record the failed submission attempt of the URLs
+UPDATE indexnow_url
+SET
+    attempts = attempts + 1,
+    last_error = $2
+WHERE url = ANY($1);
@@ -0,0 +1,5 @@
+-- Mark the URLs submitted, unless queued again in the meantime
+UPDATE indexnow_url
+SET submitted_at = CURRENT_TIMESTAMP
+WHERE url = ANY($1)
+AND queued_at <= $2;
@@ -0,0 +1,7 @@
+-- The oldest URLs not submitted yet, leaving out the ones failing too many times
+SELECT url, queued_at
+FROM indexnow_url
+WHERE submitted_at IS NULL
+AND attempts < $1
+ORDER BY queued_at
+LIMIT $2;
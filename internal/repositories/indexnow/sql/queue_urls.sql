@@ -0,0 +1,9 @@
+-- Queue the changed URLs, the already submitted ones are queued again
+INSERT INTO indexnow_url (url)
+SELECT DISTINCT UNNEST($1::text[])
+ON CONFLICT (url) DO UPDATE
+SET
+    queued_at = CURRENT_TIMESTAMP,
+    submitted_at = NULL,
+    attempts = 0,
+    last_error = NULL;
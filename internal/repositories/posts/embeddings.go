@@ -0,0 +1,335 @@
+package posts
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"slices"
+	"sync"
+
+	"github.com/vlatan/video-store/internal/models"
+	"github.com/vlatan/video-store/internal/utils"
+)
+
+// Get posts with missing or stale embeddings for the given model
+func (r *Repository) GetPostsToEmbed(ctx context.Context, model string, limit int) ([]*models.Post, error) {
+
+	query, err := r.GetQuery("posts_to_embed.sql", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get rows from DB
+	rows, err := r.db.Pool.Query(ctx, query, model, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	// Close rows on exit
+	defer rows.Close()
+
+	// Iterate over the rows
+	var posts []*models.Post
+	for rows.Next() {
+		var post models.Post
+		var originalTitle, summary sql.NullString
+
+		if err = rows.Scan(
+			&post.ID,
+			&post.VideoID,
+			&post.Title,
+			&originalTitle,
+			&summary,
+		); err != nil {
+			return nil, err
+		}
+
+		post.OriginalTitle = utils.FromNullString(originalTitle)
+		post.Summary = utils.FromNullString(summary)
+		posts = append(posts, &post)
+	}
+
+	// If error during iteration
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return posts, nil
+}
+
+// Insert or update post's embedding
+func (r *Repository) UpsertEmbedding(
+	ctx context.Context,
+	postID int,
+	model string,
+	embedding []float32,
+) (int64, error) {
+
+	query, err := r.GetQuery("upsert_embedding.sql", nil)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := r.db.Pool.Exec(ctx, query, postID, model, embedding)
+	return result.RowsAffected(), err
+}
+
+// Get post's nearest neighbours by cosine similarity of their embeddings.
+// Returns no posts if the post has no embedding for the given model.
+func (r *Repository) GetNearestPosts(
+	ctx context.Context,
+	videoID string,
+	model string,
+	limit int,
+) (models.Posts, error) {
+
+	var zero models.Posts
+
+	indexed, err := r.hasVectorIndex(ctx)
+	if err != nil {
+		return zero, err
+	}
+
+	var ids []int
+	if indexed {
+		ids, err = r.indexedNeighbours(ctx, videoID, model, limit)
+	} else {
+		ids, err = r.computedNeighbours(ctx, videoID, model, limit)
+	}
+
+	if err != nil {
+		return zero, err
+	}
+
+	return r.getPostsByIDs(ctx, ids)
+}
+
+// Get the IDs of the post's nearest neighbours from the pgvector HNSW index
+func (r *Repository) indexedNeighbours(
+	ctx context.Context,
+	videoID string,
+	model string,
+	limit int,
+) ([]int, error) {
+
+	query, err := r.GetQuery("nearest_posts.sql", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get rows from DB
+	rows, err := r.db.Pool.Query(ctx, query, videoID, model, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	// Close rows on exit
+	defer rows.Close()
+
+	// The IDs of the nearest posts, the nearest first
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err = rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// Get the IDs of the post's nearest neighbours
+// by comparing all the embeddings for the given model
+func (r *Repository) computedNeighbours(
+	ctx context.Context,
+	videoID string,
+	model string,
+	limit int,
+) ([]int, error) {
+
+	query, err := r.GetQuery("post_embeddings.sql", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get rows from DB
+	rows, err := r.db.Pool.Query(ctx, query, model)
+	if err != nil {
+		return nil, err
+	}
+
+	// Close rows on exit
+	defer rows.Close()
+
+	// Collect all the embeddings
+	var target []float32
+	embeddings := make(map[int][]float32)
+	for rows.Next() {
+		var id int
+		var postVideoID string
+		var embedding []float32
+		if err = rows.Scan(&id, &postVideoID, &embedding); err != nil {
+			return nil, err
+		}
+
+		if postVideoID == videoID {
+			target = embedding
+			continue
+		}
+
+		embeddings[id] = embedding
+	}
+
+	// If error during iteration
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(target) == 0 {
+		return nil, nil
+	}
+
+	return nearestNeighbours(target, embeddings, limit), nil
+}
+
+// Get posts by their IDs, preserving the order of the IDs
+func (r *Repository) getPostsByIDs(ctx context.Context, ids []int) (models.Posts, error) {
+
+	var zero, posts models.Posts
+	if len(ids) == 0 {
+		return zero, nil
+	}
+
+	query, err := r.GetQuery("posts_by_ids.sql", nil)
+	if err != nil {
+		return zero, err
+	}
+
+	// Get rows from DB
+	rows, err := r.db.Pool.Query(ctx, query, ids)
+	if err != nil {
+		return zero, err
+	}
+
+	// Close rows on exit
+	defer rows.Close()
+
+	// Iterate over the rows
+	for rows.Next() {
+
+		var (
+			post          models.Post
+			originalTitle sql.NullString
+			avgRating     sql.NullFloat64
+			ratingCount   sql.NullInt64
+		)
+
+		if err = rows.Scan(
+			&post.VideoID,
+			&post.Title,
+			&originalTitle,
+			&post.RawThumbs,
+			&post.Likes,
+			&avgRating,
+			&ratingCount,
+		); err != nil {
+			return zero, err
+		}
+
+		// Include the processed post in the result
+		post.OriginalTitle = utils.FromNullString(originalTitle)
+
+		// Attach ratings if any
+		if avgRating.Valid && ratingCount.Valid {
+			post.Rating = &models.Rating{
+				Avg:   utils.FromNullFloat64(avgRating),
+				Count: utils.FromNullInt64(ratingCount),
+			}
+		}
+
+		posts.Items = append(posts.Items, post)
+	}
+
+	// If error during iteration
+	if err = rows.Err(); err != nil {
+		return zero, err
+	}
+
+	// Post-process the posts, prepare the thumbnail
+	if err = postProcessPosts(ctx, posts); err != nil {
+		return zero, err
+	}
+
+	return posts, nil
+}
+
+// nearestNeighbours returns the IDs of the k most similar embeddings to the target
+func nearestNeighbours(target []float32, embeddings map[int][]float32, k int) []int {
+
+	if k <= 0 {
+		return nil
+	}
+
+	type neighbour struct {
+		id    int
+		score float64
+	}
+
+	neighbours := make([]neighbour, 0, len(embeddings))
+	for id, embedding := range embeddings {
+		neighbours = append(neighbours, neighbour{id, utils.CosineSimilarity(target, embedding)})
+	}
+
+	// Sort by score descending, break the ties by ID for stable results
+	slices.SortFunc(neighbours, func(a, b neighbour) int {
+		if c := cmp.Compare(b.score, a.score); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.id, a.id)
+	})
+
+	ids := make([]int, 0, min(k, len(neighbours)))
+	for _, n := range neighbours[:min(k, len(neighbours))] {
+		ids = append(ids, n.id)
+	}
+
+	return ids
+}
+
+// Whether the pgvector HNSW index on the embeddings exists.
+// The migration creates it only if the extension is available.
+type vectorIndex struct {
+	mu      sync.RWMutex
+	checked bool
+	found   bool
+}
+
+// hasVectorIndex reports whether the HNSW index exists, checked once.
+// A failed check is retried on the next call.
+func (r *Repository) hasVectorIndex(ctx context.Context) (bool, error) {
+
+	r.vectorIndex.mu.RLock()
+	checked, found := r.vectorIndex.checked, r.vectorIndex.found
+	r.vectorIndex.mu.RUnlock()
+
+	if checked {
+		return found, nil
+	}
+
+	query, err := r.GetQuery("vector_index.sql", nil)
+	if err != nil {
+		return false, err
+	}
+
+	if err = r.db.Pool.QueryRow(ctx, query).Scan(&found); err != nil {
+		return false, err
+	}
+
+	r.vectorIndex.mu.Lock()
+	r.vectorIndex.checked = true
+	r.vectorIndex.found = found
+	r.vectorIndex.mu.Unlock()
+
+	return found, nil
+}
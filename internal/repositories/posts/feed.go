@@ -0,0 +1,78 @@
+package posts
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/vlatan/video-store/internal/models"
+	"github.com/vlatan/video-store/internal/utils"
+)
+
+// AddFeedDetails attaches the summary, the category and the timestamps
+// to the posts, the details the lists don't need but the feeds do
+func (r *Repository) AddFeedDetails(ctx context.Context, posts []models.Post) error {
+
+	if len(posts) == 0 {
+		return nil
+	}
+
+	query, err := r.GetQuery("feed_details.sql", nil)
+	if err != nil {
+		return err
+	}
+
+	index := make(map[string]int, len(posts))
+	videoIDs := make([]string, len(posts))
+	for i, post := range posts {
+		index[post.VideoID] = i
+		videoIDs[i] = post.VideoID
+	}
+
+	rows, err := r.db.Pool.Query(ctx, query, videoIDs)
+	if err != nil {
+		return err
+	}
+
+	// Close rows on exit
+	defer rows.Close()
+
+	for rows.Next() {
+
+		var (
+			videoID                             string
+			summary, categorySlug, categoryName sql.NullString
+			createdAt, updatedAt                sql.NullTime
+		)
+
+		if err = rows.Scan(
+			&videoID,
+			&summary,
+			&categorySlug,
+			&categoryName,
+			&createdAt,
+			&updatedAt,
+		); err != nil {
+			return err
+		}
+
+		post := &posts[index[videoID]]
+		post.Summary = utils.FromNullString(summary)
+
+		if categorySlug.Valid && categoryName.Valid {
+			post.Category = &models.Category{
+				Slug: categorySlug.String,
+				Name: categoryName.String,
+			}
+		}
+
+		if createdAt.Valid {
+			post.CreatedAt = &createdAt.Time
+		}
+
+		if updatedAt.Valid {
+			post.UpdatedAt = &updatedAt.Time
+		}
+	}
+
+	return rows.Err()
+}
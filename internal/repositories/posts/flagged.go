@@ -0,0 +1,126 @@
+package posts
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/vlatan/video-store/internal/models"
+	"github.com/vlatan/video-store/internal/utils"
+)
+
+// Check if the post is flagged for review
+func (r *Repository) IsPostFlagged(ctx context.Context, videoID string) error {
+	var result int
+	const query = "SELECT 1 FROM flagged_post WHERE video_id = $1;"
+	return r.db.Pool.QueryRow(ctx, query, videoID).Scan(&result)
+}
+
+// Flag a post for review with the quality issues and the rejected response
+func (r *Repository) FlagPost(
+	ctx context.Context,
+	videoID string,
+	issues []string,
+	response *models.GenaiResponse,
+) (int64, error) {
+
+	// Marshal the rejected response
+	rawResponse, err := json.Marshal(response)
+	if err != nil {
+		return 0, err
+	}
+
+	query, err := r.GetQuery("flag_post.sql", nil)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := r.db.Pool.Exec(ctx, query, videoID, issues, rawResponse)
+	return result.RowsAffected(), err
+}
+
+// Remove the review flag from a post
+func (r *Repository) UnflagPost(ctx context.Context, videoID string) (int64, error) {
+	const query = "DELETE FROM flagged_post WHERE video_id = $1;"
+	result, err := r.db.Pool.Exec(ctx, query, videoID)
+	return result.RowsAffected(), err
+}
+
+// Get a single flagged post
+func (r *Repository) GetFlaggedPost(ctx context.Context, videoID string) (models.FlaggedPost, error) {
+
+	var zero models.FlaggedPost
+	const query = "SELECT video_id, issues, response FROM flagged_post WHERE video_id = $1;"
+
+	var fp models.FlaggedPost
+	var rawResponse []byte
+	err := r.db.Pool.QueryRow(ctx, query, videoID).Scan(&fp.VideoID, &fp.Issues, &rawResponse)
+	if err != nil {
+		return zero, err
+	}
+
+	if len(rawResponse) > 0 {
+		if err = json.Unmarshal(rawResponse, &fp.Response); err != nil {
+			return zero, err
+		}
+	}
+
+	return fp, nil
+}
+
+// Get all the posts flagged for review
+func (r *Repository) GetFlaggedPosts(ctx context.Context) ([]models.FlaggedPost, error) {
+
+	query, err := r.GetQuery("flagged_posts.sql", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get rows from DB
+	rows, err := r.db.Pool.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// Close rows on exit
+	defer rows.Close()
+
+	// Iterate over the rows
+	var posts []models.FlaggedPost
+	for rows.Next() {
+		var fp models.FlaggedPost
+		var originalTitle sql.NullString
+		var rawResponse []byte
+
+		if err = rows.Scan(
+			&fp.VideoID,
+			&fp.Title,
+			&originalTitle,
+			&fp.Issues,
+			&rawResponse,
+			&fp.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		// Prefer the original title if any
+		if ot := utils.FromNullString(originalTitle); ot != "" {
+			fp.Title = ot
+		}
+
+		if len(rawResponse) > 0 {
+			if err = json.Unmarshal(rawResponse, &fp.Response); err != nil {
+				return nil, err
+			}
+		}
+
+		posts = append(posts, fp)
+	}
+
+	// If error during iteration
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return posts, nil
+}
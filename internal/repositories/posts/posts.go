@@ -14,9 +14,11 @@ import (
 var sqlFS embed.FS
 
 type Repository struct {
-	db      *database.Service
-	config  *config.Config
-	queries *template.Template
+	db          *database.Service
+	config      *config.Config
+	queries     *template.Template
+	synonyms    *synonyms
+	vectorIndex *vectorIndex
 }
 
 func New(db *database.Service, config *config.Config, fsys fs.FS) (*Repository, error) {
@@ -30,7 +32,7 @@ func New(db *database.Service, config *config.Config, fsys fs.FS) (*Repository,
 		return nil, err
 	}
 
-	return &Repository{db, config, queries}, nil
+	return &Repository{db, config, queries, &synonyms{}, &vectorIndex{}}, nil
 }
 
 func (r *Repository) GetQuery(name string, sqlParts any) (string, error) {
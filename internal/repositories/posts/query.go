@@ -0,0 +1,164 @@
+package posts
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Field prefixes scoping a search term, e.g. title:cosmos
+const (
+	fieldTitle    = "title"
+	fieldCategory = "category"
+	fieldSource   = "source"
+)
+
+// Search query parsed from the advanced search syntax:
+// "exact phrase", -excluded, title:term, category:name and source:name.
+// The invalid syntax degrades to plain words, the parser never fails.
+type searchQuery struct {
+	terms    []string // plain words
+	phrases  []string // quoted exact phrases
+	excluded []string // excluded words or phrases
+	title    []string // words to match in the title only
+	category string   // category slug or name
+	source   string   // source playlist ID or part of its title
+}
+
+// parseSearchQuery parses the user input into a search query
+func parseSearchQuery(input string) searchQuery {
+
+	var q searchQuery
+	runes := []rune(input)
+
+	for i := 0; i < len(runes); {
+
+		// Skip the whitespace between tokens
+		if unicode.IsSpace(runes[i]) {
+			i++
+			continue
+		}
+
+		// Excluded term or phrase
+		exclude := false
+		if runes[i] == '-' {
+			exclude = true
+			i++
+		}
+
+		// Field prefix, only the known fields, anything else is a plain word
+		field := ""
+		for _, f := range []string{fieldTitle, fieldCategory, fieldSource} {
+			prefix := []rune(f + ":")
+			if hasPrefixFold(runes[i:], prefix) {
+				field = f
+				i += len(prefix)
+				break
+			}
+		}
+
+		// Quoted phrase, an unclosed quote runs until the end of the input
+		var value string
+		quoted := i < len(runes) && runes[i] == '"'
+		if quoted {
+			i++
+			end := i
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			value = string(runes[i:end])
+			i = min(end+1, len(runes))
+		} else {
+			end := i
+			for end < len(runes) && !unicode.IsSpace(runes[end]) {
+				end++
+			}
+			value = string(runes[i:end])
+			i = end
+		}
+
+		// Drop the stray quotes and whitespace
+		value = strings.Join(strings.Fields(strings.ReplaceAll(value, `"`, " ")), " ")
+		if value == "" {
+			continue
+		}
+
+		switch {
+		case exclude:
+			q.excluded = append(q.excluded, value)
+		case field == fieldTitle:
+			q.title = append(q.title, value)
+		case field == fieldCategory:
+			q.category = value
+		case field == fieldSource:
+			q.source = value
+		case quoted && strings.Contains(value, " "):
+			q.phrases = append(q.phrases, value)
+		default:
+			q.terms = append(q.terms, value)
+		}
+	}
+
+	return q
+}
+
+// text returns all the positive words to rank the posts by
+func (q searchQuery) text() string {
+	parts := make([]string, 0, len(q.terms)+len(q.phrases)+len(q.title))
+	parts = append(parts, q.terms...)
+	parts = append(parts, q.phrases...)
+	parts = append(parts, q.title...)
+	return strings.Join(parts, " ")
+}
+
+// empty tells if there's nothing to search for, e.g. only exclusions.
+// The category and the source alone narrow down all the posts.
+func (q searchQuery) empty() bool {
+	return q.text() == "" && q.category == "" && q.source == ""
+}
+
+// conditions returns the SQL conditions on the scored posts enforcing
+// the phrases, exclusions and fields. The values are bound as arguments,
+// add appends the arguments and formats their placeholders in the condition.
+func (q searchQuery) conditions(add func(condition string, values ...any)) {
+
+	for _, phrase := range q.phrases {
+		add("search_vector @@ phraseto_tsquery(search_regconfig(), $%d)", phrase)
+	}
+
+	// The posts also match through their reviews, so exclude the reviews too
+	for _, excluded := range q.excluded {
+		add(
+			"NOT (search_vector @@ phraseto_tsquery(search_regconfig(), $%[1]d)) "+
+				"AND NOT EXISTS (SELECT 1 FROM post_review AS pr WHERE pr.post_id = scored_posts.id "+
+				"AND pr.search_vector @@ phraseto_tsquery(search_regconfig(), $%[1]d))",
+			excluded,
+		)
+	}
+
+	if len(q.title) > 0 {
+		add(
+			"to_tsvector(search_regconfig(), title || ' ' || COALESCE(original_title, '')) "+
+				"@@ plainto_tsquery(search_regconfig(), $%d)",
+			strings.Join(q.title, " "),
+		)
+	}
+
+	if q.category != "" {
+		add("(category_slug = $%[1]d OR LOWER(category_name) = LOWER($%[1]d))", q.category)
+	}
+
+	if q.source != "" {
+		add(
+			"(playlist_id = $%d OR playlist_title ILIKE '%%' || $%d || '%%')",
+			q.source, likeEscaper.Replace(q.source),
+		)
+	}
+}
+
+// Check if runes start with the prefix case insensitive
+func hasPrefixFold(runes, prefix []rune) bool {
+	if len(runes) < len(prefix) {
+		return false
+	}
+	return strings.EqualFold(string(runes[:len(prefix)]), string(prefix))
+}
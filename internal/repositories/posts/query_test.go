@@ -0,0 +1,111 @@
+package posts
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseSearchQuery(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		input    string
+		expected searchQuery
+	}{
+		{"empty", "", searchQuery{}},
+		{"plain words", "world  war", searchQuery{terms: []string{"world", "war"}}},
+		{"phrase", `"cold war" spies`, searchQuery{
+			terms:   []string{"spies"},
+			phrases: []string{"cold war"},
+		}},
+		{"single quoted word", `"war"`, searchQuery{terms: []string{"war"}}},
+		{"unclosed quote", `war "cold peace`, searchQuery{
+			terms:   []string{"war"},
+			phrases: []string{"cold peace"},
+		}},
+		{"exclusions", `war -vietnam -"cold war"`, searchQuery{
+			terms:    []string{"war"},
+			excluded: []string{"vietnam", "cold war"},
+		}},
+		{"lone dash", "war - peace", searchQuery{terms: []string{"war", "peace"}}},
+		{"hyphenated word", "re-entry", searchQuery{terms: []string{"re-entry"}}},
+		{"fields", `Title:apollo category:history source:"BBC Earth"`, searchQuery{
+			title:    []string{"apollo"},
+			category: "history",
+			source:   "BBC Earth",
+		}},
+		{"empty field", "title: moon", searchQuery{terms: []string{"moon"}}},
+		{"unknown field", "cosmos: odyssey", searchQuery{terms: []string{"cosmos:", "odyssey"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseSearchQuery(tt.input); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("got %+v, want %+v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSearchQueryText(t *testing.T) {
+	query := parseSearchQuery(`moon "space race" -soviet title:apollo source:nasa`)
+	if got, want := query.text(), "moon space race apollo"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestSearchQueryEmpty(t *testing.T) {
+
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"", true},
+		{"-soviet", true},
+		{`"  "`, true},
+		{"moon", false},
+		{"title:apollo", false},
+		{"category:history", false},
+		{`source:"BBC Earth"`, false},
+		{"-soviet category:history", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := parseSearchQuery(tt.input).empty(); got != tt.expected {
+				t.Errorf("got %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSearchQueryExclusions(t *testing.T) {
+
+	var conditions []string
+	var args []any
+	parseSearchQuery(`moon -soviet`).conditions(func(condition string, values ...any) {
+		placeholders := make([]any, len(values))
+		for i, value := range values {
+			args = append(args, value)
+			placeholders[i] = len(args)
+		}
+		conditions = append(conditions, fmt.Sprintf(condition, placeholders...))
+	})
+
+	if len(conditions) != 1 || !reflect.DeepEqual(args, []any{"soviet"}) {
+		t.Fatalf("got conditions %q with args %v, want one with the excluded word", conditions, args)
+	}
+
+	// Neither the post nor its reviews may match the excluded word
+	for _, want := range []string{"NOT (search_vector @@", "NOT EXISTS (SELECT 1 FROM post_review"} {
+		if !strings.Contains(conditions[0], want) {
+			t.Errorf("the condition %q doesn't contain %q", conditions[0], want)
+		}
+	}
+
+	if strings.Count(conditions[0], "$1") != 2 {
+		t.Errorf("the condition %q doesn't bind the excluded word twice", conditions[0])
+	}
+}
@@ -0,0 +1,60 @@
+package posts
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// SyncSearchConfig switches the text search configuration to the configured one
+// and rebuilds the search vectors if it changed. Reports whether it rebuilt them.
+func (r *Repository) SyncSearchConfig(ctx context.Context) (bool, error) {
+
+	syncQuery, err := r.GetQuery("sync_search_config.sql", nil)
+	if err != nil {
+		return false, err
+	}
+
+	rebuildQuery, err := r.GetQuery("rebuild_search_vectors.sql", nil)
+	if err != nil {
+		return false, err
+	}
+
+	// Start transaction, the searches see either the old or the new vectors
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	// Rollback if something goes wrong.
+	// Release the connection in any case.
+	defer func() {
+		rbErr := tx.Rollback(ctx)
+		if rbErr != nil && !errors.Is(rbErr, pgx.ErrTxClosed) {
+			slog.ErrorContext(
+				ctx, "transaction rollback on search config failed",
+				"regconfig", r.config.SearchConfig,
+				"error", rbErr,
+			)
+		}
+	}()
+
+	result, err := tx.Exec(ctx, syncQuery, r.config.SearchConfig)
+	if err != nil {
+		return false, err
+	}
+
+	// The configuration didn't change
+	if result.RowsAffected() == 0 {
+		return false, tx.Commit(ctx)
+	}
+
+	// No arguments, the multiple statements run in a single call
+	if _, err = tx.Exec(ctx, rebuildQuery); err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit(ctx)
+}
@@ -2,25 +2,45 @@ package posts
 
 import (
 	"context"
+	"log/slog"
+	"slices"
 
 	"github.com/vlatan/video-store/internal/models"
 )
 
-// Get post's related posts based on provided title as search query
-func (r *Repository) GetRelatedPosts(ctx context.Context, title string) (models.Posts, error) {
+// Get post's related posts based on the embeddings' nearest neighbours.
+// Falls back to search with provided title as search query and random posts.
+func (r *Repository) GetRelatedPosts(ctx context.Context, videoID, title string) (models.Posts, error) {
 
 	var zero, posts models.Posts
 	nrp := r.config.NumRelatedPosts
 
+	// Get the semantically nearest posts if any.
+	// Log the error, we can still fallback to search.
+	nearestPosts, err := r.GetNearestPosts(ctx, videoID, r.config.GeminiEmbeddingModel, nrp)
+	if err != nil {
+		slog.ErrorContext(
+			ctx, "failed to get the nearest posts",
+			"videoId", videoID,
+			"error", err,
+		)
+	}
+
+	posts.Items = append(posts.Items, nearestPosts.Items...)
+	if len(posts.Items) >= nrp {
+		posts.Items = posts.Items[:nrp]
+		return posts, nil
+	}
+
 	// Search the DB for posts
-	searchedPosts, err := r.SearchPosts(ctx, title, nrp+1, "")
+	searchedPosts, err := r.SearchPosts(ctx, title, models.SearchFilters{}, nrp+1, "")
 
 	if err != nil {
 		return zero, err
 	}
 
 	for _, sp := range searchedPosts.Items {
-		if sp.GetTitle() != title {
+		if sp.GetTitle() != title && !containsPost(posts.Items, sp.VideoID) {
 			posts.Items = append(posts.Items, sp)
 		}
 	}
@@ -47,3 +67,10 @@ func (r *Repository) GetRelatedPosts(ctx context.Context, title string) (models.
 
 	return posts, nil
 }
+
+// containsPost checks if a post with the given video ID is in the posts
+func containsPost(posts []models.Post, videoID string) bool {
+	return slices.ContainsFunc(posts, func(p models.Post) bool {
+		return p.VideoID == videoID
+	})
+}
@@ -6,29 +6,67 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"html"
+	"html/template"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/vlatan/video-store/internal/models"
 	"github.com/vlatan/video-store/internal/utils"
 )
 
-// Get posts based on a user search query using a cursor
+// Private use characters marking the matched terms in the snippets,
+// they can't clash with the HTML escaping of the snippet text
+const (
+	markStart = "\uE000"
+	markStop  = "\uE001"
+)
+
+// Options of ts_headline for the search results snippets
+var headlineOptions = fmt.Sprintf(
+	`StartSel=%s, StopSel=%s, MinWords=15, MaxWords=35, MaxFragments=2, FragmentDelimiter=" … "`,
+	markStart, markStop,
+)
+
+// Replace the markers on the matched terms with mark tags
+var markReplacer = strings.NewReplacer(markStart, "<mark>", markStop, "</mark>")
+
+// SQL parts of the search_matches.sql template, shared by the search queries
+type matchParts struct {
+	FilterCondition string // the filters WHERE condition if any
+	TsQuery         string // the expanded query placeholder, empty to search all the posts
+	VideoIDs        string // the video IDs placeholder, empty to search all the posts
+	MatchAll        bool   // match all the words, there's no ranking to put such matches first
+}
+
+// Get posts based on a user search query and filters using a cursor
 // Transform the user query into two queries with words separated by '&' and '|'
 func (r *Repository) SearchPosts(
 	ctx context.Context,
 	searchTerm string,
+	filters models.SearchFilters,
 	limit int,
 	cursor string) (models.Posts, error) {
 
+	var zero, posts models.Posts
+
+	// Nothing to search for, e.g. only exclusions
+	query := parseSearchQuery(searchTerm)
+	if query.empty() {
+		return posts, nil
+	}
+
 	// Construct the SQL parts as well as the arguments
-	// The search term and limit are the first two arguments ($1 and $2)
+	// The search text and limit are the first two arguments ($1 and $2)
 	// Peek for one post beoynd the limit
 	var where string
 	total := "COUNT(*) OVER()"
-	args := []any{searchTerm, limit + 1}
+	args := []any{query.text(), limit + 1}
 
-	var zero, posts models.Posts
+	// The filters apply before the cursor, so the keyset
+	// pagination runs on the already filtered posts
+	filter, args := filterCondition(filters, query, args)
 
 	// Build args and SQL parts
 	// No cursor on the first page, no need for total and the WHERE clause
@@ -49,18 +87,33 @@ func (r *Repository) SearchPosts(
 			return zero, err
 		}
 
+		n := len(args)
 		args = append(args, score, cursorParts[1], cursorParts[2])
-		where = "WHERE (score, upload_date, id) < ($3, $4, $5)"
+		where = fmt.Sprintf(
+			"WHERE (score, upload_date, id) < ($%d, $%d, $%d)",
+			n+1, n+2, n+3,
+		)
 	}
 
-	sqlParts := struct{ TotalCount, WhereCondition string }{total, where}
-	query, err := r.GetQuery("search_posts.sql", sqlParts)
+	// The query expanded with the synonyms and the snippets options are the last arguments
+	tsQuery, args, err := r.appendTsQuery(ctx, query, args)
+	if err != nil {
+		return zero, err
+	}
+
+	args = append(args, headlineOptions)
+
+	sqlParts := struct {
+		matchParts
+		TotalCount, WhereCondition, HeadlineOptions string
+	}{matchParts{filter, tsQuery, "", false}, total, where, fmt.Sprintf("$%d", len(args))}
+	sqlQuery, err := r.GetQuery("search_posts.sql", sqlParts)
 	if err != nil {
 		return zero, err
 	}
 
 	// Get rows from DB
-	rows, err := r.db.Pool.Query(ctx, query, args...)
+	rows, err := r.db.Pool.Query(ctx, sqlQuery, args...)
 	if err != nil {
 		return zero, err
 	}
@@ -77,6 +130,7 @@ func (r *Repository) SearchPosts(
 			totalNum      int
 			avgRating     sql.NullFloat64
 			ratingCount   sql.NullInt64
+			snippet       sql.NullString
 		)
 
 		// Paste post from row to struct, thumbnails in a separate var
@@ -92,12 +146,14 @@ func (r *Repository) SearchPosts(
 			&totalNum,
 			&post.UploadDate,
 			&post.SearchScore,
+			&snippet,
 		); err != nil {
 			return zero, err
 		}
 
 		// Include the processed post in the result
 		post.OriginalTitle = utils.FromNullString(originalTitle)
+		post.Snippet = highlightSnippet(utils.FromNullString(snippet))
 
 		// Attach ratings if any
 		if avgRating.Valid && ratingCount.Valid {
@@ -141,3 +197,297 @@ func (r *Repository) SearchPosts(
 
 	return posts, nil
 }
+
+// Count the posts matching the user search query and filters per facet value
+func (r *Repository) SearchFacets(
+	ctx context.Context,
+	searchTerm string,
+	filters models.SearchFilters) (*models.SearchFacets, error) {
+
+	// Nothing to search for, e.g. only exclusions
+	query := parseSearchQuery(searchTerm)
+	if query.empty() {
+		return &models.SearchFacets{}, nil
+	}
+
+	// The search text is the first argument ($1)
+	filter, args := filterCondition(filters, query, []any{query.text()})
+
+	// The query expanded with the synonyms and the facet buckets are the last arguments
+	tsQuery, args, err := r.appendTsQuery(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	n := len(args)
+	var (
+		names, labels []string
+		mins, maxs    []int
+	)
+
+	for _, bucket := range models.DurationBuckets {
+		names = append(names, bucket.Name)
+		labels = append(labels, bucket.Label)
+		mins = append(mins, bucket.Min*60)
+		maxs = append(maxs, bucket.Max*60)
+	}
+
+	args = append(args, names, labels, mins, maxs, models.RatingThresholds)
+
+	sqlParts := struct {
+		matchParts
+		DurationBuckets, RatingThresholds string
+	}{
+		matchParts{filter, tsQuery, "", false},
+		fmt.Sprintf("$%d::text[], $%d::text[], $%d::int[], $%d::int[]", n+1, n+2, n+3, n+4),
+		fmt.Sprintf("$%d::int[]", n+5),
+	}
+
+	sqlQuery, err := r.GetQuery("search_facets.sql", sqlParts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get rows from DB
+	rows, err := r.db.Pool.Query(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Close rows on exit
+	defer rows.Close()
+
+	var facets models.SearchFacets
+	for rows.Next() {
+
+		var (
+			kind  string
+			facet models.Facet
+		)
+
+		if err = rows.Scan(&kind, &facet.Value, &facet.Label, &facet.Count); err != nil {
+			return nil, err
+		}
+
+		switch kind {
+		case "category":
+			facets.Categories = append(facets.Categories, facet)
+		case "source":
+			facets.Sources = append(facets.Sources, facet)
+		case "duration":
+			facets.Durations = append(facets.Durations, facet)
+		case "decade":
+			facets.Decades = append(facets.Decades, facet)
+		case "rating":
+			facets.Ratings = append(facets.Ratings, facet)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &facets, nil
+}
+
+// Get the video IDs out of the given ones matching
+// the user search query and filters, e.g. a saved search
+func (r *Repository) MatchPosts(
+	ctx context.Context,
+	searchTerm string,
+	filters models.SearchFilters,
+	videoIDs []string) ([]string, error) {
+
+	// Nothing to search for, e.g. only exclusions
+	query := parseSearchQuery(searchTerm)
+	if query.empty() || len(videoIDs) == 0 {
+		return nil, nil
+	}
+
+	// The search text is the first argument ($1)
+	filter, args := filterCondition(filters, query, []any{query.text()})
+
+	// The query expanded with the synonyms and the video IDs are the last arguments.
+	// Only the given posts are scored.
+	tsQuery, args, err := r.appendTsQuery(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	// A saved search notifies of every match, the posts must match all the words
+	args = append(args, videoIDs)
+	sqlParts := matchParts{filter, tsQuery, fmt.Sprintf("$%d", len(args)), true}
+
+	sqlQuery, err := r.GetQuery("match_posts.sql", sqlParts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get rows from DB
+	rows, err := r.db.Pool.Query(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Close rows on exit
+	defer rows.Close()
+
+	var matches []string
+	for rows.Next() {
+		var videoID string
+		if err = rows.Scan(&videoID); err != nil {
+			return nil, err
+		}
+		matches = append(matches, videoID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return matches, nil
+}
+
+// Append the search text expanded with the synonyms to the arguments
+// and return its placeholder. A query scoping only the fields,
+// e.g. category:history, has no text to rank the posts by,
+// the placeholder is empty then and all the posts are searched.
+func (r *Repository) appendTsQuery(
+	ctx context.Context,
+	query searchQuery,
+	args []any) (string, []any, error) {
+
+	if query.text() == "" {
+		return "", args, nil
+	}
+
+	tsQuery, err := r.expandQuery(ctx, query.text())
+	if err != nil {
+		return "", nil, err
+	}
+
+	args = append(args, tsQuery)
+	return fmt.Sprintf("$%d", len(args)), args, nil
+}
+
+// Build the WHERE condition narrowing down the search results
+// with the filters and the advanced search syntax of the query.
+// The placeholders continue after the arguments already in args.
+func filterCondition(
+	filters models.SearchFilters,
+	query searchQuery,
+	args []any) (string, []any) {
+
+	var conditions []string
+	add := func(condition string, values ...any) {
+		placeholders := make([]any, len(values))
+		for i, value := range values {
+			args = append(args, value)
+			placeholders[i] = len(args)
+		}
+		conditions = append(conditions, fmt.Sprintf(condition, placeholders...))
+	}
+
+	query.conditions(add)
+
+	if filters.Category != "" {
+		add("category_slug = $%d", filters.Category)
+	}
+
+	if filters.Source != "" {
+		add("playlist_id = $%d", filters.Source)
+	}
+
+	if bucket, ok := models.GetDurationBucket(filters.Duration); ok {
+		add("duration_seconds >= $%d", bucket.Min*60)
+		if bucket.Max > 0 {
+			add("duration_seconds < $%d", bucket.Max*60)
+		}
+	}
+
+	if filters.YearFrom > 0 {
+		add("upload_year >= $%d", filters.YearFrom)
+	}
+
+	if filters.YearTo > 0 {
+		add("upload_year <= $%d", filters.YearTo)
+	}
+
+	if filters.MinRating > 0 {
+		add("avg_rating >= $%d", filters.MinRating)
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// Escape the snippet text and mark the matched terms
+func highlightSnippet(snippet string) template.HTML {
+
+	snippet = strings.TrimSpace(snippet)
+	if snippet == "" {
+		return ""
+	}
+
+	// Drop unbalanced markers, e.g. if the text itself contained one
+	if strings.Count(snippet, markStart) != strings.Count(snippet, markStop) {
+		snippet = strings.NewReplacer(markStart, "", markStop, "").Replace(snippet)
+	}
+
+	return template.HTML(markReplacer.Replace(html.EscapeString(snippet)))
+}
+
+// SearchWithFallback searches the posts, on the first page includes the facet counts too.
+// If nothing found falls back to the spelling corrected query if any.
+func (r *Repository) SearchWithFallback(
+	ctx context.Context,
+	searchQuery string,
+	filters models.SearchFilters,
+	cursor string) (models.Posts, error) {
+
+	posts, err := r.SearchPosts(
+		ctx, searchQuery, filters, r.config.PostsPerPage, cursor,
+	)
+
+	if err != nil {
+		return posts, err
+	}
+
+	// The next pages of a corrected search come with the original query,
+	// they are empty too so they fall back to the same correction
+	if len(posts.Items) == 0 {
+
+		correction, err := r.CorrectQuery(ctx, searchQuery)
+		if err != nil {
+			return posts, err
+		}
+
+		if correction != "" {
+			corrected, err := r.SearchPosts(
+				ctx, correction, filters, r.config.PostsPerPage, cursor,
+			)
+
+			if err != nil {
+				return posts, err
+			}
+
+			// No point suggesting a correction which finds nothing too
+			if len(corrected.Items) > 0 {
+				corrected.Correction = correction
+				corrected.Corrected = true
+				posts = corrected
+				searchQuery = correction
+			}
+		}
+	}
+
+	if cursor != "" {
+		return posts, nil
+	}
+
+	posts.Facets, err = r.SearchFacets(ctx, searchQuery, filters)
+	return posts, err
+}
@@ -0,0 +1,80 @@
+package posts
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+	"testing"
+
+	"github.com/vlatan/video-store/internal/config"
+)
+
+func TestHighlightSnippet(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		snippet  string
+		expected template.HTML
+	}{
+		{"empty", "  ", ""},
+		{"no match", "The cold war", "The cold war"},
+		{"marked terms",
+			"The " + markStart + "cold" + markStop + " " + markStart + "war" + markStop,
+			"The <mark>cold</mark> <mark>war</mark>",
+		},
+		{"escaped html",
+			"<script>" + markStart + "war" + markStop + " & peace</script>",
+			"&lt;script&gt;<mark>war</mark> &amp; peace&lt;/script&gt;",
+		},
+		{"unbalanced markers", "The " + markStart + "cold war", "The cold war"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := highlightSnippet(tt.snippet); got != tt.expected {
+				t.Errorf("got %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSearchMatchesQuery(t *testing.T) {
+
+	r, err := New(nil, &config.Config{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		file     string
+		matchAll bool
+		expected string
+	}{
+		{"search any word", "search_posts.sql", false, "st.or_query"},
+		{"saved search all words", "match_posts.sql", true, "st.and_query"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			sqlParts := struct {
+				matchParts
+				TotalCount, WhereCondition, HeadlineOptions string
+			}{matchParts{"", "$2", "$3", tt.matchAll}, "", "", "$4"}
+
+			query, err := r.GetQuery(tt.file, sqlParts)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Both the posts and the reviews match the same way
+			for _, vector := range []string{"p.search_vector", "pr.search_vector"} {
+				match := fmt.Sprintf("WHERE %s @@ %s", vector, tt.expected)
+				if !strings.Contains(query, match) {
+					t.Errorf("the query doesn't contain %q", match)
+				}
+			}
+		})
+	}
+}
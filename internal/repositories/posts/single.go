@@ -111,6 +111,7 @@ func (r *Repository) GetSinglePost(ctx context.Context, videoID string) (models.
 		&categoryName,
 		&post.UploadDate,
 		&post.Duration,
+		&post.Locales,
 	)
 
 	if err != nil {
@@ -145,11 +146,8 @@ func (r *Repository) GetSinglePost(ctx context.Context, videoID string) (models.
 	post.Summary = utils.FromNullString(summary)
 
 	// Parse markdown to HTML
-	if post.HTMLSummary, err = utils.ParseMarkdown(post.Summary); err != nil {
-		return zero, fmt.Errorf(
-			"could not convert markdown to html on %q: %v",
-			post.VideoID, err,
-		)
+	if err = setHTMLSummary(&post); err != nil {
+		return zero, err
 	}
 
 	// Like button text
@@ -174,19 +172,36 @@ func (r *Repository) GetSinglePost(ctx context.Context, videoID string) (models.
 		return zero, fmt.Errorf("video ID %q: %w", videoID, err)
 	}
 
-	// Assign the biggest thumbnail to post
+	// Assign the biggest thumbnail to post, keep the rest for the embeds
+	post.Thumbnails = &thumbs
 	maxThumb := thumbs.MaxThumb()
 	post.Thumbnail = maxThumb
 
+	// Make srcset string
+	post.Srcset = thumbs.Srcset(maxThumb.Width)
+
+	return post, nil
+}
+
+// setHTMLSummary parses the post's summary markdown to HTML,
+// and sets the first sentence of the summary as meta description
+func setHTMLSummary(post *models.Post) error {
+
+	html, err := utils.ParseMarkdown(post.Summary)
+	if err != nil {
+		return fmt.Errorf(
+			"could not convert markdown to html on %q: %v",
+			post.VideoID, err,
+		)
+	}
+	post.HTMLSummary = html
+
 	// Get the first sentence of the summary to be used as meta description
 	post.MetaDescription = strings.Split(post.Summary, ".")[0]
 	replacer := strings.NewReplacer("<p>", "", "</p>", "")
 	post.MetaDescription = replacer.Replace(post.MetaDescription)
 
-	// Make srcset string
-	post.Srcset = thumbs.Srcset(maxThumb.Width)
-
-	return post, nil
+	return nil
 }
 
 func (r *Repository) UpdatePost(
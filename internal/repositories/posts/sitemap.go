@@ -2,11 +2,18 @@ package posts
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/vlatan/video-store/internal/models"
+	"github.com/vlatan/video-store/internal/utils"
 )
 
+// Google accepts videos up to 8 hours long
+const maxVideoDuration = 8 * time.Hour
+
 func (r *Repository) SitemapData(ctx context.Context, args ...any) ([]*models.SitemapItem, error) {
 
 	// Get query
@@ -29,9 +36,22 @@ func (r *Repository) SitemapData(ctx context.Context, args ...any) ([]*models.Si
 	for rows.Next() {
 		var item models.SitemapItem
 		var lastModified *time.Time
+		var video sitemapVideoRow
 
-		// Paste post from row to struct, thumbnails in a separate var
-		if err = rows.Scan(&item.Type, &item.BucketId, &item.Location, &lastModified); err != nil {
+		// Paste post from row to struct, video details in a separate var
+		if err = rows.Scan(
+			&item.Type,
+			&item.BucketId,
+			&item.Location,
+			&lastModified,
+			&video.videoID,
+			&video.title,
+			&video.summary,
+			&video.thumbnails,
+			&video.duration,
+			&video.uploadDate,
+			&video.category,
+		); err != nil {
 			return nil, err
 		}
 
@@ -39,6 +59,13 @@ func (r *Repository) SitemapData(ctx context.Context, args ...any) ([]*models.Si
 			item.LastModified = lastModified.Format("2006-01-02")
 		}
 
+		// Only the posts have video details
+		if video.videoID.Valid {
+			if item.Video, err = video.toSitemapVideo(); err != nil {
+				return nil, err
+			}
+		}
+
 		// Include the processed post in the result
 		data = append(data, &item)
 	}
@@ -50,3 +77,59 @@ func (r *Repository) SitemapData(ctx context.Context, args ...any) ([]*models.Si
 
 	return data, nil
 }
+
+// The video details of a sitemap row, all null on the non-post rows
+type sitemapVideoRow struct {
+	videoID    sql.NullString
+	title      sql.NullString
+	summary    sql.NullString
+	thumbnails []byte
+	duration   sql.NullString
+	uploadDate sql.NullTime
+	category   sql.NullString
+}
+
+// Convert the row to the sitemap video, nil if there's no thumbnail
+// because Google requires one
+func (v sitemapVideoRow) toSitemapVideo() (*models.SitemapVideo, error) {
+
+	var thumbs models.Thumbnails
+	if err := json.Unmarshal(v.thumbnails, &thumbs); err != nil {
+		return nil, fmt.Errorf("video ID %q: %w", v.videoID.String, err)
+	}
+
+	maxThumb := thumbs.MaxThumb()
+	if maxThumb == nil || maxThumb.Url == "" {
+		return nil, nil
+	}
+
+	description, err := utils.MarkdownToText(v.summary.String)
+	if err != nil {
+		return nil, fmt.Errorf("video ID %q: %w", v.videoID.String, err)
+	}
+
+	// The description is required too
+	if description == "" {
+		description = v.title.String
+	}
+
+	video := &models.SitemapVideo{
+		ThumbnailLoc: maxThumb.Url,
+		Title:        v.title.String,
+		Description:  description,
+		PlayerLoc:    "https://www.youtube.com/embed/" + v.videoID.String,
+		Category:     v.category.String,
+	}
+
+	// Leave out the unknown or the too long durations
+	duration, err := models.ISO8601Duration(v.duration.String).Seconds()
+	if err == nil && duration > 0 && duration <= maxVideoDuration {
+		video.Duration = int(duration.Seconds())
+	}
+
+	if v.uploadDate.Valid {
+		video.PublicationDate = v.uploadDate.Time.Format(time.RFC3339)
+	}
+
+	return video, nil
+}
@@ -0,0 +1,78 @@
+package posts
+
+import (
+	"context"
+	"regexp"
+	"strings"
+)
+
+// Word which may be corrected, the lexicon holds only such words
+var correctableWord = regexp.MustCompile(`^\p{L}{3,}$`)
+
+// Correct the misspelled words in the search query using the lexicon of
+// the indexed words. Returns an empty string if there's nothing to correct.
+func (r *Repository) CorrectQuery(ctx context.Context, searchTerm string) (string, error) {
+
+	tokens := strings.Fields(strings.ToLower(searchTerm))
+
+	// Collect the words to check and their positions in the query
+	var (
+		words     []string
+		positions []int
+	)
+
+	for i, token := range tokens {
+		if correctableWord.MatchString(token) {
+			words = append(words, token)
+			positions = append(positions, i)
+		}
+	}
+
+	if len(words) == 0 {
+		return "", nil
+	}
+
+	query, err := r.GetQuery("correct_words.sql", nil)
+	if err != nil {
+		return "", err
+	}
+
+	rows, err := r.db.Pool.Query(ctx, query, words)
+	if err != nil {
+		return "", err
+	}
+
+	// Close rows on exit
+	defer rows.Close()
+
+	var corrected bool
+	for i := 0; rows.Next(); i++ {
+
+		var word string
+		if err = rows.Scan(&word); err != nil {
+			return "", err
+		}
+
+		if i < len(positions) && word != tokens[positions[i]] {
+			tokens[positions[i]] = word
+			corrected = true
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return "", err
+	}
+
+	if !corrected {
+		return "", nil
+	}
+
+	return strings.Join(tokens, " "), nil
+}
+
+// Refresh the lexicon of the indexed words with the current posts
+func (r *Repository) RefreshLexicon(ctx context.Context) error {
+	const query = "REFRESH MATERIALIZED VIEW CONCURRENTLY search_lexicon;"
+	_, err := r.db.Pool.Exec(ctx, query)
+	return err
+}
@@ -0,0 +1,18 @@
+-- Replace each unknown word with the most similar word in the lexicon.
+-- Known words and words without a similar match stay as they are.
+SELECT
+    COALESCE(
+        (
+            SELECT l.word FROM search_lexicon AS l
+            WHERE l.word = w.word
+        ),
+        (
+            SELECT l.word FROM search_lexicon AS l
+            WHERE l.word % w.word
+            ORDER BY similarity(l.word, w.word) DESC, l.ndoc DESC
+            LIMIT 1
+        ),
+        w.word
+    ) AS corrected
+FROM unnest($1::text[]) WITH ORDINALITY AS w (word, pos)
+ORDER BY w.pos;
@@ -0,0 +1,10 @@
+SELECT
+    post.video_id,
+    post.summary,
+    category.slug,
+    category.name,
+    post.created_at,
+    GREATEST(post.created_at, post.updated_at) AS updated_at
+FROM post
+LEFT JOIN category ON category.id = post.category_id
+WHERE post.video_id = ANY($1);
@@ -0,0 +1,6 @@
+INSERT INTO flagged_post (video_id, issues, response)
+VALUES ($1, $2, $3)
+ON CONFLICT (video_id) DO UPDATE
+SET
+    issues = EXCLUDED.issues,
+    response = EXCLUDED.response;
@@ -0,0 +1,10 @@
+SELECT
+    fp.video_id,
+    post.title,
+    post.original_title,
+    fp.issues,
+    fp.response,
+    fp.created_at
+FROM flagged_post AS fp
+JOIN post ON post.video_id = fp.video_id
+ORDER BY fp.created_at DESC, fp.id DESC;
@@ -0,0 +1,4 @@
+{{ template "search_matches.sql" . }}
+-- Only the given posts were scored, e.g. the newly inserted ones
+SELECT video_id
+FROM filtered_posts;
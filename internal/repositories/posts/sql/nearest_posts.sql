@@ -0,0 +1,20 @@
+-- The post's nearest neighbours by the cosine distance of the embeddings,
+-- ordered by the same expression as the HNSW index, so the index serves it.
+-- The subqueries run once. No neighbours if the post has no embedding for the model.
+SELECT pe.post_id
+FROM post_embedding AS pe
+WHERE pe.model = $2
+AND pe.post_id <> (SELECT id FROM post WHERE video_id = $1)
+AND EXISTS (
+    SELECT 1
+    FROM post_embedding AS e
+    JOIN post ON post.id = e.post_id
+    WHERE post.video_id = $1 AND e.model = $2
+)
+ORDER BY pe.embedding::vector(768) <=> (
+    SELECT e.embedding::vector(768)
+    FROM post_embedding AS e
+    JOIN post ON post.id = e.post_id
+    WHERE post.video_id = $1 AND e.model = $2
+)
+LIMIT $3;
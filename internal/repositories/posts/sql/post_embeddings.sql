@@ -0,0 +1,4 @@
+SELECT pe.post_id, post.video_id, pe.embedding
+FROM post_embedding AS pe
+JOIN post ON post.id = pe.post_id
+WHERE pe.model = $1;
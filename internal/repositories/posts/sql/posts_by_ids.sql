@@ -0,0 +1,28 @@
+WITH likes AS (
+    SELECT post_id, COUNT(*) AS likes
+    FROM post_like
+    WHERE post_id = ANY($1)
+    GROUP BY post_id
+),
+ratings AS (
+    SELECT
+        post_id,
+        ROUND(AVG(rating), 2)::float8 AS avg_rating,
+        COUNT(rating) AS rating_count
+    FROM post_rating
+    WHERE post_id = ANY($1)
+    GROUP BY post_id
+)
+SELECT
+    video_id,
+    title,
+    original_title,
+    thumbnails,
+    COALESCE(l.likes, 0) AS likes,
+    r.avg_rating,
+    COALESCE(r.rating_count, 0) AS rating_count
+FROM post
+LEFT JOIN likes AS l ON l.post_id = post.id
+LEFT JOIN ratings AS r ON r.post_id = post.id
+WHERE post.id = ANY($1)
+ORDER BY array_position($1, post.id);
@@ -0,0 +1,13 @@
+SELECT
+    post.id,
+    post.video_id,
+    post.title,
+    post.original_title,
+    post.summary
+FROM post
+LEFT JOIN post_embedding AS pe ON pe.post_id = post.id
+WHERE pe.post_id IS NULL
+    OR pe.model != $1
+    OR pe.updated_at < post.updated_at
+ORDER BY post.upload_date DESC, post.id DESC
+LIMIT $2;
@@ -0,0 +1,13 @@
+SELECT
+    post.id,
+    post.video_id,
+    post.title,
+    post.original_title,
+    post.summary
+FROM post
+LEFT JOIN post_translation AS pt
+    ON pt.post_id = post.id AND pt.locale = $1
+WHERE post.summary IS NOT NULL AND post.summary != ''
+    AND (pt.id IS NULL OR pt.updated_at < post.updated_at)
+ORDER BY post.upload_date DESC, post.id DESC
+LIMIT $2;
@@ -0,0 +1,10 @@
+-- Rebuild the search vectors with the current text search configuration,
+-- without touching the updated_at timestamps
+ALTER TABLE post DISABLE TRIGGER post_timestamp_update;
+ALTER TABLE post_review DISABLE TRIGGER post_review_timestamp_update;
+
+UPDATE post SET search_vector = post_search_vector(post);
+UPDATE post_review SET search_vector = post_review_search_vector(post_review);
+
+ALTER TABLE post ENABLE TRIGGER post_timestamp_update;
+ALTER TABLE post_review ENABLE TRIGGER post_review_timestamp_update;
@@ -0,0 +1,38 @@
+{{ template "search_matches.sql" . }}
+-- Count the filtered posts per facet value
+SELECT 'category' AS facet, category_slug AS value, category_name AS label, COUNT(*) AS posts_count
+FROM filtered_posts
+WHERE category_slug IS NOT NULL
+GROUP BY category_slug, category_name
+
+UNION ALL
+
+SELECT 'source', playlist_id, playlist_title, COUNT(*)
+FROM filtered_posts
+WHERE playlist_id IS NOT NULL AND playlist_id <> ''
+GROUP BY playlist_id, playlist_title
+
+UNION ALL
+
+SELECT 'duration', b.name, b.label, COUNT(*)
+FROM filtered_posts AS fp
+JOIN unnest({{ .DurationBuckets }}) AS b (name, label, min_seconds, max_seconds)
+ON fp.duration_seconds >= b.min_seconds
+AND (b.max_seconds = 0 OR fp.duration_seconds < b.max_seconds)
+GROUP BY b.name, b.label
+
+UNION ALL
+
+SELECT 'decade', (upload_year / 10 * 10)::text, (upload_year / 10 * 10)::text || 's', COUNT(*)
+FROM filtered_posts
+GROUP BY upload_year / 10 * 10
+
+UNION ALL
+
+SELECT 'rating', t.threshold::text, t.threshold::text || '+', COUNT(*)
+FROM filtered_posts AS fp
+JOIN unnest({{ .RatingThresholds }}) AS t (threshold)
+ON fp.avg_rating >= t.threshold
+GROUP BY t.threshold
+
+ORDER BY facet, posts_count DESC, value;
@@ -0,0 +1,118 @@
+WITH
+{{- if .TsQuery }}
+    -- Construct AND, OR and RAW queries from the input search phrase.
+    -- The AND query comes expanded with the synonyms, the RAW query without them.
+    search_terms AS (
+        SELECT
+            lexeme AS and_query,
+            to_tsquery(search_regconfig(), replace(lexeme::text, ' & ', ' | ')) AS or_query,
+            replace(plainto_tsquery(search_regconfig(), $1)::text, ' & ', ' ') AS raw_query
+        FROM to_tsquery(search_regconfig(), {{ .TsQuery }}) AS lexeme
+    ),
+    -- Isolated GIN scan #1 - match posts
+    post_matches AS (
+        SELECT 
+            p.id,
+            (ts_rank(p.search_vector, st.and_query, 32) * 2) + 
+            ts_rank(p.search_vector, st.or_query, 32) +
+            (similarity(p.title, st.raw_query) * 0.25) + 
+            (COALESCE(similarity(p.original_title, st.raw_query), 0) * 0.25) AS post_score
+        FROM post AS p
+        CROSS JOIN search_terms AS st
+        -- If st.or_query is matched no need to look for st.and_query match,
+        -- unless all the words must match
+        WHERE p.search_vector @@ {{ if .MatchAll }}st.and_query{{ else }}st.or_query{{ end }}
+        {{- with .VideoIDs }}
+        AND p.video_id = ANY({{ . }}) -- only the given posts if any
+        {{- end }}
+    ),
+    -- Isolated GIN scan #2 - match reviews and calculate scores
+    review_matches AS (
+        SELECT
+            pr.post_id,
+            (MAX(ts_rank(pr.search_vector, st.and_query, 32)) * 1.5) +
+            (MAX(ts_rank(pr.search_vector, st.or_query, 32)) * 0.75) +
+            (MAX(COALESCE(similarity(pr.title, st.raw_query), 0) * 0.25)) AS review_score
+        FROM post_review AS pr
+        CROSS JOIN search_terms AS st
+        WHERE pr.search_vector @@ {{ if .MatchAll }}st.and_query{{ else }}st.or_query{{ end }}
+        {{- with .VideoIDs }}
+        AND pr.post_id IN (SELECT id FROM post WHERE video_id = ANY({{ . }}))
+        {{- end }}
+        GROUP BY pr.post_id
+    ),
+    -- Merge the IDs and save the total score
+    combined_matches AS (
+        SELECT 
+            COALESCE(pm.id, rm.post_id) AS post_id,
+            COALESCE(pm.post_score, 0) + COALESCE(rm.review_score, 0) AS total_score
+        FROM post_matches AS pm
+        FULL OUTER JOIN review_matches AS rm ON pm.id = rm.post_id
+    ),
+{{- else }}
+    -- Only the fields are searched, e.g. category:history,
+    -- there are no words to match and rank the posts by
+    search_terms AS (
+        SELECT
+            NULL::tsquery AS and_query,
+            NULL::tsquery AS or_query,
+            $1::text AS raw_query
+    ),
+    -- All the posts, the field conditions narrow them down
+    combined_matches AS (
+        SELECT p.id AS post_id, 0::real AS total_score
+        FROM post AS p
+        {{- with .VideoIDs }}
+        WHERE p.video_id = ANY({{ . }}) -- only the given posts if any
+        {{- end }}
+    ),
+{{- end }}
+    likes AS (
+        SELECT post_id, COUNT(*) AS likes
+        FROM post_like
+        GROUP BY post_id
+    ),
+    ratings AS (
+        SELECT
+            post_id,
+            ROUND(AVG(rating), 2)::float8 AS avg_rating,
+            COUNT(rating) AS rating_count
+        FROM post_rating
+        GROUP BY post_id
+    ),
+    -- Get the data we need
+    scored_posts AS (
+        SELECT
+            p.id,
+            p.video_id,
+            p.title,
+            p.original_title,
+            p.thumbnails,
+            COALESCE(l.likes, 0) AS likes,
+            r.avg_rating,
+            COALESCE(r.rating_count, 0) AS rating_count,
+            p.upload_date,
+            cm.total_score AS score,
+            p.search_vector,
+            c.slug AS category_slug,
+            c.name AS category_name,
+            pl.playlist_id,
+            pl.title AS playlist_title,
+            EXTRACT(YEAR FROM p.upload_date)::int AS upload_year,
+            -- Durations are stored in ISO 8601 format, e.g. PT1H2M3S
+            CASE
+                WHEN p.duration LIKE 'P%'
+                THEN EXTRACT(EPOCH FROM p.duration::interval)::int
+            END AS duration_seconds
+        FROM combined_matches AS cm
+        JOIN post AS p ON p.id = cm.post_id
+        LEFT JOIN category AS c ON c.id = p.category_id
+        LEFT JOIN playlist AS pl ON pl.id = p.playlist_db_id
+        LEFT JOIN likes AS l ON l.post_id = p.id
+        LEFT JOIN ratings AS r ON r.post_id = p.id
+    ),
+    -- Narrow down the posts with the user filters
+    filtered_posts AS (
+        SELECT * FROM scored_posts
+        {{ .FilterCondition }} -- the filters WHERE condition if any
+    )
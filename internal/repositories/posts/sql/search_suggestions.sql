@@ -0,0 +1,51 @@
+-- Suggest posts, categories and sources while the user types the search phrase
+-- The trigram operator % and the ILIKE prefix both use the GIN trigram indexes
+WITH
+    post_suggestions AS (
+        SELECT
+            'post' AS kind,
+            COALESCE(original_title, title) AS label,
+            '/video/' || video_id || '/' AS url,
+            GREATEST(
+                similarity(title, $1),
+                COALESCE(similarity(original_title, $1), 0)
+            ) AS score
+        FROM post
+        WHERE
+            title % $1 OR original_title % $1 OR
+            title ILIKE $2 OR original_title ILIKE $2
+        ORDER BY score DESC, upload_date DESC
+        LIMIT $3
+    ),
+    category_suggestions AS (
+        SELECT
+            'category' AS kind,
+            name AS label,
+            '/category/' || slug || '/' AS url,
+            similarity(name, $1) AS score
+        FROM category
+        WHERE name % $1 OR name ILIKE $2
+        ORDER BY score DESC
+        LIMIT $4
+    ),
+    source_suggestions AS (
+        SELECT
+            'source' AS kind,
+            title AS label,
+            '/source/' || playlist_id || '/' AS url,
+            similarity(title, $1) AS score
+        FROM playlist
+        WHERE title % $1 OR title ILIKE $2
+        ORDER BY score DESC
+        LIMIT $4
+    )
+SELECT kind, label, url FROM (
+    SELECT * FROM category_suggestions
+    UNION ALL
+    SELECT * FROM source_suggestions
+    UNION ALL
+    SELECT * FROM post_suggestions
+) AS suggestions
+ORDER BY
+    CASE kind WHEN 'category' THEN 1 WHEN 'source' THEN 2 ELSE 3 END,
+    score DESC;
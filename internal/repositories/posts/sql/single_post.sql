@@ -15,7 +15,12 @@ SELECT
     category.slug,
     category.name,
     post.upload_date,
-    post.duration
+    post.duration,
+    ARRAY(
+        SELECT locale FROM post_translation
+        WHERE post_translation.post_id = post.id
+        ORDER BY locale
+    ) AS locales
 FROM post
 LEFT JOIN LATERAL (
     SELECT COUNT(*) AS likes
@@ -1,10 +1,38 @@
-    -- Posts (last modified = last updated_at)
+    -- Posts (last modified = last updated_at) with their video details
 	SELECT
 		$2 as part_type,
-		(id % $1) AS bucket_id,
-		CONCAT('/video/', video_id, '/') AS item_location,
-		updated_at AS last_modified
+		(post.id % $1) AS bucket_id,
+		CONCAT('/video/', post.video_id, '/') AS item_location,
+		post.updated_at AS last_modified,
+		post.video_id,
+		COALESCE(post.original_title, post.title) AS video_title,
+		LEFT(post.summary, 2048) AS video_summary,
+		post.thumbnails,
+		post.duration,
+		post.upload_date,
+		c.name AS category_name
 	FROM post
+	LEFT JOIN category AS c ON c.id = post.category_id
+
+	UNION ALL
+
+	-- Localized posts (last modified = last updated_at)
+	SELECT
+		$2 as part_type,
+		(post.id % $1) AS bucket_id,
+		CONCAT('/', pt.locale, '/video/', post.video_id, '/') AS item_location,
+		pt.updated_at AS last_modified,
+		post.video_id,
+		COALESCE(pt.title, post.original_title, post.title) AS video_title,
+		LEFT(pt.summary, 2048) AS video_summary,
+		post.thumbnails,
+		post.duration,
+		post.upload_date,
+		c.name AS category_name
+	FROM post_translation AS pt
+	INNER JOIN post ON post.id = pt.post_id
+	LEFT JOIN category AS c ON c.id = post.category_id
+	WHERE pt.locale = ANY($4::text[])
 
 	UNION ALL
 
@@ -13,7 +41,8 @@
 		$3 AS part_type,
 		0 AS bucket_id,
 		CONCAT('/page/', slug, '/') AS item_location,
-		updated_at AS last_modified
+		updated_at AS last_modified,
+		NULL, NULL, NULL, NULL, NULL, NULL, NULL
 	FROM page
 
 	UNION ALL
@@ -23,7 +52,8 @@
 		$3 AS part_type,
 		0 AS bucket_id,
 		CONCAT('/source/', p.playlist_id, '/') AS item_location, 
-		MAX(post.upload_date) AS last_modified
+		MAX(post.upload_date) AS last_modified,
+		NULL, NULL, NULL, NULL, NULL, NULL, NULL
 	FROM playlist AS p
 	INNER JOIN post ON post.playlist_db_id = p.id
 	GROUP BY p.id
@@ -35,7 +65,8 @@
 		$3 AS part_type,
 		0 AS bucket_id,
 		'/source/other/' AS item_location,
-		MAX(upload_date) AS last_modified
+		MAX(upload_date) AS last_modified,
+		NULL, NULL, NULL, NULL, NULL, NULL, NULL
 	FROM post
 	WHERE playlist_id IS NULL OR playlist_id = ''
 	HAVING COUNT(*) > 0
@@ -47,7 +78,8 @@
 		$3 AS part_type,
 		0 AS bucket_id,
 		CONCAT('/category/', c.slug, '/') AS item_location,
-		MAX(post.upload_date) AS last_modified
+		MAX(post.upload_date) AS last_modified,
+		NULL, NULL, NULL, NULL, NULL, NULL, NULL
 	FROM category AS c
 	INNER JOIN post ON post.category_id = c.id
 	GROUP BY c.id
@@ -59,7 +91,8 @@
 		$3 AS part_type,
 		0 AS bucket_id,
 		'/' AS item_location,
-		MAX(upload_date) AS last_modified
+		MAX(upload_date) AS last_modified,
+		NULL, NULL, NULL, NULL, NULL, NULL, NULL
 	FROM post
 
 	UNION ALL
@@ -69,7 +102,8 @@
 		$3 AS part_type,
 		0 AS bucket_id,
 		'/sources/' AS item_location, 
-		MAX(created_at) AS last_modified
+		MAX(created_at) AS last_modified,
+		NULL, NULL, NULL, NULL, NULL, NULL, NULL
 	FROM playlist
 
 	ORDER BY part_type, item_location;
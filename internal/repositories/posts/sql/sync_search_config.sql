@@ -0,0 +1,4 @@
+-- Switch the text search configuration only if it's different
+UPDATE search_setting
+SET regconfig = $1::regconfig, updated_at = NOW()
+WHERE regconfig <> $1::regconfig;
@@ -0,0 +1,3 @@
+SELECT terms
+FROM search_synonym
+ORDER BY id;
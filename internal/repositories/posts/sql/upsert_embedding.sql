@@ -0,0 +1,7 @@
+INSERT INTO post_embedding (post_id, model, embedding)
+VALUES ($1, $2, $3)
+ON CONFLICT (post_id) DO UPDATE
+SET
+    model = EXCLUDED.model,
+    embedding = EXCLUDED.embedding,
+    updated_at = CURRENT_TIMESTAMP;
@@ -0,0 +1,7 @@
+INSERT INTO post_translation (post_id, locale, title, summary)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (post_id, locale) DO UPDATE
+SET
+    title = EXCLUDED.title,
+    summary = EXCLUDED.summary,
+    updated_at = CURRENT_TIMESTAMP;
@@ -0,0 +1,2 @@
+-- Whether the pgvector HNSW index on the embeddings exists
+SELECT to_regclass('idx_post_embedding_hnsw') IS NOT NULL;
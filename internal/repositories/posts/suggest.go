@@ -0,0 +1,53 @@
+package posts
+
+import (
+	"context"
+	"strings"
+
+	"github.com/vlatan/video-store/internal/models"
+)
+
+// Escape the LIKE wildcards in user input
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// Get the search suggestions matching the typed search phrase.
+// Posts are limited to postsLimit, categories and sources to taxLimit each.
+func (r *Repository) SearchSuggestions(
+	ctx context.Context,
+	searchTerm string,
+	postsLimit int,
+	taxLimit int) (models.Suggestions, error) {
+
+	query, err := r.GetQuery("search_suggestions.sql", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Match the titles which start with the search term too
+	prefix := likeEscaper.Replace(searchTerm) + "%"
+
+	rows, err := r.db.Pool.Query(ctx, query, searchTerm, prefix, postsLimit, taxLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	// Close rows on exit
+	defer rows.Close()
+
+	// Not nil so it's encoded as an empty JSON array
+	suggestions := models.Suggestions{}
+	for rows.Next() {
+		var s models.Suggestion
+		if err = rows.Scan(&s.Kind, &s.Label, &s.URL); err != nil {
+			return nil, err
+		}
+
+		suggestions = append(suggestions, s)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return suggestions, nil
+}
@@ -0,0 +1,224 @@
+package posts
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// Reload the synonyms from DB at most this often,
+// the admin changes on other instances show up this late
+const synonymsTTL = time.Minute
+
+// Escape the single quotes and backslashes in a tsquery lexeme
+var lexemeEscaper = strings.NewReplacer(`\`, `\\`, `'`, `''`)
+
+// Synonyms loaded from DB
+type synonyms struct {
+	mu        sync.RWMutex
+	thesaurus *thesaurus
+	loadedAt  time.Time
+}
+
+// Thesaurus expanding the search terms with their synonyms
+type thesaurus struct {
+	groups   [][]string     // groups of equivalent normalized terms
+	terms    map[string]int // normalized term to its group index
+	maxWords int            // words in the longest term
+}
+
+// newThesaurus creates a thesaurus out of the synonym groups.
+// A term in more than one group belongs to the first one.
+func newThesaurus(groups [][]string) *thesaurus {
+
+	t := &thesaurus{terms: make(map[string]int)}
+
+	for _, group := range groups {
+
+		var terms []string
+		for _, term := range group {
+			words := slices.DeleteFunc(normalizeWords(term), func(w string) bool {
+				return w == ""
+			})
+
+			if len(words) == 0 {
+				continue
+			}
+
+			key := strings.Join(words, " ")
+			if _, ok := t.terms[key]; ok {
+				continue
+			}
+
+			t.terms[key] = len(t.groups)
+			t.maxWords = max(t.maxWords, len(words))
+			terms = append(terms, key)
+		}
+
+		// Nothing to expand into
+		if len(terms) < 2 {
+			for _, term := range terms {
+				delete(t.terms, term)
+			}
+			continue
+		}
+
+		t.groups = append(t.groups, terms)
+	}
+
+	return t
+}
+
+// tsQuery builds the to_tsquery input out of the search text.
+// All the words must match, a word or a phrase having synonyms
+// matches any of them. The longest phrase with synonyms wins.
+func (t *thesaurus) tsQuery(text string) string {
+
+	fields := strings.Fields(text)
+	words := normalizeWords(text)
+
+	var parts []string
+	for i := 0; i < len(fields); {
+
+		// Only punctuation, nothing to search for
+		if words[i] == "" {
+			i++
+			continue
+		}
+
+		n, group := t.match(words[i:])
+		if group < 0 {
+			parts = append(parts, quoteLexeme(fields[i]))
+			i++
+			continue
+		}
+
+		alternatives := make([]string, len(t.groups[group]))
+		for j, term := range t.groups[group] {
+			alternatives[j] = quoteLexeme(term)
+		}
+
+		parts = append(parts, "("+strings.Join(alternatives, " | ")+")")
+		i += n
+	}
+
+	return strings.Join(parts, " & ")
+}
+
+// Find the longest term with synonyms at the start of the words.
+// Returns the number of matched words and the group index, -1 if none.
+func (t *thesaurus) match(words []string) (int, int) {
+
+	if t == nil {
+		return 0, -1
+	}
+
+	for n := min(t.maxWords, len(words)); n > 0; n-- {
+		if group, ok := t.terms[strings.Join(words[:n], " ")]; ok {
+			return n, group
+		}
+	}
+
+	return 0, -1
+}
+
+// Lowercase the words and strip their surrounding punctuation,
+// one normalized word per whitespace separated field
+func normalizeWords(text string) []string {
+	fields := strings.Fields(strings.ToLower(text))
+	for i, field := range fields {
+		fields[i] = strings.TrimFunc(field, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+	}
+	return fields
+}
+
+// Quote a lexeme for to_tsquery, the words of a quoted phrase
+// get normalized by the text search configuration and must follow each other
+func quoteLexeme(lexeme string) string {
+	return "'" + lexemeEscaper.Replace(lexeme) + "'"
+}
+
+// Expand the search text with the synonyms into the to_tsquery input
+func (r *Repository) expandQuery(ctx context.Context, text string) (string, error) {
+
+	t, err := r.getThesaurus(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return t.tsQuery(text), nil
+}
+
+// Get the thesaurus, reload the synonyms from DB if stale
+func (r *Repository) getThesaurus(ctx context.Context) (*thesaurus, error) {
+
+	r.synonyms.mu.RLock()
+	t, loadedAt := r.synonyms.thesaurus, r.synonyms.loadedAt
+	r.synonyms.mu.RUnlock()
+
+	if t != nil && time.Since(loadedAt) < synonymsTTL {
+		return t, nil
+	}
+
+	groups, err := r.getSynonymGroups(ctx)
+	if err != nil {
+
+		// Keep searching with the stale synonyms rather than fail
+		if t != nil {
+			slog.ErrorContext(ctx, "failed to reload the search synonyms", "error", err)
+			return t, nil
+		}
+
+		return nil, err
+	}
+
+	t = newThesaurus(groups)
+
+	r.synonyms.mu.Lock()
+	r.synonyms.thesaurus = t
+	r.synonyms.loadedAt = time.Now()
+	r.synonyms.mu.Unlock()
+
+	return t, nil
+}
+
+// ResetSynonyms makes the next search reload the synonyms from DB
+func (r *Repository) ResetSynonyms() {
+	r.synonyms.mu.Lock()
+	r.synonyms.thesaurus = nil
+	r.synonyms.mu.Unlock()
+}
+
+// Get the terms of the synonym groups
+func (r *Repository) getSynonymGroups(ctx context.Context) ([][]string, error) {
+
+	query, err := r.GetQuery("synonyms.sql", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Pool.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// Close rows on exit
+	defer rows.Close()
+
+	var groups [][]string
+	for rows.Next() {
+		var terms []string
+		if err = rows.Scan(&terms); err != nil {
+			return nil, err
+		}
+		groups = append(groups, terms)
+	}
+
+	return groups, rows.Err()
+}
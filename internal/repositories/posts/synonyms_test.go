@@ -0,0 +1,48 @@
+package posts
+
+import "testing"
+
+func TestThesaurusTsQuery(t *testing.T) {
+
+	th := newThesaurus([][]string{
+		{"WWII", "World War II", "Second World War"},
+		{"USSR", "Soviet Union"},
+		{"lonely"},               // nothing to expand into
+		{"ussr", "Russia", "  "}, // ussr already belongs to a group
+	})
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"no synonyms", "cold war", "'cold' & 'war'"},
+		{"single word", "wwii", "('wwii' | 'world war ii' | 'second world war')"},
+		{"phrase", "world war ii tanks",
+			"('wwii' | 'world war ii' | 'second world war') & 'tanks'",
+		},
+		{"case and punctuation", "Spies of the USSR!",
+			"'Spies' & 'of' & 'the' & ('ussr' | 'soviet union')",
+		},
+		{"partial phrase", "world war", "'world' & 'war'"},
+		{"group without alternatives", "lonely russia", "'lonely' & 'russia'"},
+		{"only punctuation", "war - peace", "'war' & 'peace'"},
+		{"quotes escaped", `o'neill c:\`, `'o''neill' & 'c:\\'`},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := th.tsQuery(tt.input); got != tt.expected {
+				t.Errorf("got %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNilThesaurusTsQuery(t *testing.T) {
+	var th *thesaurus
+	if got, want := th.tsQuery("cold war"), "'cold' & 'war'"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,111 @@
+package posts
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/vlatan/video-store/internal/models"
+	"github.com/vlatan/video-store/internal/utils"
+)
+
+// Get posts with missing or stale translations for the given locale
+func (r *Repository) GetPostsToTranslate(ctx context.Context, locale string, limit int) ([]*models.Post, error) {
+
+	query, err := r.GetQuery("posts_to_translate.sql", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get rows from DB
+	rows, err := r.db.Pool.Query(ctx, query, locale, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	// Close rows on exit
+	defer rows.Close()
+
+	// Iterate over the rows
+	var posts []*models.Post
+	for rows.Next() {
+		var post models.Post
+		var originalTitle sql.NullString
+
+		if err = rows.Scan(
+			&post.ID,
+			&post.VideoID,
+			&post.Title,
+			&originalTitle,
+			&post.Summary,
+		); err != nil {
+			return nil, err
+		}
+
+		post.OriginalTitle = utils.FromNullString(originalTitle)
+		posts = append(posts, &post)
+	}
+
+	// If error during iteration
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return posts, nil
+}
+
+// Insert or update post's translation
+func (r *Repository) UpsertTranslation(
+	ctx context.Context,
+	postID int,
+	locale string,
+	translation *models.GenaiTranslation,
+) (int64, error) {
+
+	query, err := r.GetQuery("upsert_translation.sql", nil)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := r.db.Pool.Exec(
+		ctx,
+		query,
+		postID,
+		locale,
+		utils.ToNullString(translation.Title),
+		translation.Summary,
+	)
+
+	return result.RowsAffected(), err
+}
+
+// Get single post from DB with its title and summary in the given locale
+func (r *Repository) GetLocalizedPost(ctx context.Context, videoID, locale string) (models.Post, error) {
+
+	var zero models.Post
+	post, err := r.GetSinglePost(ctx, videoID)
+	if err != nil {
+		return zero, err
+	}
+
+	const query = `
+		SELECT title, summary FROM post_translation
+		WHERE post_id = $1 AND locale = $2
+	`
+
+	var title sql.NullString
+	if err = r.db.Pool.QueryRow(ctx, query, post.ID, locale).Scan(&title, &post.Summary); err != nil {
+		return zero, err
+	}
+
+	// Keep the original title if there's no translated one
+	if t := utils.FromNullString(title); t != "" {
+		post.OriginalTitle = t
+	}
+
+	if err = setHTMLSummary(&post); err != nil {
+		return zero, err
+	}
+
+	post.Locale = locale
+	return post, nil
+}
@@ -3,7 +3,7 @@ package posts
 import (
 	"context"
 	"encoding/json"
-	"log"
+	"log/slog"
 	"runtime"
 
 	"github.com/vlatan/video-store/internal/models"
@@ -36,9 +36,10 @@ func postProcessPosts(ctx context.Context, posts models.Posts) error {
 					return nil
 				}
 
-				log.Printf( // Just log the non-breaking error
-					"couldn't unmarshal the thumbs for post %s; %v",
-					post.VideoID, err,
+				slog.ErrorContext( // Just log the non-breaking error
+					ctx, "failed to unmarshal the post thumbnails",
+					"video", post.VideoID,
+					"error", err,
 				)
 
 				// Set empty Thumbnail so the HTML templates don't break
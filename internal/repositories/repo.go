@@ -3,12 +3,17 @@ package repo
 import (
 	"bytes"
 	"text/template"
+
+	"github.com/vlatan/video-store/internal/tracing"
 )
 
 // GetQuery executes named template SQL query from the tmpl object
 func GetQuery(tmpl *template.Template, name string, sqlParts any) (string, error) {
 	var buf bytes.Buffer
 
+	// Name the query, so the traces tell the queries apart
+	buf.WriteString(tracing.QueryNamePrefix + name + "\n")
+
 	// Execute the specific SQL query by its filename
 	err := tmpl.ExecuteTemplate(&buf, name, sqlParts)
 	if err != nil {
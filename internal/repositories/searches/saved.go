@@ -0,0 +1,268 @@
+package searches
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/vlatan/video-store/internal/models"
+	"github.com/vlatan/video-store/internal/utils"
+)
+
+// Save a search for the user, saving it again updates the email preference
+func (r *Repository) SaveSearch(ctx context.Context, search models.SavedSearch) (int64, error) {
+
+	sqlQuery, err := r.GetQuery("save_search.sql", nil)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := r.db.Pool.Exec(
+		ctx,
+		sqlQuery,
+		search.UserID,
+		search.Query,
+		search.Filters.Key(),
+		search.EmailDigest,
+	)
+
+	return result.RowsAffected(), err
+}
+
+// Delete a saved search of the user along with its notifications
+func (r *Repository) DeleteSavedSearch(ctx context.Context, userID, id int) (int64, error) {
+
+	sqlQuery, err := r.GetQuery("delete_saved_search.sql", nil)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := r.db.Pool.Exec(ctx, sqlQuery, id, userID)
+	return result.RowsAffected(), err
+}
+
+// Get the user's saved searches with their number of unread notifications
+func (r *Repository) GetUserSavedSearches(ctx context.Context, userID int) ([]models.SavedSearch, error) {
+	return r.getSavedSearches(ctx, "user_saved_searches.sql", userID)
+}
+
+// Get all the saved searches of all the users
+func (r *Repository) GetAllSavedSearches(ctx context.Context) ([]models.SavedSearch, error) {
+	return r.getSavedSearches(ctx, "all_saved_searches.sql")
+}
+
+// Notify the saved search on the new matching posts
+func (r *Repository) InsertNotifications(ctx context.Context, savedSearchID int, videoIDs []string) (int64, error) {
+
+	sqlQuery, err := r.GetQuery("insert_notifications.sql", nil)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := r.db.Pool.Exec(ctx, sqlQuery, savedSearchID, videoIDs)
+	return result.RowsAffected(), err
+}
+
+// Get the latest notifications of the user
+func (r *Repository) GetUserNotifications(
+	ctx context.Context,
+	userID int,
+	limit int) ([]models.SearchNotification, error) {
+
+	sqlQuery, err := r.GetQuery("user_notifications.sql", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Pool.Query(ctx, sqlQuery, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	// Close rows on exit
+	defer rows.Close()
+
+	var notifications []models.SearchNotification
+	for rows.Next() {
+
+		var (
+			n       models.SearchNotification
+			filters string
+		)
+
+		if err = rows.Scan(
+			&n.ID,
+			&n.SavedSearch.ID,
+			&n.SavedSearch.Query,
+			&filters,
+			&n.VideoID,
+			&n.Title,
+			&n.Read,
+			&n.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		n.SavedSearch.UserID = userID
+		n.SavedSearch.ParseFilters(filters)
+		notifications = append(notifications, n)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+// Count the user's unread notifications
+func (r *Repository) CountUnreadNotifications(ctx context.Context, userID int) (int, error) {
+
+	sqlQuery, err := r.GetQuery("unread_notifications.sql", nil)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	err = r.db.Pool.QueryRow(ctx, sqlQuery, userID).Scan(&count)
+	return count, err
+}
+
+// Mark all the user's notifications as read
+func (r *Repository) ReadNotifications(ctx context.Context, userID int) (int64, error) {
+
+	sqlQuery, err := r.GetQuery("read_notifications.sql", nil)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := r.db.Pool.Exec(ctx, sqlQuery, userID)
+	return result.RowsAffected(), err
+}
+
+// Get the unread notifications not emailed yet, grouped per user
+func (r *Repository) GetPendingDigests(ctx context.Context) ([]models.SearchDigest, error) {
+
+	sqlQuery, err := r.GetQuery("pending_digests.sql", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Pool.Query(ctx, sqlQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	// Close rows on exit
+	defer rows.Close()
+
+	var digests []models.SearchDigest
+	for rows.Next() {
+
+		var (
+			userID  int
+			name    sql.NullString
+			email   string
+			n       models.SearchNotification
+			filters string
+		)
+
+		if err = rows.Scan(
+			&userID,
+			&name,
+			&email,
+			&n.ID,
+			&n.SavedSearch.ID,
+			&n.SavedSearch.Query,
+			&filters,
+			&n.VideoID,
+			&n.Title,
+			&n.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		n.SavedSearch.UserID = userID
+		n.SavedSearch.EmailDigest = true
+		n.SavedSearch.ParseFilters(filters)
+
+		// The rows are ordered by user
+		if len(digests) == 0 || digests[len(digests)-1].UserID != userID {
+			digests = append(digests, models.SearchDigest{
+				UserID: userID,
+				Name:   utils.FromNullString(name),
+				Email:  email,
+			})
+		}
+
+		last := &digests[len(digests)-1]
+		last.Notifications = append(last.Notifications, n)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return digests, nil
+}
+
+// Mark the notifications as emailed
+func (r *Repository) MarkEmailed(ctx context.Context, ids []int) (int64, error) {
+
+	sqlQuery, err := r.GetQuery("mark_emailed.sql", nil)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := r.db.Pool.Exec(ctx, sqlQuery, ids)
+	return result.RowsAffected(), err
+}
+
+// Get the saved searches with the given query
+func (r *Repository) getSavedSearches(
+	ctx context.Context,
+	name string,
+	args ...any) ([]models.SavedSearch, error) {
+
+	sqlQuery, err := r.GetQuery(name, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Pool.Query(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Close rows on exit
+	defer rows.Close()
+
+	var searches []models.SavedSearch
+	for rows.Next() {
+
+		var (
+			search  models.SavedSearch
+			filters string
+		)
+
+		if err = rows.Scan(
+			&search.ID,
+			&search.UserID,
+			&search.Query,
+			&filters,
+			&search.EmailDigest,
+			&search.Unread,
+			&search.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		search.ParseFilters(filters)
+		searches = append(searches, search)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return searches, nil
+}
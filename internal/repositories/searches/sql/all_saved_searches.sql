@@ -0,0 +1,10 @@
+SELECT
+    id,
+    user_id,
+    query,
+    filters,
+    email_digest,
+    0 AS unread,
+    created_at
+FROM saved_search
+ORDER BY id;
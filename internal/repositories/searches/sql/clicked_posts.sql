@@ -0,0 +1,13 @@
+SELECT
+    p.video_id,
+    COALESCE(p.original_title, p.title) AS title,
+    pl.title AS source_title,
+    SUM(sc.clicks)::int AS clicks,
+    COUNT(DISTINCT sc.query)::int AS queries
+FROM search_click AS sc
+JOIN post AS p ON p.id = sc.post_id
+LEFT JOIN playlist AS pl ON pl.id = p.playlist_db_id
+WHERE sc.day > CURRENT_DATE - $1::int
+GROUP BY p.id, pl.title
+ORDER BY clicks DESC, p.video_id
+LIMIT $2;
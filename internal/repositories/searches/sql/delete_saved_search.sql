@@ -0,0 +1,2 @@
+DELETE FROM saved_search
+WHERE id = $1 AND user_id = $2;
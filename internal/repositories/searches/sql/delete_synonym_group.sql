@@ -0,0 +1,2 @@
+DELETE FROM search_synonym
+WHERE id = $1;
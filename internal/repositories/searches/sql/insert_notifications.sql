@@ -0,0 +1,6 @@
+-- Notify only once per post, e.g. if the worker is restarted
+INSERT INTO search_notification (saved_search_id, post_id)
+SELECT $1, p.id
+FROM post AS p
+WHERE p.video_id = ANY($2)
+ON CONFLICT (saved_search_id, post_id) DO NOTHING;
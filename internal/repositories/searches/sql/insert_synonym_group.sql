@@ -0,0 +1,2 @@
+INSERT INTO search_synonym (terms)
+VALUES ($1);
@@ -0,0 +1,3 @@
+UPDATE search_notification
+SET emailed_at = NOW()
+WHERE id = ANY($1);
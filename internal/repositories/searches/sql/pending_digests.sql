@@ -0,0 +1,22 @@
+-- The notifications not yet emailed nor seen on the site
+SELECT
+    u.id,
+    u.name,
+    u.email,
+    n.id,
+    s.id,
+    s.query,
+    s.filters,
+    p.video_id,
+    p.title,
+    n.created_at
+FROM search_notification AS n
+JOIN saved_search AS s ON s.id = n.saved_search_id
+JOIN app_user AS u ON u.id = s.user_id
+JOIN post AS p ON p.id = n.post_id
+WHERE s.email_digest
+AND n.emailed_at IS NULL
+AND n.read_at IS NULL
+AND u.email IS NOT NULL
+AND u.email <> ''
+ORDER BY u.id, s.id, n.id;
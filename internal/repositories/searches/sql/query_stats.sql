@@ -0,0 +1,19 @@
+WITH stats AS (
+    SELECT
+        query,
+        SUM(searches)::int AS searches,
+        SUM(zero_results)::int AS zero_results,
+        -- The latest results, of the plain search if any that day
+        (ARRAY_AGG(results ORDER BY day DESC, filters = '' DESC))[1] AS results,
+        COALESCE(
+            ROUND(SUM(latency_ms)::numeric / NULLIF(SUM(searches), 0), 1), 0
+        )::float8 AS avg_latency,
+        SUM(clicks)::int AS clicks
+    FROM search_stat
+    WHERE day > CURRENT_DATE - $1::int
+    GROUP BY query
+)
+SELECT * FROM stats
+{{ .WhereCondition }} -- the WHERE condition if any
+ORDER BY searches DESC, query
+LIMIT $2;
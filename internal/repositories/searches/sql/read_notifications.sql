@@ -0,0 +1,6 @@
+UPDATE search_notification AS n
+SET read_at = NOW()
+FROM saved_search AS s
+WHERE s.id = n.saved_search_id
+AND s.user_id = $1
+AND n.read_at IS NULL;
@@ -0,0 +1,22 @@
+WITH
+    -- Count the click on the post only if the query was searched today
+    -- with the same filters
+    clicked AS (
+        INSERT INTO search_click (query, post_id, clicks)
+        SELECT $1, p.id, 1
+        FROM post AS p
+        WHERE p.video_id = $2
+        AND EXISTS (
+            SELECT 1 FROM search_stat
+            WHERE query = $1 AND filters = $3 AND day = CURRENT_DATE
+        )
+        ON CONFLICT (query, day, post_id) DO UPDATE
+        SET clicks = search_click.clicks + 1
+        RETURNING query
+    )
+UPDATE search_stat
+SET clicks = search_stat.clicks + 1
+FROM clicked
+WHERE search_stat.query = clicked.query
+AND search_stat.filters = $3
+AND search_stat.day = CURRENT_DATE;
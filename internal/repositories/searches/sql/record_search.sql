@@ -0,0 +1,8 @@
+INSERT INTO search_stat (query, filters, searches, zero_results, results, latency_ms)
+VALUES ($1, $2, 1, CASE WHEN $3::int = 0 THEN 1 ELSE 0 END, $3, $4)
+ON CONFLICT (query, filters, day) DO UPDATE
+SET
+    searches = search_stat.searches + 1,
+    zero_results = search_stat.zero_results + EXCLUDED.zero_results,
+    results = EXCLUDED.results,
+    latency_ms = search_stat.latency_ms + EXCLUDED.latency_ms;
@@ -0,0 +1,5 @@
+-- Saving the same search again only updates the email preference
+INSERT INTO saved_search (user_id, query, filters, email_digest)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id, query, filters) DO UPDATE
+SET email_digest = EXCLUDED.email_digest;
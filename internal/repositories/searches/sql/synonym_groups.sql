@@ -0,0 +1,3 @@
+SELECT id, terms, created_at
+FROM search_synonym
+ORDER BY id DESC;
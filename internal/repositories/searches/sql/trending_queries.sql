@@ -0,0 +1,20 @@
+-- Compare the searches in the last $1 days with the $1 days before
+WITH windows AS (
+    SELECT
+        query,
+        COALESCE(SUM(searches) FILTER (WHERE day > CURRENT_DATE - $1::int), 0)::int AS recent,
+        COALESCE(SUM(searches) FILTER (WHERE day <= CURRENT_DATE - $1::int), 0)::int AS previous
+    FROM search_stat
+    WHERE day > CURRENT_DATE - 2 * $1::int
+    GROUP BY query
+)
+SELECT
+    query,
+    recent,
+    previous,
+    -- Smoothed growth so the new queries don't divide by zero
+    ((recent + 1)::float8 / (previous + 1)) AS trend
+FROM windows
+WHERE recent >= $3 AND recent > previous
+ORDER BY trend DESC, recent DESC, query
+LIMIT $2;
@@ -0,0 +1,5 @@
+SELECT COUNT(*)
+FROM search_notification AS n
+JOIN saved_search AS s ON s.id = n.saved_search_id
+WHERE s.user_id = $1
+AND n.read_at IS NULL;
@@ -0,0 +1,15 @@
+SELECT
+    n.id,
+    s.id,
+    s.query,
+    s.filters,
+    p.video_id,
+    p.title,
+    n.read_at IS NOT NULL AS read,
+    n.created_at
+FROM search_notification AS n
+JOIN saved_search AS s ON s.id = n.saved_search_id
+JOIN post AS p ON p.id = n.post_id
+WHERE s.user_id = $1
+ORDER BY n.created_at DESC, n.id DESC
+LIMIT $2;
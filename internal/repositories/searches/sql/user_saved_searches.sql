@@ -0,0 +1,13 @@
+SELECT
+    s.id,
+    s.user_id,
+    s.query,
+    s.filters,
+    s.email_digest,
+    COUNT(n.id) FILTER (WHERE n.read_at IS NULL) AS unread,
+    s.created_at
+FROM saved_search AS s
+LEFT JOIN search_notification AS n ON n.saved_search_id = s.id
+WHERE s.user_id = $1
+GROUP BY s.id
+ORDER BY s.created_at DESC, s.id DESC;
@@ -0,0 +1,184 @@
+package searches
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/vlatan/video-store/internal/models"
+	"github.com/vlatan/video-store/internal/utils"
+)
+
+// Record a search on a normalized query and its filters key,
+// empty if none, with its number of results and latency
+func (r *Repository) RecordSearch(
+	ctx context.Context,
+	query string,
+	filters string,
+	results int,
+	latency time.Duration) (int64, error) {
+
+	sqlQuery, err := r.GetQuery("record_search.sql", nil)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := r.db.Pool.Exec(ctx, sqlQuery, query, filters, results, latency.Milliseconds())
+	return result.RowsAffected(), err
+}
+
+// Record a click on a post from the search results
+// of a normalized query and its filters key
+func (r *Repository) RecordClick(ctx context.Context, query, filters, videoID string) (int64, error) {
+
+	sqlQuery, err := r.GetQuery("record_click.sql", nil)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := r.db.Pool.Exec(ctx, sqlQuery, query, videoID, filters)
+	return result.RowsAffected(), err
+}
+
+// Get the most searched queries in the last days
+func (r *Repository) GetTopQueries(ctx context.Context, days, limit int) ([]models.SearchStat, error) {
+	return r.getQueryStats(ctx, days, limit, "")
+}
+
+// Get the most searched queries in the last days which found nothing
+func (r *Repository) GetZeroResultQueries(ctx context.Context, days, limit int) ([]models.SearchStat, error) {
+	return r.getQueryStats(ctx, days, limit, "WHERE results = 0")
+}
+
+// Get the queries with the highest growth in the last days
+// compared to the same number of days before that
+func (r *Repository) GetTrendingQueries(
+	ctx context.Context,
+	days int,
+	limit int,
+	minSearches int) ([]models.SearchStat, error) {
+
+	sqlQuery, err := r.GetQuery("trending_queries.sql", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Pool.Query(ctx, sqlQuery, days, limit, minSearches)
+	if err != nil {
+		return nil, err
+	}
+
+	// Close rows on exit
+	defer rows.Close()
+
+	var stats []models.SearchStat
+	for rows.Next() {
+		var stat models.SearchStat
+		if err = rows.Scan(
+			&stat.Query,
+			&stat.Searches,
+			&stat.Previous,
+			&stat.Trend,
+		); err != nil {
+			return nil, err
+		}
+
+		stats = append(stats, stat)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// Get the most clicked posts from the search results in the last days
+func (r *Repository) GetClickedPosts(ctx context.Context, days, limit int) ([]models.SearchClick, error) {
+
+	sqlQuery, err := r.GetQuery("clicked_posts.sql", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Pool.Query(ctx, sqlQuery, days, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	// Close rows on exit
+	defer rows.Close()
+
+	var clicks []models.SearchClick
+	for rows.Next() {
+
+		var (
+			click       models.SearchClick
+			sourceTitle sql.NullString
+		)
+
+		if err = rows.Scan(
+			&click.VideoID,
+			&click.Title,
+			&sourceTitle,
+			&click.Clicks,
+			&click.Queries,
+		); err != nil {
+			return nil, err
+		}
+
+		click.SourceTitle = utils.FromNullString(sourceTitle)
+		clicks = append(clicks, click)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return clicks, nil
+}
+
+// Get the aggregated stats on the queries searched in the last days
+func (r *Repository) getQueryStats(
+	ctx context.Context,
+	days int,
+	limit int,
+	where string) ([]models.SearchStat, error) {
+
+	sqlParts := struct{ WhereCondition string }{where}
+	sqlQuery, err := r.GetQuery("query_stats.sql", sqlParts)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Pool.Query(ctx, sqlQuery, days, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	// Close rows on exit
+	defer rows.Close()
+
+	var stats []models.SearchStat
+	for rows.Next() {
+		var stat models.SearchStat
+		if err = rows.Scan(
+			&stat.Query,
+			&stat.Searches,
+			&stat.ZeroResults,
+			&stat.Results,
+			&stat.AvgLatency,
+			&stat.Clicks,
+		); err != nil {
+			return nil, err
+		}
+
+		stats = append(stats, stat)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,63 @@
+package searches
+
+import (
+	"context"
+
+	"github.com/vlatan/video-store/internal/models"
+)
+
+// Get all the synonym groups, the latest first
+func (r *Repository) GetSynonymGroups(ctx context.Context) ([]models.SynonymGroup, error) {
+
+	sqlQuery, err := r.GetQuery("synonym_groups.sql", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Pool.Query(ctx, sqlQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	// Close rows on exit
+	defer rows.Close()
+
+	var groups []models.SynonymGroup
+	for rows.Next() {
+		var group models.SynonymGroup
+		if err = rows.Scan(&group.ID, &group.Terms, &group.CreatedAt); err != nil {
+			return nil, err
+		}
+		groups = append(groups, group)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+// Insert a group of equivalent search terms
+func (r *Repository) InsertSynonymGroup(ctx context.Context, terms []string) (int64, error) {
+
+	sqlQuery, err := r.GetQuery("insert_synonym_group.sql", nil)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := r.db.Pool.Exec(ctx, sqlQuery, terms)
+	return result.RowsAffected(), err
+}
+
+// Delete a synonym group
+func (r *Repository) DeleteSynonymGroup(ctx context.Context, id int) (int64, error) {
+
+	sqlQuery, err := r.GetQuery("delete_synonym_group.sql", nil)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := r.db.Pool.Exec(ctx, sqlQuery, id)
+	return result.RowsAffected(), err
+}
@@ -0,0 +1,2 @@
+DELETE FROM api_token
+WHERE id = $1 AND user_id = $2;
@@ -0,0 +1,2 @@
+INSERT INTO api_token (user_id, name, token_hash, prefix, scopes, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6);
@@ -0,0 +1,27 @@
+-- Authenticate with the token and record its use,
+-- at most once a minute so the requests don't write every time
+WITH token AS (
+    SELECT t.id, t.scopes, t.user_id, t.last_used_at
+    FROM api_token AS t
+    WHERE t.token_hash = $1
+    AND (t.expires_at IS NULL OR t.expires_at > NOW())
+),
+used AS (
+    UPDATE api_token AS t
+    SET last_used_at = NOW()
+    FROM token
+    WHERE t.id = token.id
+    AND (token.last_used_at IS NULL OR token.last_used_at < NOW() - INTERVAL '1 minute')
+)
+SELECT
+    token.id,
+    token.scopes,
+    u.id,
+    u.provider_user_id,
+    u.provider,
+    u.analytics_id,
+    u.name,
+    u.email,
+    u.picture
+FROM token
+JOIN app_user AS u ON u.id = token.user_id;
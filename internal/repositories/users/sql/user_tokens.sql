@@ -0,0 +1,11 @@
+SELECT
+    id,
+    name,
+    prefix,
+    scopes,
+    expires_at,
+    last_used_at,
+    created_at
+FROM api_token
+WHERE user_id = $1
+ORDER BY created_at DESC, id DESC;
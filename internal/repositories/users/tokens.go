@@ -0,0 +1,118 @@
+package users
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/vlatan/video-store/internal/models"
+	"github.com/vlatan/video-store/internal/utils"
+)
+
+// Insert a new API token of the user, only its hash gets stored
+func (r *Repository) InsertToken(ctx context.Context, token models.APIToken, hash []byte) (int64, error) {
+
+	query, err := r.GetQuery("insert_token.sql", nil)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := r.db.Pool.Exec(
+		ctx,
+		query,
+		token.UserID,
+		token.Name,
+		hash,
+		token.Prefix,
+		token.Scopes,
+		token.ExpiresAt,
+	)
+
+	return result.RowsAffected(), err
+}
+
+// Get the user's API tokens, the newest first
+func (r *Repository) GetUserTokens(ctx context.Context, userID int) ([]models.APIToken, error) {
+
+	query, err := r.GetQuery("user_tokens.sql", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Close rows on exit
+	defer rows.Close()
+
+	var tokens []models.APIToken
+	for rows.Next() {
+
+		token := models.APIToken{UserID: userID}
+		if err = rows.Scan(
+			&token.ID,
+			&token.Name,
+			&token.Prefix,
+			&token.Scopes,
+			&token.ExpiresAt,
+			&token.LastUsedAt,
+			&token.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		tokens = append(tokens, token)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// Revoke an API token of the user
+func (r *Repository) DeleteToken(ctx context.Context, userID, id int) (int64, error) {
+
+	query, err := r.GetQuery("delete_token.sql", nil)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := r.db.Pool.Exec(ctx, query, id, userID)
+	return result.RowsAffected(), err
+}
+
+// Get the user owning the valid API token and record the token use.
+// Returns pgx.ErrNoRows if the token is unknown or expired.
+func (r *Repository) GetTokenUser(ctx context.Context, hash []byte) (models.User, error) {
+
+	var zero, user models.User
+	query, err := r.GetQuery("token_user.sql", nil)
+	if err != nil {
+		return zero, err
+	}
+
+	var name, email, avatarURL, analyticsID sql.NullString
+	if err = r.db.Pool.QueryRow(ctx, query, hash).Scan(
+		&user.TokenID,
+		&user.Scopes,
+		&user.ID,
+		&user.ProviderUserId,
+		&user.Provider,
+		&analyticsID,
+		&name,
+		&email,
+		&avatarURL,
+	); err != nil {
+		return zero, err
+	}
+
+	user.Name = utils.FromNullString(name)
+	user.Email = utils.FromNullString(email)
+	user.AvatarURL = utils.FromNullString(avatarURL)
+	user.AnalyticsID = utils.FromNullString(analyticsID)
+
+	return user, nil
+}
@@ -0,0 +1,40 @@
+package tracing
+
+import (
+	"context"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// LogHandler adds the trace and span IDs of the context to the records,
+// so the log lines of a request can be found next to its trace
+type LogHandler struct {
+	slog.Handler
+}
+
+// NewLogHandler wraps the handler
+func NewLogHandler(h slog.Handler) *LogHandler {
+	return &LogHandler{h}
+}
+
+// Handle adds the IDs if the context carries a recorded span
+func (h *LogHandler) Handle(ctx context.Context, record slog.Record) error {
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		record.AddAttrs(
+			slog.String("traceId", sc.TraceID().String()),
+			slog.String("spanId", sc.SpanID().String()),
+		)
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+// WithAttrs keeps the wrapper around the new handler
+func (h *LogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &LogHandler{h.Handler.WithAttrs(attrs)}
+}
+
+// WithGroup keeps the wrapper around the new handler
+func (h *LogHandler) WithGroup(name string) slog.Handler {
+	return &LogHandler{h.Handler.WithGroup(name)}
+}
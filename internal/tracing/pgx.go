@@ -0,0 +1,63 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// The comment the repositories prefix their queries with, naming the SQL file
+const QueryNamePrefix = "-- query: "
+
+// QueryTracer is the pgx hook starting a span for every query
+type QueryTracer struct{}
+
+// TraceQueryStart starts the query span
+func (QueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	ctx, _ = tracer.Start(ctx, queryName(data.SQL),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemNamePostgreSQL,
+			semconv.DBQueryText(data.SQL),
+		),
+	)
+	return ctx
+}
+
+// TraceQueryEnd ends the query span, no rows is not an error
+func (QueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	span := trace.SpanFromContext(ctx)
+	defer span.End()
+
+	if data.Err != nil && !errors.Is(data.Err, pgx.ErrNoRows) {
+		span.RecordError(data.Err)
+		span.SetStatus(codes.Error, data.Err.Error())
+		return
+	}
+
+	span.SetAttributes(semconv.DBResponseReturnedRows(int(data.CommandTag.RowsAffected())))
+}
+
+// The span name, the SQL file of the query if named,
+// otherwise the leading keyword, i.e. "SELECT"
+func queryName(sql string) string {
+
+	sql = strings.TrimSpace(sql)
+	if name, ok := strings.CutPrefix(sql, QueryNamePrefix); ok {
+		name, _, _ = strings.Cut(name, "\n")
+		return "db " + strings.TrimSpace(name)
+	}
+
+	keyword, _, _ := strings.Cut(sql, " ")
+	keyword, _, _ = strings.Cut(keyword, "\n")
+	if keyword == "" {
+		return "db"
+	}
+
+	return "db " + strings.ToUpper(keyword)
+}
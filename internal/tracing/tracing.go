@@ -0,0 +1,112 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/vlatan/video-store/internal/config"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// The instrumentation scope of the app's own spans
+const scope = "github.com/vlatan/video-store"
+
+// The global tracer, a no-op until Setup installs an exporting provider
+var tracer = otel.Tracer(scope)
+
+// Setup installs the global tracer provider exporting the spans over OTLP/HTTP.
+// Without an endpoint the no-op default provider stays and nothing is recorded.
+// The exporter reads the rest of the standard OTEL_EXPORTER_OTLP_* variables itself.
+// The returned function flushes the pending spans on exit.
+func Setup(ctx context.Context, cfg *config.Config) (func(context.Context) error, error) {
+
+	// Continue the traces of the incoming requests, and pass them on to the outgoing
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	if cfg.OtelEndpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+		resource.WithAttributes(
+			semconv.ServiceName(cfg.OtelServiceName),
+			attribute.String("app.target", string(cfg.Target)),
+		),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(
+			sdktrace.TraceIDRatioBased(cfg.OtelSampleRatio),
+		)),
+	)
+
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Start a span of the app's own work
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// Handler starts the server span of every request,
+// continuing the trace of the caller if any
+func Handler(next http.Handler, skip func(*http.Request) bool) http.Handler {
+	return otelhttp.NewHandler(next, "http.server",
+		otelhttp.WithFilter(func(r *http.Request) bool { return !skip(r) }),
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+			return r.Method
+		}),
+	)
+}
+
+// Client wraps the transport so the outgoing requests get client spans.
+// The nil transport is the http.DefaultTransport.
+func Client(transport http.RoundTripper) *http.Client {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &http.Client{Transport: otelhttp.NewTransport(transport)}
+}
+
+// SetRoute names the server span after the route pattern the mux matched.
+// The span has only the method in its name until the mux has seen the request.
+func SetRoute(ctx context.Context, method, pattern string) {
+
+	if pattern == "" {
+		return
+	}
+
+	route := pattern
+	if _, path, ok := strings.Cut(pattern, " "); ok {
+		route = path
+	}
+
+	span := trace.SpanFromContext(ctx)
+	span.SetName(method + " " + route)
+	span.SetAttributes(semconv.HTTPRoute(route))
+}
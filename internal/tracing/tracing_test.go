@@ -0,0 +1,134 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/vlatan/video-store/internal/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestQueryName(t *testing.T) {
+
+	tests := []struct {
+		name string
+		sql  string
+		want string
+	}{
+		{"named", QueryNamePrefix + "single_post.sql\nSELECT 1", "db single_post.sql"},
+		{"keyword", "select * FROM post", "db SELECT"},
+		{"keyword on its own line", "\n  UPDATE\npost SET", "db UPDATE"},
+		{"empty", "", "db"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := queryName(tt.sql); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogHandler(t *testing.T) {
+
+	spanCtx := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1},
+		SpanID:     trace.SpanID{2},
+		TraceFlags: trace.FlagsSampled,
+	})
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		wantTrace string
+	}{
+		{"no span", context.Background(), ""},
+		{"span", trace.ContextWithSpanContext(context.Background(), spanCtx), spanCtx.TraceID().String()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			var buf bytes.Buffer
+			logger := slog.New(NewLogHandler(slog.NewJSONHandler(&buf, nil))).With("key", "value")
+			logger.InfoContext(tt.ctx, "message")
+
+			var record map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+				t.Fatal(err)
+			}
+
+			if got, _ := record["traceId"].(string); got != tt.wantTrace {
+				t.Errorf("got trace ID %q, want %q", got, tt.wantTrace)
+			}
+
+			if record["key"] != "value" {
+				t.Error("the logger lost its attributes")
+			}
+		})
+	}
+}
+
+func TestSetupWithoutEndpoint(t *testing.T) {
+
+	shutdown, err := Setup(context.Background(), &config.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = shutdown(context.Background()); err != nil {
+		t.Errorf("got shutdown error %v", err)
+	}
+}
+
+func TestQueryTracer(t *testing.T) {
+
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus codes.Code
+	}{
+		{"success", nil, codes.Unset},
+		{"no rows", pgx.ErrNoRows, codes.Unset},
+		{"failure", errors.New("boom"), codes.Error},
+	}
+
+	var qt QueryTracer
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			ctx := qt.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{
+				SQL: QueryNamePrefix + "post.sql\nSELECT 1",
+			})
+			qt.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{
+				CommandTag: pgconn.NewCommandTag("SELECT 1"),
+				Err:        tt.err,
+			})
+
+			spans := recorder.Ended()
+			span := spans[len(spans)-1]
+
+			if span.Name() != "db post.sql" {
+				t.Errorf("got span name %q, want %q", span.Name(), "db post.sql")
+			}
+
+			if span.Status().Code != tt.wantStatus {
+				t.Errorf("got status %v, want %v", span.Status().Code, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -1,7 +1,6 @@
 package ui
 
 import (
-	"log"
 	"log/slog"
 	"net/http"
 	"time"
@@ -18,12 +17,12 @@ func (s *service) StoreFlashMessage(
 ) {
 	session, err := s.store.Get(r, s.config.FlashSessionName)
 	if err != nil {
-		log.Println("Unable to get the flash session", err)
+		slog.ErrorContext(r.Context(), "failed to get the flash session", "error", err)
 	}
 
 	session.AddFlash(m)
 	if err = session.Save(r, w); err != nil {
-		log.Println("Unable to save the flash session", err)
+		slog.ErrorContext(r.Context(), "failed to save the flash session", "error", err)
 	}
 }
 
@@ -47,7 +46,7 @@ func (s *service) GetUserFromSession(w http.ResponseWriter, r *http.Request) *mo
 		// Clear the session this is anonymous user
 		session.Options.MaxAge = -1
 		if err = session.Save(r, w); err != nil {
-			log.Printf("couldn't clear the session for anonymous user; %v", err)
+			slog.ErrorContext(r.Context(), "failed to clear the session of the anonymous user", "error", err)
 		}
 		return nil
 	}
@@ -74,7 +73,7 @@ func (s *service) GetUserFromSession(w http.ResponseWriter, r *http.Request) *mo
 
 	// Save the session
 	if err = session.Save(r, w); err != nil {
-		log.Printf("couldn't save session for updating user last seen; %v", err)
+		slog.ErrorContext(r.Context(), "failed to save the session updating the user last seen", "error", err)
 	}
 
 	providerUserId, _ := session.Values["ProviderUserId"].(string)
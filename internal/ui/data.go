@@ -1,8 +1,11 @@
 package ui
 
 import (
-	"log"
+	"context"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"time"
 
 	"github.com/vlatan/video-store/internal/drivers/rdb"
 	"github.com/vlatan/video-store/internal/models"
@@ -11,6 +14,10 @@ import (
 	"github.com/gorilla/csrf"
 )
 
+// The worker adds the notifications without touching the cache,
+// so they show up at most this late
+const notificationsCacheTimeout = 5 * time.Minute
+
 // NewData creates new default data struct to be passed to the templates
 // Instead of manualy envoking this function in each route it can be envoked in a middleware
 // and passed donwstream as value to the request context.
@@ -22,9 +29,13 @@ func (s *service) NewData(w http.ResponseWriter, r *http.Request) *models.Templa
 		s.rdb,
 		"categories",
 		s.config.CacheTimeout,
-		func() (models.Categories, error) {
-			return s.catsRepo.GetCategories(r.Context())
+		func(ctx context.Context) (models.Categories, error) {
+			return s.catsRepo.GetCategories(ctx)
 		},
+		rdb.WithTags(rdb.CategoriesTag),
+		rdb.WithSingleflight(),
+		rdb.WithLock(),
+		rdb.WithSoftTTL(s.config.CacheSoftTimeout),
 	)
 
 	// Construct the data
@@ -37,11 +48,24 @@ func (s *service) NewData(w http.ResponseWriter, r *http.Request) *models.Templa
 		CSRFField:    csrf.TemplateField(r),
 	}
 
-	// Check if the path needs flash messages
+	// Check if the path needs flash messages and notifications
 	if utils.IsFilePath(r.URL.Path) {
 		return data
 	}
 
+	// Get the number of the user's unread notifications from cache
+	if user := models.GetUserFromContext(r); user.IsAuthenticated() {
+		data.UnreadNotifications, _ = rdb.GetCachedData(
+			r.Context(),
+			s.rdb,
+			fmt.Sprintf(models.UnreadNotificationsKey, user.ID),
+			notificationsCacheTimeout,
+			func(ctx context.Context) (int, error) {
+				return s.searchesRepo.CountUnreadNotifications(ctx, user.ID)
+			},
+		)
+	}
+
 	// Check for flash cookie
 	if _, err := r.Cookie(s.config.FlashSessionName); err != nil {
 		return data
@@ -61,7 +85,7 @@ func (s *service) NewData(w http.ResponseWriter, r *http.Request) *models.Templa
 	// Clear the flash session created with s.store.Get
 	session.Options.MaxAge = -1
 	if err := session.Save(r, w); err != nil {
-		log.Printf("unable to clear/save the flash session; %v", err)
+		slog.ErrorContext(r.Context(), "failed to clear the flash session", "error", err)
 	}
 
 	// Put flash messages to data
@@ -41,6 +41,9 @@ func (s *service) ExecuteErrorTemplate(w io.Writer, status int, data *models.Tem
 	case http.StatusMethodNotAllowed:
 		data.HTMLErrorData.Heading = fmt.Sprintf("Method not allowed (%d)", http.StatusMethodNotAllowed)
 		data.HTMLErrorData.Text = "Use the appropriate method and try again."
+	case http.StatusTooManyRequests:
+		data.HTMLErrorData.Heading = fmt.Sprintf("Too many requests (%d)", http.StatusTooManyRequests)
+		data.HTMLErrorData.Text = "Please slow down and try again in a moment."
 	case http.StatusInternalServerError:
 		data.HTMLErrorData.Heading = fmt.Sprintf("Something went wrong (%d)", http.StatusInternalServerError)
 		data.HTMLErrorData.Text = "Sorry about that. We're working on fixing this."
@@ -0,0 +1,20 @@
+package ui
+
+import (
+	"net/http"
+
+	"github.com/vlatan/video-store/internal/models"
+)
+
+// Get the user owning the API token, the token user has no session.
+// The API responses don't show the avatar, so it's not set.
+func (s *service) GetUserFromToken(r *http.Request, token string) (*models.User, error) {
+
+	user, err := s.usersRepo.GetTokenUser(r.Context(), models.HashToken(token))
+	if err != nil {
+		return nil, err
+	}
+
+	user.Config = s.config
+	return &user, nil
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/vlatan/video-store/internal/integrations/r2"
 	"github.com/vlatan/video-store/internal/models"
 	"github.com/vlatan/video-store/internal/repositories/categories"
+	"github.com/vlatan/video-store/internal/repositories/searches"
 	"github.com/vlatan/video-store/internal/repositories/users"
 
 	"github.com/gorilla/sessions"
@@ -24,6 +25,8 @@ import (
 type Service interface {
 	// Get the user from session
 	GetUserFromSession(w http.ResponseWriter, r *http.Request) *models.User
+	// Get the user owning the API token
+	GetUserFromToken(r *http.Request, token string) (*models.User, error)
 	// Store flash message in a session
 	StoreFlashMessage(w http.ResponseWriter, r *http.Request, m *models.FlashMessage)
 	// Get the map containing the static files
@@ -45,15 +48,16 @@ type Service interface {
 }
 
 type service struct {
-	templates   models.TemplateMap
-	textFiles   models.TextFiles
-	staticFiles models.StaticFiles
-	rdb         *rdb.Service
-	r2s         r2.Service
-	config      *config.Config
-	store       sessions.Store
-	catsRepo    *categories.Repository
-	usersRepo   *users.Repository
+	templates    models.TemplateMap
+	textFiles    models.TextFiles
+	staticFiles  models.StaticFiles
+	rdb          *rdb.Service
+	r2s          r2.Service
+	config       *config.Config
+	store        sessions.Store
+	catsRepo     *categories.Repository
+	usersRepo    *users.Repository
+	searchesRepo *searches.Repository
 }
 
 var validJS = regexp.MustCompile("^(application|text)/(x-)?(java|ecma)script$")
@@ -63,6 +67,7 @@ var validXML = regexp.MustCompile("[/+]xml$")
 func New(
 	usersRepo *users.Repository,
 	catsRepo *categories.Repository,
+	searchesRepo *searches.Repository,
 	rdb *rdb.Service,
 	r2s r2.Service,
 	store sessions.Store,
@@ -96,14 +101,15 @@ func New(
 	}
 
 	return &service{
-		templates:   templates,
-		staticFiles: staticFiles,
-		textFiles:   parseTextFiles(config),
-		rdb:         rdb,
-		r2s:         r2s,
-		config:      config,
-		store:       store,
-		catsRepo:    catsRepo,
-		usersRepo:   usersRepo,
+		templates:    templates,
+		staticFiles:  staticFiles,
+		textFiles:    parseTextFiles(config),
+		rdb:          rdb,
+		r2s:          r2s,
+		config:       config,
+		store:        store,
+		catsRepo:     catsRepo,
+		usersRepo:    usersRepo,
+		searchesRepo: searchesRepo,
 	}, nil
 }
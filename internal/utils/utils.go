@@ -6,8 +6,9 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"html"
 	"html/template"
-	"log"
+	"math"
 	"net/http"
 	"net/url"
 	"os"
@@ -96,6 +97,18 @@ func EscapeTrancateString(query string, maxLen int) string {
 	return escapedQuery
 }
 
+// NormalizeQuery lowercases the search query, collapses the whitespace
+// and truncates it to maxLen characters. If maxLen <= 0 it doesn't truncate.
+func NormalizeQuery(query string, maxLen int) string {
+
+	query = strings.ToLower(strings.Join(strings.Fields(query), " "))
+	if runes := []rune(query); maxLen > 0 && len(runes) > maxLen {
+		query = strings.TrimSpace(string(runes[:maxLen]))
+	}
+
+	return query
+}
+
 // Get page number from the request query param
 // Defaults to 1 if invalid page
 func GetPageNum(r *http.Request) (page int) {
@@ -203,14 +216,6 @@ func SleepContext(ctx context.Context, delay time.Duration) error {
 	}
 }
 
-// LogPlainln prints a line without a prefix using the log package
-func LogPlainln(v ...any) {
-	flags := log.Flags()
-	log.SetFlags(0)
-	log.Println(v...)
-	log.SetFlags(flags)
-}
-
 // GetProjectRoot returns the absolute path to the project root.
 // It works by finding the directory of the caller of this func and navigating up
 // until it finds the go.mod file.
@@ -248,3 +253,35 @@ func ParseMarkdown(content string) (template.HTML, error) {
 	html := bluemonday.UGCPolicy().SanitizeBytes(buf.Bytes())
 	return template.HTML(html), nil // #nosec G203
 }
+
+// MarkdownToText converts markdown to plain text on a single line
+func MarkdownToText(content string) (string, error) {
+	var buf bytes.Buffer
+	if err := goldmark.Convert([]byte(content), &buf); err != nil {
+		return "", err
+	}
+
+	text := bluemonday.StrictPolicy().SanitizeBytes(buf.Bytes())
+	return strings.Join(strings.Fields(html.UnescapeString(string(text))), " "), nil
+}
+
+// CosineSimilarity computes the cosine similarity of two vectors.
+// Returns 0 if the vectors differ in length or any of them is a zero vector.
+func CosineSimilarity(a, b []float32) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
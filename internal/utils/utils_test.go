@@ -1,12 +1,11 @@
 package utils
 
 import (
-	"bytes"
 	"context"
 	"database/sql"
 	"errors"
 	"fmt"
-	"log"
+	"math"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -116,6 +115,30 @@ func TestEscapeTrancateString(t *testing.T) {
 
 }
 
+func TestNormalizeQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		maxLen   int
+		expected string
+	}{
+		{"empty query", "", 10, ""},
+		{"mixed case", "World War", 10, "world war"},
+		{"extra whitespace", "  world \t war  ", 10, "world war"},
+		{"long query", "world war two", 10, "world war"},
+		{"multibyte runes", "Ćirilica Šuma", 9, "ćirilica"},
+		{"negative length", "World War Two", -2, "world war two"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeQuery(tt.query, tt.maxLen); got != tt.expected {
+				t.Errorf("got %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
 func TestGetPageNum(t *testing.T) {
 	tests := []struct {
 		name, page string
@@ -331,28 +354,51 @@ func TestSleepContext(t *testing.T) {
 	}
 }
 
-func TestLogPlainln(t *testing.T) {
-	var buf bytes.Buffer
-	original := log.Writer()
-	log.SetOutput(&buf)
-	t.Cleanup(func() { log.SetOutput(original) })
+func TestCosineSimilarity(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     []float32
+		expected float64
+	}{
+		{"empty vectors", nil, nil, 0},
+		{"different lengths", []float32{1, 2}, []float32{1, 2, 3}, 0},
+		{"zero vector", []float32{0, 0}, []float32{1, 2}, 0},
+		{"identical vectors", []float32{1, 2, 3}, []float32{1, 2, 3}, 1},
+		{"scaled vectors", []float32{1, 2}, []float32{2, 4}, 1},
+		{"orthogonal vectors", []float32{1, 0}, []float32{0, 1}, 0},
+		{"opposite vectors", []float32{1, 2}, []float32{-1, -2}, -1},
+	}
 
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CosineSimilarity(tt.a, tt.b)
+			if math.Abs(got-tt.expected) > 1e-9 {
+				t.Errorf("got %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMarkdownToText(t *testing.T) {
 	tests := []struct {
 		name     string
-		input    []any
+		content  string
 		expected string
 	}{
-		{"empty string", []any{""}, "\n"},
-		{"valid single string", []any{"foo"}, "foo\n"},
-		{"valid multiple strings", []any{"foo", "bar"}, "foo bar\n"},
+		{"empty", "", ""},
+		{"emphasis", "Some **bold** and _italic_ text", "Some bold and italic text"},
+		{"paragraphs", "First.\n\nSecond & third.", "First. Second & third."},
+		{"raw html dropped", "<script>alert(1)</script>", ""},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			t.Cleanup(buf.Reset)
-			LogPlainln(tt.input...)
-			if buf.String() != tt.expected {
-				t.Errorf("got: %q, expected %q", buf.String(), tt.expected)
+			got, err := MarkdownToText(tt.content)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("got %q, want %q", got, tt.expected)
 			}
 		})
 	}
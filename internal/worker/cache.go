@@ -0,0 +1,59 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+
+	"github.com/vlatan/video-store/internal/drivers/rdb"
+	"github.com/vlatan/video-store/internal/models"
+)
+
+// staleCache remembers the tags of the cache entries changed in this run
+func (w *Worker) staleCache(tags ...string) {
+	w.staleTags = append(w.staleTags, tags...)
+}
+
+// staleCategory remembers the category gaining a video.
+// The slug tagging its lists is looked up once, at the end of the run.
+func (w *Worker) staleCategory(category *models.Category) {
+	if category != nil && category.Name != "" {
+		w.staleCategories = append(w.staleCategories, category.Name)
+	}
+}
+
+// invalidateCache deletes the cache entries changed in this run.
+// The errors are only logged, the entries expire on their own eventually.
+func (w *Worker) invalidateCache(ctx context.Context) {
+
+	tags := w.staleTags
+
+	if len(w.staleCategories) > 0 {
+		categories, err := w.catsRepo.GetCategories(ctx)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to get the categories from DB", "error", err)
+		}
+
+		for _, name := range w.staleCategories {
+			if slug, ok := categories.Slug(name); ok {
+				tags = append(tags, rdb.CategoryTag(slug))
+			}
+		}
+	}
+
+	if len(tags) == 0 {
+		return
+	}
+
+	slices.Sort(tags)
+	tags = slices.Compact(tags)
+
+	// Use ctx without cancel so the invalidation survives the expired ctx
+	deleted, err := w.rdb.Invalidate(context.WithoutCancel(ctx), tags...)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to invalidate the cache", "error", err)
+		return
+	}
+
+	slog.InfoContext(ctx, "invalidated the cache", "tags", len(tags), "entries", deleted)
+}
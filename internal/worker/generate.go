@@ -4,7 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"math/rand/v2"
 	"time"
 
@@ -28,6 +28,12 @@ func (w *Worker) generateContent(
 		return false, nil
 	}
 
+	// Skip the video flagged for admin review.
+	// If error is nil the post is IN the flagged_post database table.
+	if err := w.postsRepo.IsPostFlagged(ctx, video.VideoID); err == nil {
+		return false, nil
+	}
+
 	// Create video contents
 	contents, err := w.gemini.MakeVideoContents(video)
 	if err != nil {
@@ -64,10 +70,10 @@ func (w *Worker) generateContent(
 	// If so make another gemini API call just with a text contents.
 	var target *gemini.BlockedError
 	if errors.As(err, &target) {
-		log.Printf(
-			"failed to generate content on video %q, "+
-				"trying again with text contents: %v",
-			video.VideoID, err,
+		slog.WarnContext(
+			ctx, "failed to generate content, trying again with text contents",
+			"video", video.VideoID,
+			"error", err,
 		)
 
 		// Create text contents
@@ -98,12 +104,21 @@ func (w *Worker) generateContent(
 		}
 	}
 
+	// The content failed the quality checks, let the caller flag the video
+	var qualityErr *gemini.QualityError
+	if errors.As(err, &qualityErr) {
+		return false, fmt.Errorf(
+			"failed to generate content on video %q; %w",
+			video.VideoID, err)
+	}
+
 	// For every other error we just log and exit with nil error.
 	// The video was not summarized though.
 	if err != nil {
-		log.Printf(
-			"failed to generate content on video %q; %v",
-			video.VideoID, err,
+		slog.ErrorContext(
+			ctx, "failed to generate content",
+			"video", video.VideoID,
+			"error", err,
 		)
 		return false, nil
 	}
@@ -111,6 +126,7 @@ func (w *Worker) generateContent(
 	video.OriginalTitle = genaiResponse.OriginalTitle
 	video.Summary = genaiResponse.Summary
 	video.Category = &models.Category{Name: genaiResponse.Category}
+	video.SuggestedCategory = genaiResponse.SuggestedCategory
 
 	return true, nil
 }
@@ -129,3 +145,69 @@ func sleep(ctx context.Context, minSleep, maxSleep time.Duration) error {
 	sleepTime := minSleep + rand.N(maxSleep-minSleep) // #nosec G404
 	return utils.SleepContext(ctx, sleepTime)
 }
+
+// flagVideo flags the video for admin review with the quality issues found.
+// Exits with error only if context ended, any other error is just logged.
+func (w *Worker) flagVideo(
+	ctx context.Context,
+	video *models.Post,
+	qualityErr *gemini.QualityError) error {
+
+	rowsAffected, err := w.postsRepo.FlagPost(
+		ctx,
+		video.VideoID,
+		qualityErr.Issues,
+		qualityErr.Response,
+	)
+	w.stats.FlaggedDbVideos += rowsAffected
+
+	if err == nil {
+		return nil
+	}
+
+	// Exit early if context ended
+	if utils.IsContextErr(err) {
+		return err
+	}
+
+	slog.ErrorContext(
+		ctx, "failed to flag the video for review",
+		"video", video.VideoID,
+		"error", err,
+	)
+
+	return nil
+}
+
+// suggestCategory records the category suggested by the model if any.
+// Exits with error only if context ended, any other error is just logged.
+func (w *Worker) suggestCategory(ctx context.Context, video *models.Post) error {
+
+	if video.SuggestedCategory == "" {
+		return nil
+	}
+
+	rowsAffected, err := w.catsRepo.SuggestCategory(
+		ctx,
+		video.VideoID,
+		video.SuggestedCategory,
+	)
+	w.stats.SuggestedDbVideos += rowsAffected
+
+	if err == nil {
+		return nil
+	}
+
+	// Exit early if context ended
+	if utils.IsContextErr(err) {
+		return err
+	}
+
+	slog.ErrorContext(
+		ctx, "failed to record the suggested category",
+		"video", video.VideoID,
+		"error", err,
+	)
+
+	return nil
+}
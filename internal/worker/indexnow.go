@@ -0,0 +1,34 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/vlatan/video-store/internal/utils"
+)
+
+// notifySearchEngines queues the inserted and the deleted videos,
+// and submits all the changed URLs queued since the last run
+func (w *Worker) notifySearchEngines(ctx context.Context) error {
+
+	if !w.indexNow.Enabled() {
+		return nil
+	}
+
+	w.indexNow.QueuePosts(ctx, w.newVideoIDs...)
+	w.indexNow.QueuePosts(ctx, w.stats.DeletedDbVideos...)
+
+	submitted, err := w.indexNow.Submit(ctx)
+	w.stats.SubmittedURLs = submitted
+
+	if utils.IsContextErr(err) {
+		return err
+	}
+
+	// The failed URLs are retried on the next run
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to submit the changed URLs to IndexNow", "error", err)
+	}
+
+	return nil
+}
@@ -4,9 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 
 	"github.com/vlatan/video-store/internal/drivers/rdb"
+	"github.com/vlatan/video-store/internal/integrations/gemini"
 	"github.com/vlatan/video-store/internal/integrations/yt"
 	"github.com/vlatan/video-store/internal/models"
 	"github.com/vlatan/video-store/internal/utils"
@@ -172,6 +173,10 @@ func (w *Worker) adoptVideos(
 		)
 		w.stats.AdoptedDbVideos += rowsAffected
 
+		if rowsAffected > 0 {
+			w.staleCache(rdb.PostTag(dbVideo.VideoID), rdb.SourceTag(ytVideo.PlaylistID))
+		}
+
 		if err == nil {
 			continue
 		}
@@ -181,9 +186,10 @@ func (w *Worker) adoptVideos(
 			return err
 		}
 
-		log.Printf(
-			"Failed to update the playlist on video %q; %v",
-			dbVideo.VideoID, err,
+		slog.ErrorContext(
+			ctx, "failed to update the playlist of the video",
+			"video", dbVideo.VideoID,
+			"error", err,
 		)
 	}
 
@@ -230,6 +236,7 @@ func (w *Worker) deleteVideos(
 		_, err := w.postsRepo.DeletePost(ctx, dbVideo.VideoID)
 		if err == nil {
 			w.stats.DeletedDbVideos = append(w.stats.DeletedDbVideos, dbVideo.VideoID)
+			w.staleCache(rdb.PostTag(dbVideo.VideoID))
 			continue
 		}
 
@@ -238,9 +245,10 @@ func (w *Worker) deleteVideos(
 			return nil, err
 		}
 
-		log.Printf(
-			"Could not delete the video %q in DB; %v",
-			dbVideo.VideoID, err,
+		slog.ErrorContext(
+			ctx, "failed to delete the video in DB",
+			"video", dbVideo.VideoID,
+			"error", err,
 		)
 	}
 
@@ -264,9 +272,30 @@ func (w *Worker) insertVideos(ctx context.Context, videos []*models.Post) error
 			return err
 		}
 
+		// Remember if the generated content failed the quality checks
+		qualityErr, flag := errors.AsType[*gemini.QualityError](err)
+
 		rowsAffected, err := w.postsRepo.InsertPost(ctx, video)
 		w.stats.InsertedDbVideos += rowsAffected
 
+		// Remember the new video to notify the saved searches,
+		// and the lists it joins
+		if rowsAffected > 0 {
+			w.newVideoIDs = append(w.newVideoIDs, video.VideoID)
+			w.staleCache(rdb.HomeTag, rdb.SearchTag, rdb.SourceTag(video.PlaylistID))
+			w.staleCategory(video.Category)
+		}
+
+		// Flag the inserted video for admin review
+		if err == nil && flag {
+			err = w.flagVideo(ctx, video, qualityErr)
+		}
+
+		// Record the new category proposed by the model
+		if err == nil {
+			err = w.suggestCategory(ctx, video)
+		}
+
 		if err == nil {
 			continue
 		}
@@ -276,9 +305,10 @@ func (w *Worker) insertVideos(ctx context.Context, videos []*models.Post) error
 			return err
 		}
 
-		log.Printf(
-			"Failed to insert video %q in DB: %v",
-			video.VideoID, err,
+		slog.ErrorContext(
+			ctx, "failed to insert the video in DB",
+			"video", video.VideoID,
+			"error", err,
 		)
 	}
 
@@ -298,7 +328,15 @@ func (w *Worker) updateVideos(ctx context.Context, videos []*models.Post) error
 
 		ok, err := w.generateContent(ctx, video)
 
-		// Exit on any error, stop updating
+		// Flag the video for admin review if the content failed the quality checks
+		if qualityErr, flag := errors.AsType[*gemini.QualityError](err); flag {
+			if err = w.flagVideo(ctx, video, qualityErr); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// Exit on any other error, stop updating
 		if err != nil {
 			return err
 		}
@@ -311,6 +349,16 @@ func (w *Worker) updateVideos(ctx context.Context, videos []*models.Post) error
 		rowsAffected, err := w.postsRepo.UpdateGeneratedData(ctx, video)
 		w.stats.UpdatedDbVideos += rowsAffected
 
+		if rowsAffected > 0 {
+			w.staleCache(rdb.PostTag(video.VideoID), rdb.SearchTag)
+			w.staleCategory(video.Category)
+		}
+
+		// Record the new category proposed by the model
+		if err == nil {
+			err = w.suggestCategory(ctx, video)
+		}
+
 		if err == nil {
 			continue
 		}
@@ -320,11 +368,157 @@ func (w *Worker) updateVideos(ctx context.Context, videos []*models.Post) error
 			return err
 		}
 
-		log.Printf(
-			"Failed to update generated data in DB on video %q; %v",
-			video.VideoID, err,
+		slog.ErrorContext(
+			ctx, "failed to update the generated data in DB",
+			"video", video.VideoID,
+			"error", err,
 		)
 	}
 
 	return nil
 }
+
+// embedVideos creates embeddings for videos with missing or stale embeddings
+// in batches, and stores them in database.
+// Exits with error only if context ended, any other error is just logged.
+func (w *Worker) embedVideos(ctx context.Context) error {
+
+	model := w.config.GeminiEmbeddingModel
+	batchSize := max(w.config.EmbeddingBatchSize, 1)
+
+	for {
+
+		// Check the context first
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		videos, err := w.postsRepo.GetPostsToEmbed(ctx, model, batchSize)
+		if err != nil {
+			if utils.IsContextErr(err) {
+				return err
+			}
+			slog.ErrorContext(ctx, "failed to get the videos to embed from DB", "error", err)
+			return nil
+		}
+
+		if len(videos) == 0 {
+			return nil
+		}
+
+		embeddings, err := w.gemini.EmbedPosts(ctx, videos, w.geminiRetryConfig)
+		if err != nil {
+			if utils.IsContextErr(err) {
+				return err
+			}
+			slog.ErrorContext(ctx, "failed to create the embeddings", "videos", len(videos), "error", err)
+			return nil
+		}
+
+		var embedded int64
+		for i, video := range videos {
+			rowsAffected, err := w.postsRepo.UpsertEmbedding(ctx, video.ID, model, embeddings[i])
+			embedded += rowsAffected
+
+			if err == nil {
+				continue
+			}
+
+			// Exit early if context ended
+			if utils.IsContextErr(err) {
+				return err
+			}
+
+			slog.ErrorContext(
+				ctx, "failed to store the embedding in DB",
+				"video", video.VideoID,
+				"error", err,
+			)
+		}
+
+		w.stats.EmbeddedDbVideos += embedded
+
+		// Stop if this was the last batch or no progress was made
+		if len(videos) < batchSize || embedded == 0 {
+			return nil
+		}
+	}
+}
+
+// translateVideos translates the videos with missing or stale translations
+// into the configured locales, and stores them in database.
+// Exits with error only if context ended or lock not owned anymore,
+// any other error is just logged.
+func (w *Worker) translateVideos(ctx context.Context) error {
+
+	for _, locale := range w.config.Locales {
+
+		videos, err := w.postsRepo.GetPostsToTranslate(ctx, locale, w.config.TranslationBatchSize)
+		if err != nil {
+			if utils.IsContextErr(err) {
+				return err
+			}
+			slog.ErrorContext(ctx, "failed to get the videos to translate from DB", "locale", locale, "error", err)
+			continue
+		}
+
+		for _, video := range videos {
+
+			// Check if the worker still owns the lock before an API call
+			if err = w.lock.CheckLock(ctx); err != nil {
+				return fmt.Errorf(
+					"this worker %q does not own the lock anymore; %w",
+					w.id, err,
+				)
+			}
+
+			translation, err := w.gemini.Translate(ctx, video, locale, w.geminiRetryConfig)
+
+			// Exit early if context ended
+			if utils.IsContextErr(err) {
+				return err
+			}
+
+			// No more quota for today, stop translating
+			if errors.Is(err, gemini.ErrDailyLimitReached) {
+				slog.WarnContext(ctx, "stopped translating the videos", "error", err)
+				return nil
+			}
+
+			if err != nil {
+				slog.ErrorContext(
+					ctx, "failed to translate the video",
+					"video", video.VideoID,
+					"locale", locale,
+					"error", err,
+				)
+				continue
+			}
+
+			rowsAffected, err := w.postsRepo.UpsertTranslation(ctx, video.ID, locale, translation)
+			w.stats.TranslatedDbVideos += rowsAffected
+
+			if rowsAffected > 0 {
+				w.staleCache(rdb.PostTag(video.VideoID))
+			}
+
+			if err == nil {
+				continue
+			}
+
+			// Exit early if context ended
+			if utils.IsContextErr(err) {
+				return err
+			}
+
+			slog.ErrorContext(
+				ctx, "failed to store the translation in DB",
+				"video", video.VideoID,
+				"locale", locale,
+				"error", err,
+			)
+		}
+	}
+
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"maps"
 	"slices"
 
+	"github.com/vlatan/video-store/internal/drivers/rdb"
 	"github.com/vlatan/video-store/internal/models"
 	"google.golang.org/api/youtube/v3"
 )
@@ -16,6 +17,23 @@ const deleteLimit = 5
 // Process processes the videos
 func (w *Worker) Process(ctx context.Context) error {
 
+	// SYNC THE TEXT SEARCH CONFIGURATION
+	// ###################################################################
+
+	// Rebuild the search vectors if the configuration changed
+	rebuilt, err := w.postsRepo.SyncSearchConfig(ctx)
+	if err != nil {
+		return fmt.Errorf(
+			"could not sync the text search config %q; %w",
+			w.config.SearchConfig, err,
+		)
+	}
+	w.stats.RebuiltSearchVectors = rebuilt
+
+	if rebuilt {
+		w.staleCache(rdb.SearchTag)
+	}
+
 	// GET ALL THE PLAYLISTS FROM DATABASE
 	// ###################################################################
 
@@ -151,6 +169,19 @@ func (w *Worker) Process(ctx context.Context) error {
 		return err
 	}
 
+	// NOTIFY THE SAVED SEARCHES MATCHING THE NEW VIDEOS
+	// ###################################################################
+
+	// Right after the inserts, the next run doesn't see these videos as new,
+	// so a later stage failing would otherwise leave them unmatched
+	if err = w.notifySavedSearches(ctx); err != nil {
+		return err
+	}
+
+	if err = w.sendDigests(ctx); err != nil {
+		return err
+	}
+
 	// UPDATE THE EXISTING VIDEOS IN DATABASE
 	// ###################################################################
 
@@ -158,5 +189,33 @@ func (w *Worker) Process(ctx context.Context) error {
 		return err
 	}
 
+	// TRANSLATE THE NEW AND UPDATED VIDEOS IN DATABASE
+	// ###################################################################
+
+	if err = w.translateVideos(ctx); err != nil {
+		return err
+	}
+
+	// EMBED THE NEW AND UPDATED VIDEOS IN DATABASE
+	// ###################################################################
+
+	if err = w.embedVideos(ctx); err != nil {
+		return err
+	}
+
+	// REFRESH THE SEARCH LEXICON WITH THE CURRENT VIDEOS
+	// ###################################################################
+
+	if err = w.postsRepo.RefreshLexicon(ctx); err != nil {
+		return fmt.Errorf("could not refresh the search lexicon; %w", err)
+	}
+
+	// NOTIFY THE SEARCH ENGINES OF THE CHANGED URLS
+	// ###################################################################
+
+	if err = w.notifySearchEngines(ctx); err != nil {
+		return err
+	}
+
 	return nil
 }
@@ -2,8 +2,13 @@ package worker
 
 import (
 	"context"
-	"log"
+	"log/slog"
 	"time"
+
+	"github.com/vlatan/video-store/internal/logging"
+	"github.com/vlatan/video-store/internal/metrics"
+	"github.com/vlatan/video-store/internal/tracing"
+	"go.opentelemetry.io/otel/codes"
 )
 
 // Run starts the worker
@@ -12,20 +17,47 @@ func (w *Worker) Run(ctx context.Context) {
 	// Cleanup on exit
 	defer w.cleanup()
 
+	// Every log line of the run carries its ID
+	ctx = logging.With(ctx, "runId", w.id)
+
 	// Measure execution time
 	start := time.Now()
 	defer func() {
 		elapsed := time.Since(start).Round(time.Second)
-		log.Printf("Time took: %s", elapsed)
+		slog.InfoContext(ctx, "worker finished", "elapsed", elapsed.String())
 	}()
 
-	log.Println("Worker running...")
+	slog.InfoContext(ctx, "worker running")
+
+	// One trace for the whole run
+	ctx, span := tracing.Start(ctx, "worker.Run")
 	err := w.Process(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	// Delete the cache entries the run changed, even if it failed midway
+	w.invalidateCache(ctx)
+	span.End()
 
 	// Log the worker stats
-	w.stats.Log()
+	w.stats.Log(ctx)
 
 	if err != nil {
-		log.Printf("Worker error: %v", err)
+		slog.ErrorContext(ctx, "worker failed", "error", err)
+	}
+
+	// Leave the outcome for the app's metrics.
+	// Use ctx without cancel so the record survives the expired ctx.
+	run := metrics.WorkerRun{
+		Success:  err == nil,
+		Started:  start,
+		Duration: time.Since(start),
+		Videos:   w.stats.Videos(),
+	}
+
+	if err = metrics.RecordWorkerRun(context.WithoutCancel(ctx), w.rdb.Client, run); err != nil {
+		slog.ErrorContext(ctx, "failed to record the worker run", "error", err)
 	}
 }
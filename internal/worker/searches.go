@@ -0,0 +1,140 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/vlatan/video-store/internal/models"
+	"github.com/vlatan/video-store/internal/utils"
+)
+
+// notifySavedSearches notifies the saved searches matching the newly inserted videos
+func (w *Worker) notifySavedSearches(ctx context.Context) error {
+
+	if len(w.newVideoIDs) == 0 {
+		return nil
+	}
+
+	searches, err := w.searchesRepo.GetAllSavedSearches(ctx)
+	if err != nil {
+		return fmt.Errorf("could not fetch the saved searches from DB; %w", err)
+	}
+
+	// Many users can save the same search, match it only once
+	matched := make(map[string][]string)
+
+	for _, search := range searches {
+
+		matches, ok := matched[search.Key()]
+		if !ok {
+			matches, err = w.postsRepo.MatchPosts(
+				ctx, search.Query, search.Filters, w.newVideoIDs,
+			)
+
+			if utils.IsContextErr(err) {
+				return err
+			}
+
+			if err != nil {
+				slog.ErrorContext(ctx, "failed to match the saved search", "search", search.ID, "error", err)
+				continue
+			}
+
+			matched[search.Key()] = matches
+		}
+
+		if len(matches) == 0 {
+			continue
+		}
+
+		rowsAffected, err := w.searchesRepo.InsertNotifications(ctx, search.ID, matches)
+		w.stats.NotifiedSearches += rowsAffected
+
+		if utils.IsContextErr(err) {
+			return err
+		}
+
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to notify the saved search", "search", search.ID, "error", err)
+		}
+	}
+
+	return nil
+}
+
+// sendDigests emails the users who asked for it their unseen notifications
+func (w *Worker) sendDigests(ctx context.Context) error {
+
+	// Emails are optional
+	if !w.mail.Enabled() {
+		return nil
+	}
+
+	digests, err := w.searchesRepo.GetPendingDigests(ctx)
+	if err != nil {
+		return fmt.Errorf("could not fetch the search digests from DB; %w", err)
+	}
+
+	for _, digest := range digests {
+
+		subject, body := w.digestMessage(digest)
+		err = w.mail.Send(ctx, digest.Email, subject, body)
+
+		if utils.IsContextErr(err) {
+			return err
+		}
+
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to email the search digest", "user", digest.UserID, "error", err)
+			continue
+		}
+
+		ids := make([]int, len(digest.Notifications))
+		for i, n := range digest.Notifications {
+			ids[i] = n.ID
+		}
+
+		// Not marking them would send them again on the next run
+		if _, err = w.searchesRepo.MarkEmailed(ctx, ids); err != nil {
+			return fmt.Errorf("could not mark the search digest as emailed; %w", err)
+		}
+
+		w.stats.EmailedDigests++
+	}
+
+	return nil
+}
+
+// digestMessage composes the subject and the plain text body of the digest
+func (w *Worker) digestMessage(digest models.SearchDigest) (string, string) {
+
+	baseURL := fmt.Sprintf("%s://%s", w.config.Protocol, w.config.Domain)
+	subject := fmt.Sprintf("New videos for your saved searches on %s", w.config.AppName)
+
+	var b strings.Builder
+	if digest.Name != "" {
+		fmt.Fprintf(&b, "Hi %s,\n\n", digest.Name)
+	} else {
+		b.WriteString("Hi,\n\n")
+	}
+
+	b.WriteString("New videos match your saved searches:\n")
+
+	// The notifications are ordered by the saved search
+	lastSearch := 0
+	for _, n := range digest.Notifications {
+
+		if n.SavedSearch.ID != lastSearch {
+			lastSearch = n.SavedSearch.ID
+			fmt.Fprintf(&b, "\n%q\n%s%s\n\n", n.SavedSearch.Query, baseURL, n.SavedSearch.URL())
+		}
+
+		fmt.Fprintf(&b, "- %s\n  %s/video/%s/\n", n.Title, baseURL, n.VideoID)
+	}
+
+	fmt.Fprintf(&b, "\nManage your saved searches: %s/user/searches/\n", baseURL)
+
+	return subject, b.String()
+}
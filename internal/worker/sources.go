@@ -2,8 +2,9 @@ package worker
 
 import (
 	"context"
-	"log"
+	"log/slog"
 
+	"github.com/vlatan/video-store/internal/drivers/rdb"
 	"github.com/vlatan/video-store/internal/models"
 	"github.com/vlatan/video-store/internal/utils"
 	"google.golang.org/api/youtube/v3"
@@ -39,6 +40,10 @@ func (w *Worker) updateSources(
 		rowsAffected, err := w.sourcesRepo.UpdateSource(ctx, newSource)
 		w.stats.UpdatedDbSources += rowsAffected
 
+		if rowsAffected > 0 {
+			w.staleCache(rdb.SourcesTag, rdb.SourceTag(playlistID))
+		}
+
 		if err == nil {
 			continue
 		}
@@ -49,9 +54,10 @@ func (w *Worker) updateSources(
 		}
 
 		// Log the error, do not exit if can't update playlist in DB
-		log.Printf(
-			"Could not update source %q in DB; %v",
-			newSource.PlaylistID, err,
+		slog.ErrorContext(
+			ctx, "failed to update the source in DB",
+			"playlist", newSource.PlaylistID,
+			"error", err,
 		)
 	}
 
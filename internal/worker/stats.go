@@ -1,76 +1,98 @@
 package worker
 
 import (
-	"log"
+	"context"
+	"log/slog"
 )
 
-type stat struct {
-	label string
-	value any
-}
-
 type WorkerStats struct {
-	FetchedDbSources  int
-	FetchedYtSources  int
-	FetchedYtChannels int
-	UpdatedDbSources  int64
-	FetchedDbVideos   int
-	FetchedYtVideos   int
-	AdoptedDbVideos   int64
-	DeletedDbVideos   []string
-	InsertedDbVideos  int64
-	UpdatedDbVideos   int64
+	RebuiltSearchVectors bool
+	FetchedDbSources     int
+	FetchedYtSources     int
+	FetchedYtChannels    int
+	UpdatedDbSources     int64
+	FetchedDbVideos      int
+	FetchedYtVideos      int
+	AdoptedDbVideos      int64
+	DeletedDbVideos      []string
+	InsertedDbVideos     int64
+	UpdatedDbVideos      int64
+	EmbeddedDbVideos     int64
+	FlaggedDbVideos      int64
+	TranslatedDbVideos   int64
+	SuggestedDbVideos    int64
+	NotifiedSearches     int64
+	EmailedDigests       int
+	SubmittedURLs        int
 }
 
-// Log logs the worker stats
-func (ws WorkerStats) Log() {
+// Log logs the worker stats in a single record
+func (ws WorkerStats) Log(ctx context.Context) {
+	slog.InfoContext(ctx, "worker stats", ws.Attrs()...)
+}
 
-	stats := []stat{
-		{"Fetched playlists from DB", ws.FetchedDbSources},
-		{"Fetched playlists from YT", ws.FetchedYtSources},
-		{"Fetched channels from YT", ws.FetchedYtChannels},
-	}
+// Attrs returns the worker stats as log attributes.
+// The counters which are mostly zero are left out when they are.
+func (ws WorkerStats) Attrs() []any {
 
-	if ws.UpdatedDbSources > 0 {
-		stats = append(stats, stat{"Updated playlists in DB", ws.UpdatedDbSources})
+	attrs := []any{
+		slog.Int("fetchedDbSources", ws.FetchedDbSources),
+		slog.Int("fetchedYtSources", ws.FetchedYtSources),
+		slog.Int("fetchedYtChannels", ws.FetchedYtChannels),
+		slog.Int("fetchedDbVideos", ws.FetchedDbVideos),
+		slog.Int("fetchedYtVideos", ws.FetchedYtVideos),
 	}
 
-	stats = append(stats, stat{"Fetched videos from DB", ws.FetchedDbVideos})
-	stats = append(stats, stat{"Fetched videos from YT", ws.FetchedYtVideos})
-
-	if ws.AdoptedDbVideos > 0 {
-		stats = append(stats, stat{"Adopted videos in DB", ws.AdoptedDbVideos})
+	if ws.RebuiltSearchVectors {
+		attrs = append(attrs, slog.Bool("rebuiltSearchVectors", true))
 	}
 
 	if len(ws.DeletedDbVideos) > 0 {
-		stats = append(stats, stat{"Deleted videos in DB", len(ws.DeletedDbVideos)})
-		stats = append(stats, stat{"Deleted videos ids", ws.DeletedDbVideos})
+		attrs = append(attrs,
+			slog.Int("deletedDbVideos", len(ws.DeletedDbVideos)),
+			slog.Any("deletedVideoIds", ws.DeletedDbVideos),
+		)
 
 		if len(ws.DeletedDbVideos) >= deleteLimit {
-			stats = append(stats, stat{"WARNING: MAX DELETE LIMIT REACHED", ""})
+			attrs = append(attrs, slog.Bool("deleteLimitReached", true))
 		}
 	}
 
-	if ws.InsertedDbVideos > 0 {
-		stats = append(stats, stat{"Added videos in DB", ws.InsertedDbVideos})
+	counters := []struct {
+		key   string
+		value int64
+	}{
+		{"updatedDbSources", ws.UpdatedDbSources},
+		{"adoptedDbVideos", ws.AdoptedDbVideos},
+		{"insertedDbVideos", ws.InsertedDbVideos},
+		{"updatedDbVideos", ws.UpdatedDbVideos},
+		{"flaggedDbVideos", ws.FlaggedDbVideos},
+		{"suggestedDbVideos", ws.SuggestedDbVideos},
+		{"translatedDbVideos", ws.TranslatedDbVideos},
+		{"embeddedDbVideos", ws.EmbeddedDbVideos},
+		{"notifiedSearches", ws.NotifiedSearches},
+		{"emailedDigests", int64(ws.EmailedDigests)},
+		{"submittedUrls", int64(ws.SubmittedURLs)},
 	}
 
-	if ws.UpdatedDbVideos > 0 {
-		stats = append(stats, stat{"Updated videos in DB", ws.UpdatedDbVideos})
+	for _, c := range counters {
+		if c.value > 0 {
+			attrs = append(attrs, slog.Int64(c.key, c.value))
+		}
 	}
 
-	logStats(stats)
+	return attrs
 }
 
-// logStats logs the labels and values in stats
-func logStats(stats []stat) {
-	maxLabel := 0
-	for _, s := range stats {
-		maxLabel = max(maxLabel, len(s.label))
-	}
-
-	// Pad the left strings to the length of the longest string
-	for _, s := range stats {
-		log.Printf("%-*s %v", maxLabel+1, s.label+":", s.value)
+// Videos returns the changed videos by action
+func (ws WorkerStats) Videos() map[string]int64 {
+	return map[string]int64{
+		"inserted":   ws.InsertedDbVideos,
+		"updated":    ws.UpdatedDbVideos,
+		"deleted":    int64(len(ws.DeletedDbVideos)),
+		"adopted":    ws.AdoptedDbVideos,
+		"flagged":    ws.FlaggedDbVideos,
+		"translated": ws.TranslatedDbVideos,
+		"embedded":   ws.EmbeddedDbVideos,
 	}
 }
@@ -3,7 +3,7 @@ package worker
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,10 +11,16 @@ import (
 	"github.com/vlatan/video-store/internal/drivers/database"
 	"github.com/vlatan/video-store/internal/drivers/rdb"
 	"github.com/vlatan/video-store/internal/integrations/gemini"
+	"github.com/vlatan/video-store/internal/integrations/indexnow"
+	"github.com/vlatan/video-store/internal/integrations/mail"
 	"github.com/vlatan/video-store/internal/integrations/yt"
+	"github.com/vlatan/video-store/internal/logging"
 	"github.com/vlatan/video-store/internal/repositories/categories"
+	indexNowRepo "github.com/vlatan/video-store/internal/repositories/indexnow"
 	"github.com/vlatan/video-store/internal/repositories/posts"
+	"github.com/vlatan/video-store/internal/repositories/searches"
 	"github.com/vlatan/video-store/internal/repositories/sources"
+	"github.com/vlatan/video-store/internal/tracing"
 	"github.com/vlatan/video-store/internal/utils"
 )
 
@@ -23,13 +29,20 @@ type Worker struct {
 	postsRepo         *posts.Repository
 	sourcesRepo       *sources.Repository
 	catsRepo          *categories.Repository
+	searchesRepo      *searches.Repository
 	config            *config.Config
 	youtube           *yt.Service
 	gemini            *gemini.Service
+	mail              *mail.Service
+	indexNow          *indexnow.Service
+	rdb               *rdb.Service
 	lock              *rdb.RedisLock
 	stats             WorkerStats
 	ytRetryConfig     *utils.RetryConfig
 	geminiRetryConfig *utils.RetryConfig
+	newVideoIDs       []string // inserted in this run
+	staleTags         []string // of the cache entries changed in this run
+	staleCategories   []string // names of the categories gaining videos
 	cleanup           func()
 }
 
@@ -38,6 +51,12 @@ const workerLockKey = "worker:lock"
 
 func New(cfg *config.Config, ctx context.Context) (*Worker, error) {
 
+	// Set up tracing, a no-op without an exporter endpoint
+	shutdownTracing, err := tracing.Setup(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't set up tracing; %w", err)
+	}
+
 	db, err := database.New(cfg)
 	if err != nil {
 		return nil, fmt.Errorf("couldn't create DB service; %w", err)
@@ -64,6 +83,16 @@ func New(cfg *config.Config, ctx context.Context) (*Worker, error) {
 		return nil, fmt.Errorf("couldn't create categories repo: %w", err)
 	}
 
+	searchesRepo, err := searches.New(db, nil)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't create searches repo: %w", err)
+	}
+
+	indexNowRepo, err := indexNowRepo.New(db, nil)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't create IndexNow repo: %w", err)
+	}
+
 	// Create YouTube service
 	yt, err := yt.New(ctx, cfg)
 	if err != nil {
@@ -77,13 +106,17 @@ func New(cfg *config.Config, ctx context.Context) (*Worker, error) {
 	}
 
 	w := &Worker{
-		id:          uuid.New().String(),
-		postsRepo:   postsRepo,
-		sourcesRepo: sourcesRepo,
-		catsRepo:    catsRepo,
-		config:      cfg,
-		youtube:     yt,
-		gemini:      gemini,
+		id:           uuid.New().String(),
+		postsRepo:    postsRepo,
+		sourcesRepo:  sourcesRepo,
+		catsRepo:     catsRepo,
+		searchesRepo: searchesRepo,
+		config:       cfg,
+		youtube:      yt,
+		gemini:       gemini,
+		mail:         mail.New(cfg),
+		indexNow:     indexnow.New(cfg, indexNowRepo),
+		rdb:          rdb,
 		ytRetryConfig: &utils.RetryConfig{
 			MaxRetries: 3,
 			MaxJitter:  time.Second,
@@ -106,12 +139,14 @@ func New(cfg *config.Config, ctx context.Context) (*Worker, error) {
 		return nil, fmt.Errorf("worker failed to acquire Redis lock; %w", err)
 	}
 
-	log.Println("Lock acquired!")
+	// Every log line of the run carries its ID
+	ctx = logging.With(ctx, "runId", w.id)
+	slog.InfoContext(ctx, "lock acquired")
 
 	// Register the cleanup function
 	w.cleanup = func() {
 
-		log.Println("Cleaning up...")
+		slog.InfoContext(ctx, "cleaning up")
 
 		// Close the DB pool
 		db.Pool.Close()
@@ -119,15 +154,22 @@ func New(cfg *config.Config, ctx context.Context) (*Worker, error) {
 		// Delete the Redis lock key.
 		// Use ctx without cancel so Unlock isn't killed by the expired ctx.
 		if err := w.lock.Unlock(context.WithoutCancel(ctx)); err != nil {
-			log.Printf("Failed to release the Redis lock; %v", err)
+			slog.ErrorContext(ctx, "failed to release the Redis lock", "error", err)
 		}
 
 		// Close the Redis client
 		if err := rdb.Client.Close(); err != nil {
-			log.Printf("Failed to close the Redis client; %v", err)
+			slog.ErrorContext(ctx, "failed to close the Redis client", "error", err)
+		}
+
+		// Flush the pending spans, but don't hang on an unreachable collector
+		shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(shutdownCtx); err != nil {
+			slog.ErrorContext(ctx, "failed to flush the traces", "error", err)
 		}
 
-		log.Println("Done!")
+		slog.InfoContext(ctx, "done")
 	}
 
 	return w, nil
@@ -0,0 +1,2 @@
+-- Drop post_embedding table (automatically drops its triggers)
+DROP TABLE IF EXISTS post_embedding;
@@ -0,0 +1,13 @@
+CREATE TABLE post_embedding (
+    post_id INTEGER PRIMARY KEY REFERENCES post(id) ON DELETE CASCADE,
+    model VARCHAR(128) NOT NULL,
+    embedding REAL[] NOT NULL,
+    created_at TIMESTAMP WITHOUT TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITHOUT TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+
+-- Create trigger on the post_embedding table to update the updated_at timestamp
+CREATE TRIGGER post_embedding_timestamp_update
+    BEFORE UPDATE ON post_embedding
+    FOR EACH ROW EXECUTE FUNCTION update_timestamp();
@@ -0,0 +1,2 @@
+-- Drop flagged_post table (automatically drops its triggers)
+DROP TABLE IF EXISTS flagged_post;
@@ -0,0 +1,16 @@
+-- Posts whose generated content failed the quality checks
+-- and are waiting for an admin review.
+CREATE TABLE flagged_post (
+    id SERIAL PRIMARY KEY,
+    video_id VARCHAR(20) NOT NULL UNIQUE REFERENCES post(video_id) ON DELETE CASCADE,
+    issues TEXT[] NOT NULL,
+    response JSONB,
+    created_at TIMESTAMP WITHOUT TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITHOUT TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+
+-- Create trigger on the flagged_post table to update the updated_at timestamp
+CREATE TRIGGER flagged_post_timestamp_update
+    BEFORE UPDATE ON flagged_post
+    FOR EACH ROW EXECUTE FUNCTION update_timestamp();
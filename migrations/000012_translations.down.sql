@@ -0,0 +1,2 @@
+-- Drop post_translation table (automatically drops its triggers and indexes)
+DROP TABLE IF EXISTS post_translation;
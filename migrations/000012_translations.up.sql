@@ -0,0 +1,16 @@
+CREATE TABLE post_translation (
+    id SERIAL PRIMARY KEY,
+    post_id INTEGER NOT NULL REFERENCES post(id) ON DELETE CASCADE,
+    locale VARCHAR(10) NOT NULL,
+    title VARCHAR(256),
+    summary TEXT NOT NULL,
+    created_at TIMESTAMP WITHOUT TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITHOUT TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(post_id, locale) -- One translation per locale
+);
+
+
+-- Create trigger on the post_translation table to update the updated_at timestamp
+CREATE TRIGGER post_translation_timestamp_update
+    BEFORE UPDATE ON post_translation
+    FOR EACH ROW EXECUTE FUNCTION update_timestamp();
@@ -0,0 +1,2 @@
+-- Drop category_suggestion table (automatically drops its indexes and triggers)
+DROP TABLE IF EXISTS category_suggestion;
@@ -0,0 +1,19 @@
+-- Categories proposed by the model for posts that don't fit
+-- any of the existing ones, waiting for an admin review.
+CREATE TABLE category_suggestion (
+    id SERIAL PRIMARY KEY,
+    video_id VARCHAR(20) NOT NULL UNIQUE REFERENCES post(video_id) ON DELETE CASCADE,
+    name VARCHAR(256) NOT NULL,
+    created_at TIMESTAMP WITHOUT TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITHOUT TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+
+-- Suggestions are aggregated case insensitive
+CREATE INDEX category_suggestion_name_idx ON category_suggestion (LOWER(name));
+
+
+-- Create trigger on the category_suggestion table to update the updated_at timestamp
+CREATE TRIGGER category_suggestion_timestamp_update
+    BEFORE UPDATE ON category_suggestion
+    FOR EACH ROW EXECUTE FUNCTION update_timestamp();
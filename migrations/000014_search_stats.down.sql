@@ -0,0 +1,3 @@
+-- Drop the search stats tables (automatically drops their indexes)
+DROP TABLE IF EXISTS search_click;
+DROP TABLE IF EXISTS search_stat;
@@ -0,0 +1,28 @@
+-- Daily aggregates of the normalized search queries
+CREATE TABLE search_stat (
+    query VARCHAR(256) NOT NULL,
+    day DATE NOT NULL DEFAULT CURRENT_DATE,
+    searches INTEGER NOT NULL DEFAULT 0,
+    zero_results INTEGER NOT NULL DEFAULT 0,
+    results INTEGER NOT NULL DEFAULT 0, -- the latest number of results
+    latency_ms BIGINT NOT NULL DEFAULT 0, -- the summed latency of the searches
+    clicks INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (query, day)
+);
+
+
+-- The reports aggregate the recent days
+CREATE INDEX search_stat_day_idx ON search_stat (day);
+
+
+-- Daily clicks on the posts from the search results
+CREATE TABLE search_click (
+    query VARCHAR(256) NOT NULL,
+    day DATE NOT NULL DEFAULT CURRENT_DATE,
+    post_id INTEGER NOT NULL REFERENCES post(id) ON DELETE CASCADE,
+    clicks INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (query, day, post_id)
+);
+
+
+CREATE INDEX search_click_day_idx ON search_click (day);
@@ -0,0 +1,2 @@
+-- Drop search_lexicon view (automatically drops its indexes)
+DROP MATERIALIZED VIEW IF EXISTS search_lexicon;
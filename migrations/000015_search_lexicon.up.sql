@@ -0,0 +1,25 @@
+-- Lexicon of the indexed words, used to correct the misspelled searches.
+-- The words come from the same columns as the posts' search_vector,
+-- but parsed with the 'simple' configuration so they are not stemmed
+-- and can be suggested back to the user as they are.
+CREATE MATERIALIZED VIEW search_lexicon AS
+SELECT word, ndoc
+FROM ts_stat($$
+    SELECT to_tsvector(
+        'simple',
+        coalesce(title, '') || ' ' ||
+        coalesce(original_title, '') || ' ' ||
+        coalesce(summary, '') || ' ' ||
+        coalesce(tags, '')
+    )
+    FROM post
+$$)
+WHERE length(word) > 2 AND word ~ '^[[:alpha:]]+$';
+
+
+-- Unique index needed to refresh the view concurrently
+CREATE UNIQUE INDEX search_lexicon_word_idx ON search_lexicon (word);
+
+
+-- Create GIN index on the word column for the pg_trgm
+CREATE INDEX search_lexicon_word_trgm_idx ON search_lexicon USING GIN (word gin_trgm_ops);
@@ -0,0 +1,3 @@
+-- Drop the saved searches tables (automatically drops their indexes)
+DROP TABLE IF EXISTS search_notification;
+DROP TABLE IF EXISTS saved_search;
@@ -0,0 +1,30 @@
+-- Searches the users saved to be notified on new matching posts
+CREATE TABLE saved_search (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES app_user(id) ON DELETE CASCADE,
+    query VARCHAR(256) NOT NULL,
+    filters VARCHAR(512) NOT NULL DEFAULT '', -- the encoded filters query params
+    email_digest BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITHOUT TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, query, filters)
+);
+
+
+-- New posts matching the saved searches
+CREATE TABLE search_notification (
+    id SERIAL PRIMARY KEY,
+    saved_search_id INTEGER NOT NULL REFERENCES saved_search(id) ON DELETE CASCADE,
+    post_id INTEGER NOT NULL REFERENCES post(id) ON DELETE CASCADE,
+    read_at TIMESTAMP WITHOUT TIME ZONE,
+    emailed_at TIMESTAMP WITHOUT TIME ZONE,
+    created_at TIMESTAMP WITHOUT TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (saved_search_id, post_id)
+);
+
+
+-- The unread and the not yet emailed notifications are looked up often
+CREATE INDEX search_notification_unread_idx ON search_notification (saved_search_id)
+WHERE read_at IS NULL;
+
+CREATE INDEX search_notification_unemailed_idx ON search_notification (saved_search_id)
+WHERE emailed_at IS NULL;
@@ -0,0 +1,53 @@
+DROP TABLE IF EXISTS search_synonym;
+
+
+-- Restore the search vector functions with the hardcoded configuration
+CREATE OR REPLACE FUNCTION update_post_search_vector()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.search_vector =
+        setweight(to_tsvector('english', coalesce(NEW.title, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(NEW.original_title, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(NEW.summary, '')), 'B') ||
+        setweight(to_tsvector('english', coalesce(NEW.description, '')), 'C') ||
+        setweight(to_tsvector('english', coalesce(NEW.tags, '')), 'D');
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+
+CREATE OR REPLACE FUNCTION update_post_review_search_vector()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.search_vector =
+        setweight(to_tsvector('english', coalesce(NEW.title, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(NEW.review, '')), 'B');
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+
+DROP FUNCTION IF EXISTS post_review_search_vector(post_review);
+DROP FUNCTION IF EXISTS post_search_vector(post);
+DROP FUNCTION IF EXISTS search_regconfig();
+DROP TABLE IF EXISTS search_setting;
+
+
+-- Rebuild the vectors in case they were built with another configuration,
+-- without touching the updated_at timestamps
+ALTER TABLE post DISABLE TRIGGER post_timestamp_update;
+ALTER TABLE post_review DISABLE TRIGGER post_review_timestamp_update;
+
+UPDATE post SET search_vector =
+    setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('english', coalesce(original_title, '')), 'A') ||
+    setweight(to_tsvector('english', coalesce(summary, '')), 'B') ||
+    setweight(to_tsvector('english', coalesce(description, '')), 'C') ||
+    setweight(to_tsvector('english', coalesce(tags, '')), 'D');
+
+UPDATE post_review SET search_vector =
+    setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('english', coalesce(review, '')), 'B');
+
+ALTER TABLE post ENABLE TRIGGER post_timestamp_update;
+ALTER TABLE post_review ENABLE TRIGGER post_review_timestamp_update;
@@ -0,0 +1,71 @@
+-- Single row holding the text search configuration of the search vectors.
+-- The worker switches it to the SEARCH_CONFIG env and rebuilds the vectors.
+CREATE TABLE search_setting (
+    id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+    regconfig REGCONFIG NOT NULL DEFAULT 'english',
+    updated_at TIMESTAMP WITHOUT TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO search_setting DEFAULT VALUES;
+
+
+-- The text search configuration used by the vectors and the queries
+CREATE FUNCTION search_regconfig()
+RETURNS REGCONFIG AS $$
+    SELECT regconfig FROM search_setting;
+$$ LANGUAGE sql STABLE;
+
+
+-- Compute the post search vector, used by the trigger and the rebuild
+CREATE FUNCTION post_search_vector(p post)
+RETURNS TSVECTOR AS $$
+DECLARE
+    cfg REGCONFIG := search_regconfig();
+BEGIN
+    RETURN
+        setweight(to_tsvector(cfg, coalesce(p.title, '')), 'A') ||
+        setweight(to_tsvector(cfg, coalesce(p.original_title, '')), 'A') ||
+        setweight(to_tsvector(cfg, coalesce(p.summary, '')), 'B') ||
+        setweight(to_tsvector(cfg, coalesce(p.description, '')), 'C') ||
+        setweight(to_tsvector(cfg, coalesce(p.tags, '')), 'D');
+END;
+$$ LANGUAGE plpgsql STABLE;
+
+
+CREATE OR REPLACE FUNCTION update_post_search_vector()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.search_vector = post_search_vector(NEW);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+
+-- Compute the review search vector, used by the trigger and the rebuild
+CREATE FUNCTION post_review_search_vector(pr post_review)
+RETURNS TSVECTOR AS $$
+DECLARE
+    cfg REGCONFIG := search_regconfig();
+BEGIN
+    RETURN
+        setweight(to_tsvector(cfg, coalesce(pr.title, '')), 'A') ||
+        setweight(to_tsvector(cfg, coalesce(pr.review, '')), 'B');
+END;
+$$ LANGUAGE plpgsql STABLE;
+
+
+CREATE OR REPLACE FUNCTION update_post_review_search_vector()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.search_vector = post_review_search_vector(NEW);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+
+-- Admin managed groups of equivalent search terms, e.g. WWII = World War II
+CREATE TABLE search_synonym (
+    id SERIAL PRIMARY KEY,
+    terms VARCHAR(128)[] NOT NULL CHECK (cardinality(terms) > 1),
+    created_at TIMESTAMP WITHOUT TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,2 @@
+-- Drop the API tokens table (automatically drops its indexes)
+DROP TABLE IF EXISTS api_token;
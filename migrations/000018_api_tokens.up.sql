@@ -0,0 +1,15 @@
+-- Personal access tokens of the users for the scripts calling the site
+CREATE TABLE api_token (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES app_user(id) ON DELETE CASCADE,
+    name VARCHAR(64) NOT NULL,
+    token_hash BYTEA NOT NULL UNIQUE, -- SHA-256 of the token, the token itself is never stored
+    prefix VARCHAR(16) NOT NULL, -- start of the token to recognize it by
+    scopes TEXT[] NOT NULL CHECK (cardinality(scopes) > 0),
+    expires_at TIMESTAMP WITHOUT TIME ZONE,
+    last_used_at TIMESTAMP WITHOUT TIME ZONE,
+    created_at TIMESTAMP WITHOUT TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+
+CREATE INDEX api_token_user_id_idx ON api_token (user_id);
@@ -0,0 +1,2 @@
+-- Drop the IndexNow queue table (automatically drops its indexes)
+DROP TABLE IF EXISTS indexnow_url;
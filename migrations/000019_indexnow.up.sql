@@ -0,0 +1,13 @@
+-- The changed URLs waiting for the search engines to be notified via IndexNow.
+-- A URL changed again is queued again, the last submission attempt is kept.
+CREATE TABLE indexnow_url (
+    url TEXT PRIMARY KEY,
+    queued_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    submitted_at TIMESTAMP WITHOUT TIME ZONE,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT
+);
+
+
+-- The pending URLs in the order they were queued
+CREATE INDEX indexnow_url_pending_idx ON indexnow_url (queued_at) WHERE submitted_at IS NULL;
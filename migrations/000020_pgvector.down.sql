@@ -0,0 +1,4 @@
+-- Drop the HNSW index, the embeddings are plain arrays in any case
+DROP INDEX IF EXISTS idx_post_embedding_hnsw;
+
+DROP EXTENSION IF EXISTS vector;
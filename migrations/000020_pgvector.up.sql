@@ -0,0 +1,25 @@
+-- Index the embeddings with pgvector when it's available,
+-- so the nearest posts are found by the index instead of comparing every embedding.
+-- Without the extension the embeddings stay plain arrays compared in Go.
+-- The index needs a fixed dimension, the only supported EMBEDDING_DIMENSIONS.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_available_extensions WHERE name = 'vector') THEN
+        RAISE NOTICE 'pgvector is not available, the embeddings are not indexed';
+        RETURN;
+    END IF;
+
+    CREATE EXTENSION IF NOT EXISTS vector;
+
+    -- The embeddings of another dimension can't be indexed,
+    -- the worker embeds their posts again
+    DELETE FROM post_embedding WHERE array_length(embedding, 1) <> 768;
+
+    -- HNSW index on the embeddings cast to vectors, for the cosine distance
+    CREATE INDEX idx_post_embedding_hnsw ON post_embedding
+        USING hnsw ((embedding::vector(768)) vector_cosine_ops);
+EXCEPTION
+    WHEN insufficient_privilege THEN
+        RAISE NOTICE 'not allowed to create pgvector, the embeddings are not indexed';
+END
+$$;
@@ -0,0 +1,7 @@
+-- Only the plain searches were recorded before, drop the faceted ones
+DELETE FROM search_stat WHERE filters <> '';
+
+ALTER TABLE search_stat DROP CONSTRAINT search_stat_pkey;
+ALTER TABLE search_stat ADD PRIMARY KEY (query, day);
+
+ALTER TABLE search_stat DROP COLUMN filters;
@@ -0,0 +1,6 @@
+-- The filters key of the faceted searches, empty for the plain ones,
+-- so the stats tell them apart while the reports sum them by query
+ALTER TABLE search_stat ADD COLUMN filters VARCHAR(256) NOT NULL DEFAULT '';
+
+ALTER TABLE search_stat DROP CONSTRAINT search_stat_pkey;
+ALTER TABLE search_stat ADD PRIMARY KEY (query, filters, day);
@@ -47,6 +47,13 @@ const loadItems = async (url, cursor) => {
             thumb.alt = item.title;
             thumb.srcset = item.srcset;
             template_clone.querySelector('.video-title').innerHTML = item.title;
+            const snippet = template_clone.querySelector('.video-snippet');
+            if (item.snippet) {
+                // The snippet comes HTML escaped with the matched terms marked
+                snippet.innerHTML = item.snippet;
+            } else {
+                snippet.remove();
+            }
             const remove = template_clone.querySelector('.remove-option');
             if (remove) {
                 remove.setAttribute('data-id', `${item.id}`)
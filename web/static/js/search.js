@@ -0,0 +1,31 @@
+// ==========================================================================
+// Search Analytics - record the clicks on the search results
+// ==========================================================================
+
+const searchParams = new URLSearchParams(window.location.search);
+const searchQuery = searchParams.get('q');
+
+document.getElementById('scroller').addEventListener('click', event => {
+    const link = event.target.closest('.video-link');
+    if (!searchQuery || !link) return;
+
+    // Extract the video ID from the post link
+    const match = link.getAttribute('href').match(/\/video\/([-a-zA-Z0-9_]{11})\//);
+    if (!match) return;
+
+    const headers = new Headers();
+    headers.append("Content-Type", "application/json");
+
+    // If CSRF Token send with the POST request
+    const csrfToken = document.getElementsByName("gorilla.csrf.Token");
+    if (csrfToken.length) { headers.append("X-CSRF-Token", csrfToken[0].value); }
+
+    // Keep the request alive while the browser navigates to the post
+    fetch('/api/search/click/', {
+        method: 'POST',
+        headers: headers,
+        // The server parses the filters out of the search params
+        body: JSON.stringify({ query: searchQuery, filters: searchParams.toString(), video_id: match[1] }),
+        keepalive: true,
+    }).catch(error => console.error("Failed to record the click:", error));
+});
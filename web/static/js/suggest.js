@@ -0,0 +1,118 @@
+// ==========================================================================
+// Search Suggestions - typeahead on the search form
+// ==========================================================================
+
+const suggestForm = document.getElementById('searchForm');
+const suggestInput = document.getElementById('q');
+
+const SUGGEST_DELAY = 200;      // debounce delay in milliseconds
+const SUGGEST_MIN_LENGTH = 2;   // characters typed before suggesting
+
+const suggestList = document.createElement('ul');
+suggestList.className = 'search-suggestions';
+suggestList.setAttribute('role', 'listbox');
+suggestList.id = 'searchSuggestions';
+suggestForm.appendChild(suggestList);
+
+suggestInput.setAttribute('autocomplete', 'off');
+suggestInput.setAttribute('aria-autocomplete', 'list');
+suggestInput.setAttribute('aria-controls', suggestList.id);
+
+let suggestTimer = null;
+let suggestController = null;
+let suggestIndex = -1;
+
+// Hide and empty the suggestions list
+const clearSuggestions = () => {
+    suggestList.replaceChildren();
+    suggestList.classList.remove('show-suggestions');
+    suggestIndex = -1;
+};
+
+// Render the suggestions as links, grouped by their kind
+const renderSuggestions = (suggestions) => {
+    clearSuggestions();
+    for (const suggestion of suggestions) {
+        const item = document.createElement('li');
+        item.setAttribute('role', 'option');
+        const link = document.createElement('a');
+        link.className = `suggestion-item suggestion-${suggestion.kind}`;
+        link.href = suggestion.url;
+        link.textContent = suggestion.label;
+        item.appendChild(link);
+        suggestList.appendChild(item);
+    }
+    if (suggestions.length) {
+        suggestList.classList.add('show-suggestions');
+    }
+};
+
+// Fetch the suggestions, cancel the previous request if still pending
+const fetchSuggestions = async (query) => {
+    if (suggestController) suggestController.abort();
+    suggestController = new AbortController();
+
+    try {
+        const url = new URL('/api/search/suggest/', window.location.origin);
+        url.searchParams.set('q', query);
+        const response = await fetch(url, { signal: suggestController.signal });
+        if (!response.ok) {
+            throw new Error(`HTTP error! status: ${response.status}`);
+        }
+        renderSuggestions(await response.json());
+    } catch (error) {
+        if (error.name !== 'AbortError') {
+            console.error('Failed to fetch suggestions:', error);
+        }
+    }
+};
+
+// Highlight the suggestion at index
+const highlightSuggestion = (index) => {
+    const links = suggestList.querySelectorAll('.suggestion-item');
+    if (!links.length) return;
+    suggestIndex = (index + links.length) % links.length;
+    links.forEach((link, i) => link.classList.toggle('active', i === suggestIndex));
+};
+
+suggestInput.addEventListener('input', () => {
+    clearTimeout(suggestTimer);
+    const query = suggestInput.value.trim();
+    if (query.length < SUGGEST_MIN_LENGTH) {
+        if (suggestController) suggestController.abort();
+        clearSuggestions();
+        return;
+    }
+    suggestTimer = setTimeout(() => fetchSuggestions(query), SUGGEST_DELAY);
+});
+
+suggestInput.addEventListener('keydown', event => {
+    switch (event.key) {
+        case 'ArrowDown':
+            event.preventDefault();
+            highlightSuggestion(suggestIndex + 1);
+            break;
+        case 'ArrowUp':
+            event.preventDefault();
+            highlightSuggestion(suggestIndex - 1);
+            break;
+        case 'Enter': {
+            const active = suggestList.querySelector('.suggestion-item.active');
+            if (active) {
+                event.preventDefault();
+                window.location.href = active.href;
+            }
+            break;
+        }
+        case 'Escape':
+            clearSuggestions();
+            break;
+    }
+});
+
+// Close the suggestions when clicked outside of the search form
+document.addEventListener('click', event => {
+    if (!event.target.closest('#searchForm')) {
+        clearSuggestions();
+    }
+});